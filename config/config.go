@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -15,6 +17,21 @@ type Config struct {
 	Database struct {
 		URL string `mapstructure:"url"`
 	} `mapstructure:"database"`
+	Search struct {
+		SimilarityThreshold float64 `mapstructure:"similarity_threshold"`
+	} `mapstructure:"search"`
+	Sync struct {
+		RetentionDays int `mapstructure:"retention_days"`
+	} `mapstructure:"sync"`
+	Events struct {
+		Broker string `mapstructure:"broker"`
+	} `mapstructure:"events"`
+	Audit struct {
+		Admins []string `mapstructure:"admins"`
+	} `mapstructure:"audit"`
+	Idempotency struct {
+		TTLHours int `mapstructure:"ttl_hours"`
+	} `mapstructure:"idempotency"`
 	Frontend struct {
 		Dev  string
 		Dev2 string
@@ -45,6 +62,25 @@ func LoadConfig() (*Config, error) {
 		cfg.Frontend.Dev = os.Getenv("FRONTEND_URL_DEV")
 		cfg.Frontend.Dev2 = os.Getenv("FRONTEND_URL_DEV2")
 		cfg.Frontend.Prod = os.Getenv("FRONTEND_URL_PROD")
+		cfg.Search.SimilarityThreshold, _ = strconv.ParseFloat(os.Getenv("SEARCH_SIMILARITY_THRESHOLD"), 64)
+		cfg.Sync.RetentionDays, _ = strconv.Atoi(os.Getenv("SYNC_RETENTION_DAYS"))
+		cfg.Events.Broker = getenv("EVENTS_BROKER", "memory")
+		cfg.Idempotency.TTLHours, _ = strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS"))
+		if admins := os.Getenv("AUDIT_ADMINS"); admins != "" {
+			cfg.Audit.Admins = strings.Split(admins, ",")
+		}
+	}
+
+	if cfg.Search.SimilarityThreshold <= 0 || cfg.Search.SimilarityThreshold > 1 {
+		cfg.Search.SimilarityThreshold = 0.3
+	}
+
+	if cfg.Sync.RetentionDays <= 0 {
+		cfg.Sync.RetentionDays = 30
+	}
+
+	if cfg.Idempotency.TTLHours <= 0 {
+		cfg.Idempotency.TTLHours = 24
 	}
 
 	if cfg.Database.URL == "" {
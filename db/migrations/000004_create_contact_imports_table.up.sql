@@ -0,0 +1,23 @@
+CREATE TABLE "contact_imports" (
+    "id" VARCHAR(36) PRIMARY KEY,
+    "username" VARCHAR(255) NOT NULL,
+    "file_name" VARCHAR(255),
+    "delimiter" VARCHAR(1) NOT NULL,
+    "encoding" VARCHAR(20) NOT NULL,
+    "headers" JSONB,
+    "mapping" JSONB,
+    "content" TEXT NOT NULL,
+    "status" VARCHAR(20) NOT NULL,
+    "total_rows" INT NOT NULL DEFAULT 0,
+    "processed_rows" INT NOT NULL DEFAULT 0,
+    "imported_rows" INT NOT NULL DEFAULT 0,
+    "failed_rows" INT NOT NULL DEFAULT 0,
+    "errors" JSONB,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_contact_imports_user
+        FOREIGN KEY("username")
+            REFERENCES "users"("username")
+);
+
+CREATE INDEX idx_contact_imports_username ON "contact_imports"("username");
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS addresses_search_vector_trigger ON "addresses";
+DROP TRIGGER IF EXISTS contacts_search_vector_trigger ON "contacts";
+DROP FUNCTION IF EXISTS addresses_search_vector_refresh();
+DROP FUNCTION IF EXISTS contacts_search_vector_refresh();
+DROP FUNCTION IF EXISTS contacts_address_text(INT);
+DROP FUNCTION IF EXISTS contacts_build_search_vector(TEXT, TEXT, TEXT, TEXT, TEXT);
+DROP INDEX IF EXISTS idx_contacts_search_vector;
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "search_vector";
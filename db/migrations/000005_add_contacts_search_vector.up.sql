@@ -0,0 +1,57 @@
+-- The search vector also covers the contact's addresses, which a generated
+-- column cannot reference, so it is maintained by triggers instead.
+ALTER TABLE "contacts" ADD COLUMN "search_vector" TSVECTOR;
+
+CREATE OR REPLACE FUNCTION contacts_build_search_vector(
+    first_name TEXT, last_name TEXT, email TEXT, phone TEXT, addresses TEXT
+) RETURNS TSVECTOR AS $$
+    SELECT
+        setweight(to_tsvector('simple', concat_ws(' ', first_name, last_name)), 'A') ||
+        setweight(to_tsvector('simple', concat_ws(' ', email, regexp_replace(coalesce(email, ''), '[@._+-]+', ' ', 'g'))), 'B') ||
+        setweight(to_tsvector('simple', concat_ws(' ', phone, regexp_replace(coalesce(phone, ''), '\D', '', 'g'))), 'B') ||
+        setweight(to_tsvector('simple', coalesce(addresses, '')), 'C')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION contacts_address_text(contact INT) RETURNS TEXT AS $$
+    SELECT string_agg(concat_ws(' ', street, city, province, country, postal_code), ' ')
+    FROM "addresses"
+    WHERE contact_id = contact
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION contacts_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := contacts_build_search_vector(
+        NEW.first_name, NEW.last_name, NEW.email, NEW.phone, contacts_address_text(NEW.id)
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER contacts_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF first_name, last_name, email, phone ON "contacts"
+    FOR EACH ROW EXECUTE FUNCTION contacts_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION addresses_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE "contacts" c
+    SET search_vector = contacts_build_search_vector(
+        c.first_name, c.last_name, c.email, c.phone, contacts_address_text(c.id)
+    )
+    WHERE c.id IN (
+        CASE WHEN TG_OP <> 'INSERT' THEN OLD.contact_id END,
+        CASE WHEN TG_OP <> 'DELETE' THEN NEW.contact_id END
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER addresses_search_vector_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON "addresses"
+    FOR EACH ROW EXECUTE FUNCTION addresses_search_vector_refresh();
+
+UPDATE "contacts" c
+SET search_vector = contacts_build_search_vector(
+    c.first_name, c.last_name, c.email, c.phone, contacts_address_text(c.id)
+);
+
+CREATE INDEX idx_contacts_search_vector ON "contacts" USING GIN("search_vector");
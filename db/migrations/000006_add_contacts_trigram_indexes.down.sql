@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_contacts_email_trgm;
+DROP INDEX IF EXISTS idx_contacts_last_name_trgm;
+DROP INDEX IF EXISTS idx_contacts_first_name_trgm;
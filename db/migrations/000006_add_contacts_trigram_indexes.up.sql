@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_contacts_first_name_trgm ON "contacts" USING GIN("first_name" gin_trgm_ops);
+CREATE INDEX idx_contacts_last_name_trgm ON "contacts" USING GIN("last_name" gin_trgm_ops);
+CREATE INDEX idx_contacts_email_trgm ON "contacts" USING GIN("email" gin_trgm_ops);
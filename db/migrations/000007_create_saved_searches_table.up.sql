@@ -0,0 +1,13 @@
+CREATE TABLE "saved_searches" (
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR(100) NOT NULL,
+    "query" JSONB NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_saved_searches_user
+        FOREIGN KEY("username")
+            REFERENCES "users"("username")
+);
+
+CREATE UNIQUE INDEX idx_saved_searches_username_name ON "saved_searches"("username", lower("name"));
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_contacts_username_phone_e164;
+
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "phone_e164";
+ALTER TABLE "contacts" ALTER COLUMN "phone" TYPE VARCHAR(20);
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "default_region";
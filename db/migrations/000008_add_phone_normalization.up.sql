@@ -0,0 +1,12 @@
+ALTER TABLE "users" ADD COLUMN "default_region" VARCHAR(2);
+
+ALTER TABLE "contacts" ALTER COLUMN "phone" TYPE VARCHAR(32);
+ALTER TABLE "contacts" ADD COLUMN "phone_e164" VARCHAR(16);
+
+-- Numbers already written in international notation can be normalised in
+-- place; the rest are normalised the next time the contact is saved.
+UPDATE "contacts"
+SET "phone_e164" = '+' || regexp_replace("phone", '[^0-9]', '', 'g')
+WHERE "phone" ~ '^\s*\+' AND length(regexp_replace("phone", '[^0-9]', '', 'g')) BETWEEN 8 AND 15;
+
+CREATE INDEX idx_contacts_username_phone_e164 ON "contacts"("username", "phone_e164");
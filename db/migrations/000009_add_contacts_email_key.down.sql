@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_contacts_username_email_key;
+DROP TRIGGER IF EXISTS contacts_email_key_trigger ON "contacts";
+DROP FUNCTION IF EXISTS contacts_email_key_refresh();
+DROP FUNCTION IF EXISTS contacts_email_key(TEXT, BOOLEAN);
+
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "email_key";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "canonical_gmail_emails";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "unique_contact_emails";
@@ -0,0 +1,53 @@
+ALTER TABLE "users" ADD COLUMN "unique_contact_emails" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE "users" ADD COLUMN "canonical_gmail_emails" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- email_key holds the case-folded email of contacts whose owner enabled
+-- unique emails and is NULL otherwise, so the partial unique index below
+-- only applies to those users.
+ALTER TABLE "contacts" ADD COLUMN "email_key" VARCHAR(100);
+
+CREATE OR REPLACE FUNCTION contacts_email_key(email TEXT, canonical_gmail BOOLEAN) RETURNS TEXT AS $$
+DECLARE
+    normalized TEXT := lower(btrim(email));
+    local_part TEXT;
+    domain_part TEXT;
+BEGIN
+    IF normalized IS NULL OR normalized = '' THEN
+        RETURN NULL;
+    END IF;
+
+    IF canonical_gmail AND position('@' IN normalized) > 0 THEN
+        local_part := split_part(normalized, '@', 1);
+        domain_part := substring(normalized FROM position('@' IN normalized) + 1);
+        IF domain_part IN ('gmail.com', 'googlemail.com') THEN
+            RETURN replace(split_part(local_part, '+', 1), '.', '') || '@gmail.com';
+        END IF;
+    END IF;
+
+    RETURN normalized;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION contacts_email_key_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    owner RECORD;
+BEGIN
+    SELECT unique_contact_emails, canonical_gmail_emails INTO owner
+    FROM "users"
+    WHERE username = NEW.username;
+
+    IF owner.unique_contact_emails THEN
+        NEW.email_key := contacts_email_key(NEW.email, owner.canonical_gmail_emails);
+    ELSE
+        NEW.email_key := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER contacts_email_key_trigger
+    BEFORE INSERT OR UPDATE OF email, username ON "contacts"
+    FOR EACH ROW EXECUTE FUNCTION contacts_email_key_refresh();
+
+CREATE UNIQUE INDEX idx_contacts_username_email_key ON "contacts"("username", "email_key")
+    WHERE "email_key" IS NOT NULL;
@@ -0,0 +1,28 @@
+CREATE TABLE "contact_shares" (
+    "id" SERIAL PRIMARY KEY,
+    "owner_username" VARCHAR(255) NOT NULL,
+    "grantee_username" VARCHAR(255) NOT NULL,
+    "contact_id" INT,
+    "permission" VARCHAR(10) NOT NULL CHECK ("permission" IN ('read', 'write')),
+    "status" VARCHAR(10) NOT NULL CHECK ("status" IN ('pending', 'accepted')),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "accepted_at" TIMESTAMPTZ,
+    CONSTRAINT fk_contact_shares_owner
+        FOREIGN KEY("owner_username")
+            REFERENCES "users"("username"),
+    CONSTRAINT fk_contact_shares_grantee
+        FOREIGN KEY("grantee_username")
+            REFERENCES "users"("username"),
+    CONSTRAINT fk_contact_shares_contact
+        FOREIGN KEY("contact_id")
+            REFERENCES "contacts"("id")
+            ON DELETE CASCADE
+);
+
+-- A NULL contact_id shares the whole address book, so it is folded to 0 to
+-- keep a single address book share per owner and grantee.
+CREATE UNIQUE INDEX idx_contact_shares_owner_grantee_contact
+    ON "contact_shares"("owner_username", "grantee_username", coalesce("contact_id", 0));
+
+CREATE INDEX idx_contact_shares_grantee_owner ON "contact_shares"("grantee_username", "owner_username")
+    WHERE "status" = 'accepted';
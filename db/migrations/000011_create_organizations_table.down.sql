@@ -0,0 +1,23 @@
+CREATE OR REPLACE FUNCTION contacts_email_key_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    owner RECORD;
+BEGIN
+    SELECT unique_contact_emails, canonical_gmail_emails INTO owner
+    FROM "users"
+    WHERE username = NEW.username;
+
+    IF owner.unique_contact_emails THEN
+        NEW.email_key := contacts_email_key(NEW.email, owner.canonical_gmail_emails);
+    ELSE
+        NEW.email_key := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_contacts_organization_id;
+ALTER TABLE "contacts" DROP CONSTRAINT IF EXISTS fk_contacts_organization;
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "organization_id";
+
+DROP TABLE IF EXISTS "organization_members";
+DROP TABLE IF EXISTS "organizations";
@@ -0,0 +1,57 @@
+CREATE TABLE "organizations" (
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR(100) NOT NULL,
+    "slug" VARCHAR(50) NOT NULL,
+    "created_by" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_organizations_user
+        FOREIGN KEY("created_by")
+            REFERENCES "users"("username")
+);
+
+CREATE UNIQUE INDEX idx_organizations_slug ON "organizations"("slug");
+
+CREATE TABLE "organization_members" (
+    "organization_id" INT NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "role" VARCHAR(10) NOT NULL CHECK ("role" IN ('owner', 'admin', 'member', 'viewer')),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY ("organization_id", "username"),
+    CONSTRAINT fk_organization_members_organization
+        FOREIGN KEY("organization_id")
+            REFERENCES "organizations"("id")
+            ON DELETE CASCADE,
+    CONSTRAINT fk_organization_members_user
+        FOREIGN KEY("username")
+            REFERENCES "users"("username")
+);
+
+CREATE INDEX idx_organization_members_username ON "organization_members"("username");
+
+-- Contacts with an organization belong to it rather than to username, which
+-- only records who created them.
+ALTER TABLE "contacts" ADD COLUMN "organization_id" INT;
+ALTER TABLE "contacts" ADD CONSTRAINT fk_contacts_organization
+    FOREIGN KEY("organization_id")
+        REFERENCES "organizations"("id")
+        ON DELETE CASCADE;
+
+CREATE INDEX idx_contacts_organization_id ON "contacts"("organization_id");
+
+-- Per-user unique emails only apply to personal address books.
+CREATE OR REPLACE FUNCTION contacts_email_key_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    owner RECORD;
+BEGIN
+    SELECT unique_contact_emails, canonical_gmail_emails INTO owner
+    FROM "users"
+    WHERE username = NEW.username;
+
+    IF owner.unique_contact_emails AND NEW.organization_id IS NULL THEN
+        NEW.email_key := contacts_email_key(NEW.email, owner.canonical_gmail_emails);
+    ELSE
+        NEW.email_key := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_contacts_company_id;
+ALTER TABLE "contacts" DROP CONSTRAINT IF EXISTS fk_contacts_company;
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "department";
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "job_title";
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "company_id";
+
+DROP TABLE IF EXISTS "company_addresses";
+DROP TABLE IF EXISTS "companies";
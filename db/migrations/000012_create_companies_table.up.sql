@@ -0,0 +1,46 @@
+CREATE TABLE "companies" (
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR(100) NOT NULL,
+    "domain" VARCHAR(255),
+    "industry" VARCHAR(100),
+    "website" VARCHAR(255),
+    "username" VARCHAR(255) NOT NULL,
+    "organization_id" INT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_companies_user
+        FOREIGN KEY("username")
+            REFERENCES "users"("username"),
+    CONSTRAINT fk_companies_organization
+        FOREIGN KEY("organization_id")
+            REFERENCES "organizations"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_companies_username ON "companies"("username");
+CREATE INDEX idx_companies_organization_id ON "companies"("organization_id");
+
+CREATE TABLE "company_addresses" (
+    "id" SERIAL PRIMARY KEY,
+    "street" VARCHAR(255),
+    "city" VARCHAR(100),
+    "province" VARCHAR(100),
+    "country" VARCHAR(100) NOT NULL,
+    "postal_code" VARCHAR(10) NOT NULL,
+    "company_id" INT NOT NULL,
+    CONSTRAINT fk_company_addresses_company
+        FOREIGN KEY("company_id")
+            REFERENCES "companies"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_company_addresses_company_id ON "company_addresses"("company_id");
+
+ALTER TABLE "contacts" ADD COLUMN "company_id" INT;
+ALTER TABLE "contacts" ADD COLUMN "job_title" VARCHAR(100);
+ALTER TABLE "contacts" ADD COLUMN "department" VARCHAR(100);
+ALTER TABLE "contacts" ADD CONSTRAINT fk_contacts_company
+    FOREIGN KEY("company_id")
+        REFERENCES "companies"("id")
+        ON DELETE SET NULL;
+
+CREATE INDEX idx_contacts_company_id ON "contacts"("company_id");
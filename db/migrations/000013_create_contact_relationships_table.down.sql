@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "contact_relationships";
\ No newline at end of file
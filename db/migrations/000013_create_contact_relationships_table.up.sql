@@ -0,0 +1,20 @@
+CREATE TABLE "contact_relationships" (
+    "id" SERIAL PRIMARY KEY,
+    "contact_id" INT NOT NULL,
+    "related_contact_id" INT NOT NULL,
+    "type" VARCHAR(50) NOT NULL,
+    "reciprocal_type" VARCHAR(50),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_contact_relationships_contact
+        FOREIGN KEY("contact_id")
+            REFERENCES "contacts"("id")
+            ON DELETE CASCADE,
+    CONSTRAINT fk_contact_relationships_related_contact
+        FOREIGN KEY("related_contact_id")
+            REFERENCES "contacts"("id")
+            ON DELETE CASCADE,
+    CONSTRAINT chk_contact_relationships_not_self CHECK ("contact_id" <> "related_contact_id")
+);
+
+CREATE UNIQUE INDEX idx_contact_relationships_edge ON "contact_relationships"("contact_id", "related_contact_id", "type");
+CREATE INDEX idx_contact_relationships_related_contact_id ON "contact_relationships"("related_contact_id");
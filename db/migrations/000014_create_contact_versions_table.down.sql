@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "contact_versions";
\ No newline at end of file
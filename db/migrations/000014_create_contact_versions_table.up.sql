@@ -0,0 +1,20 @@
+-- contact_versions has no foreign key to contacts on purpose: the history of
+-- a deleted contact is kept so it can be restored.
+CREATE TABLE "contact_versions" (
+    "id" SERIAL PRIMARY KEY,
+    "contact_id" INT NOT NULL,
+    "version" INT NOT NULL,
+    "action" VARCHAR(20) NOT NULL,
+    "snapshot" JSONB,
+    "changes" JSONB NOT NULL,
+    "changed_by" VARCHAR(255) NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "organization_id" INT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_contact_versions_organization
+        FOREIGN KEY("organization_id")
+            REFERENCES "organizations"("id")
+            ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_contact_versions_contact_version ON "contact_versions"("contact_id", "version");
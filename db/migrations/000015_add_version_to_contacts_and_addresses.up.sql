@@ -0,0 +1,2 @@
+ALTER TABLE "contacts" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
+ALTER TABLE "addresses" ADD COLUMN "version" INT NOT NULL DEFAULT 1;
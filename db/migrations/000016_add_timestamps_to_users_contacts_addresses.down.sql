@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_contacts_updated_at;
+DROP INDEX IF EXISTS idx_contacts_created_at;
+
+ALTER TABLE "addresses" DROP COLUMN IF EXISTS "updated_by";
+ALTER TABLE "addresses" DROP COLUMN IF EXISTS "created_by";
+ALTER TABLE "addresses" DROP COLUMN IF EXISTS "updated_at";
+ALTER TABLE "addresses" DROP COLUMN IF EXISTS "created_at";
+
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "updated_by";
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "created_by";
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "updated_at";
+ALTER TABLE "contacts" DROP COLUMN IF EXISTS "created_at";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "updated_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "created_at";
@@ -0,0 +1,31 @@
+ALTER TABLE "users" ADD COLUMN "created_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE "users" ADD COLUMN "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE "contacts" ADD COLUMN "created_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE "contacts" ADD COLUMN "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE "contacts" ADD COLUMN "created_by" VARCHAR(255);
+ALTER TABLE "contacts" ADD COLUMN "updated_by" VARCHAR(255);
+
+ALTER TABLE "addresses" ADD COLUMN "created_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE "addresses" ADD COLUMN "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE "addresses" ADD COLUMN "created_by" VARCHAR(255);
+ALTER TABLE "addresses" ADD COLUMN "updated_by" VARCHAR(255);
+
+-- Existing contacts take their timestamps and last editor from their
+-- history where there is one, and are otherwise attributed to their owner.
+UPDATE "contacts" c SET
+    "created_at" = coalesce((SELECT min(v."created_at") FROM "contact_versions" v WHERE v."contact_id" = c."id"), c."created_at"),
+    "updated_at" = coalesce((SELECT max(v."created_at") FROM "contact_versions" v WHERE v."contact_id" = c."id"), c."updated_at"),
+    "created_by" = c."username",
+    "updated_by" = coalesce((SELECT v."changed_by" FROM "contact_versions" v WHERE v."contact_id" = c."id" ORDER BY v."version" DESC LIMIT 1), c."username");
+
+UPDATE "addresses" a SET
+    "created_at" = c."created_at",
+    "updated_at" = c."updated_at",
+    "created_by" = c."created_by",
+    "updated_by" = c."updated_by"
+FROM "contacts" c
+WHERE c."id" = a."contact_id";
+
+CREATE INDEX idx_contacts_created_at ON "contacts"("created_at");
+CREATE INDEX idx_contacts_updated_at ON "contacts"("updated_at");
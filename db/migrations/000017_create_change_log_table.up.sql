@@ -0,0 +1,33 @@
+-- change_log has no foreign key to contacts on purpose: deletions are kept
+-- as tombstones for the sync API after the contact is gone.
+CREATE TABLE "change_log" (
+    "seq" BIGSERIAL PRIMARY KEY,
+    "entity" VARCHAR(16) NOT NULL,
+    "entity_id" INT NOT NULL,
+    "contact_id" INT NOT NULL,
+    "action" VARCHAR(16) NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "organization_id" INT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_change_log_organization
+        FOREIGN KEY("organization_id")
+            REFERENCES "organizations"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_change_log_entity ON "change_log"("entity", "entity_id");
+CREATE INDEX idx_change_log_contact_id ON "change_log"("contact_id");
+CREATE INDEX idx_change_log_username ON "change_log"("username");
+CREATE INDEX idx_change_log_organization_id ON "change_log"("organization_id");
+
+-- Existing contacts and addresses are logged once, so that a full sync
+-- returns them.
+INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id")
+SELECT 'contact', c."id", c."id", 'upsert', c."username", c."organization_id"
+FROM "contacts" c
+ORDER BY c."id";
+
+INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id")
+SELECT 'address', a."id", c."id", 'upsert', c."username", c."organization_id"
+FROM "addresses" a JOIN "contacts" c ON c."id" = a."contact_id"
+ORDER BY a."id";
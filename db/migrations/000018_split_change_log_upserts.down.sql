@@ -0,0 +1 @@
+UPDATE "change_log" SET "action" = 'upsert' WHERE "action" IN ('create', 'update');
@@ -0,0 +1,3 @@
+-- Writes are logged as creations and updates instead of upserts, so that
+-- the event stream can tell them apart. Earlier entries become updates.
+UPDATE "change_log" SET "action" = 'update' WHERE "action" = 'upsert';
@@ -0,0 +1,62 @@
+CREATE TABLE "webhooks" (
+    "id" SERIAL PRIMARY KEY,
+    "url" VARCHAR(2048) NOT NULL,
+    "event_types" JSONB NOT NULL,
+    "secret" VARCHAR(100) NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+    "disabled_reason" VARCHAR(255),
+    "failure_count" INT NOT NULL DEFAULT 0,
+    "cursor" BIGINT NOT NULL DEFAULT 0,
+    "username" VARCHAR(255) NOT NULL,
+    "organization_id" INT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_webhooks_user
+        FOREIGN KEY("username")
+            REFERENCES "users"("username"),
+    CONSTRAINT fk_webhooks_organization
+        FOREIGN KEY("organization_id")
+            REFERENCES "organizations"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhooks_username ON "webhooks"("username");
+CREATE INDEX idx_webhooks_organization_id ON "webhooks"("organization_id");
+
+CREATE TABLE "webhook_deliveries" (
+    "id" SERIAL PRIMARY KEY,
+    "webhook_id" INT NOT NULL,
+    "event_id" BIGINT NOT NULL,
+    "event_type" VARCHAR(32) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(16) NOT NULL,
+    "attempts" INT NOT NULL DEFAULT 0,
+    "next_attempt_at" TIMESTAMPTZ NOT NULL,
+    "last_attempt_at" TIMESTAMPTZ,
+    "redelivery_of" INT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_webhook_deliveries_webhook
+        FOREIGN KEY("webhook_id")
+            REFERENCES "webhooks"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON "webhook_deliveries"("webhook_id");
+CREATE INDEX idx_webhook_deliveries_next_attempt_at ON "webhook_deliveries"("next_attempt_at");
+
+CREATE TABLE "webhook_attempts" (
+    "id" SERIAL PRIMARY KEY,
+    "delivery_id" INT NOT NULL,
+    "attempt" INT NOT NULL,
+    "status_code" INT,
+    "error" VARCHAR(1000),
+    "response_body" TEXT,
+    "duration_ms" INT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT fk_webhook_attempts_delivery
+        FOREIGN KEY("delivery_id")
+            REFERENCES "webhook_deliveries"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_attempts_delivery_id ON "webhook_attempts"("delivery_id");
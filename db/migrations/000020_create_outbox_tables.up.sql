@@ -0,0 +1,19 @@
+-- outbox_events has no foreign keys on purpose: events outlive the
+-- entities they are about.
+CREATE TABLE "outbox_events" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "type" VARCHAR(64) NOT NULL,
+    "aggregate_id" VARCHAR(255) NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "organization_id" INT,
+    "payload" JSONB NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_outbox_events_created_at ON "outbox_events"("created_at");
+
+CREATE TABLE "outbox_subscriptions" (
+    "name" VARCHAR(100) PRIMARY KEY,
+    "cursor" BIGINT NOT NULL DEFAULT 0,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
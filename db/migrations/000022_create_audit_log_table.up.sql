@@ -0,0 +1,40 @@
+-- audit_log has no foreign keys on purpose: entries outlive the users and
+-- entities they are about.
+CREATE TABLE "audit_log" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "event_id" BIGINT NOT NULL,
+    "action" VARCHAR(64) NOT NULL,
+    "actor" VARCHAR(255) NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "organization_id" INT,
+    "entity_type" VARCHAR(32) NOT NULL,
+    "entity_id" VARCHAR(255) NOT NULL,
+    "ip" VARCHAR(64) NOT NULL,
+    "user_agent" VARCHAR(512) NOT NULL,
+    "request_id" VARCHAR(128) NOT NULL,
+    "changes" JSONB NOT NULL,
+    "snapshot" JSONB,
+    "prev_hash" VARCHAR(64) NOT NULL,
+    "hash" VARCHAR(64) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_audit_log_event_id ON "audit_log"("event_id");
+CREATE INDEX idx_audit_log_action ON "audit_log"("action");
+CREATE INDEX idx_audit_log_actor ON "audit_log"("actor");
+CREATE INDEX idx_audit_log_username ON "audit_log"("username");
+CREATE INDEX idx_audit_log_entity ON "audit_log"("entity_type", "entity_id");
+CREATE INDEX idx_audit_log_request_id ON "audit_log"("request_id");
+CREATE INDEX idx_audit_log_created_at ON "audit_log"("created_at");
+
+-- The log is append-only. The hash chain makes changes made around this
+-- trigger detectable.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only_trigger
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON "audit_log"
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,18 @@
+CREATE TABLE "idempotency_keys" (
+    "username" VARCHAR(255) NOT NULL,
+    "idempotency_key" VARCHAR(255) NOT NULL,
+    "fingerprint" VARCHAR(64) NOT NULL,
+    "status" VARCHAR(16) NOT NULL,
+    "response_status" INT,
+    "response_headers" JSONB,
+    "response_body" BYTEA,
+    "created_at" TIMESTAMPTZ NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY ("username", "idempotency_key"),
+    CONSTRAINT fk_idempotency_keys_user
+        FOREIGN KEY("username")
+            REFERENCES "users"("username")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON "idempotency_keys"("expires_at");
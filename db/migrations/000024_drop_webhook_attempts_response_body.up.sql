@@ -0,0 +1 @@
+ALTER TABLE "webhook_attempts" DROP COLUMN IF EXISTS "response_body";
@@ -0,0 +1 @@
+ALTER TABLE "contact_imports" DROP COLUMN IF EXISTS "failure_reason";
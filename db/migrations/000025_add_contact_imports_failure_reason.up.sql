@@ -0,0 +1 @@
+ALTER TABLE "contact_imports" ADD COLUMN "failure_reason" VARCHAR(1000);
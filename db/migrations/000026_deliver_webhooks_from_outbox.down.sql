@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+
+ALTER TABLE "webhooks" ADD COLUMN IF NOT EXISTS "cursor" BIGINT NOT NULL DEFAULT 0;
+UPDATE "webhooks" SET "cursor" = (SELECT COALESCE(MAX("seq"), 0) FROM "change_log");
@@ -0,0 +1,8 @@
+ALTER TABLE "webhooks" DROP COLUMN IF EXISTS "cursor";
+
+-- Deliveries now refer to outbox events rather than change log entries.
+-- Numbering new events past the old ids keeps the two apart.
+SELECT setval(pg_get_serial_sequence('outbox_events', 'id'),
+    GREATEST((SELECT MAX("id") FROM "outbox_events"), (SELECT MAX("event_id") FROM "webhook_deliveries"), 1));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON "webhook_deliveries"("webhook_id", "event_id") WHERE "redelivery_of" IS NULL;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "saved_search_members";
+
+DELETE FROM "contact_shares" WHERE "saved_search_id" IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_contact_shares_owner_grantee_contact;
+CREATE UNIQUE INDEX idx_contact_shares_owner_grantee_contact
+    ON "contact_shares"("owner_username", "grantee_username", coalesce("contact_id", 0));
+
+ALTER TABLE "contact_shares" DROP CONSTRAINT IF EXISTS chk_contact_shares_scope;
+ALTER TABLE "contact_shares" DROP COLUMN IF EXISTS "saved_search_id";
@@ -0,0 +1,32 @@
+ALTER TABLE "contact_shares" ADD COLUMN "saved_search_id" INT,
+    ADD CONSTRAINT fk_contact_shares_saved_search
+        FOREIGN KEY("saved_search_id")
+            REFERENCES "saved_searches"("id")
+            ON DELETE CASCADE;
+
+-- A share names at most one of a contact and a saved search; with neither
+-- it shares the whole address book.
+ALTER TABLE "contact_shares" ADD CONSTRAINT chk_contact_shares_scope
+    CHECK ("contact_id" IS NULL OR "saved_search_id" IS NULL);
+
+DROP INDEX IF EXISTS idx_contact_shares_owner_grantee_contact;
+CREATE UNIQUE INDEX idx_contact_shares_owner_grantee_contact
+    ON "contact_shares"("owner_username", "grantee_username", coalesce("contact_id", 0), coalesce("saved_search_id", 0));
+
+-- saved_search_members holds the contacts currently matching a shared saved
+-- search, so that share grants can be checked in SQL.
+CREATE TABLE IF NOT EXISTS "saved_search_members" (
+    "saved_search_id" INT NOT NULL,
+    "contact_id" INT NOT NULL,
+    PRIMARY KEY ("saved_search_id", "contact_id"),
+    CONSTRAINT fk_saved_search_members_saved_search
+        FOREIGN KEY("saved_search_id")
+            REFERENCES "saved_searches"("id")
+            ON DELETE CASCADE,
+    CONSTRAINT fk_saved_search_members_contact
+        FOREIGN KEY("contact_id")
+            REFERENCES "contacts"("id")
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_members_contact_id ON "saved_search_members"("contact_id");
@@ -0,0 +1,5 @@
+ALTER TABLE "idempotency_keys" ADD COLUMN "heartbeat_at" TIMESTAMPTZ;
+
+UPDATE "idempotency_keys" SET "heartbeat_at" = "created_at";
+
+ALTER TABLE "idempotency_keys" ALTER COLUMN "heartbeat_at" SET NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_change_log_position;
+ALTER TABLE "change_log" DROP COLUMN IF EXISTS "transaction_id";
+
+ALTER TABLE "outbox_subscriptions" DROP COLUMN IF EXISTS "transaction_id";
+
+DROP INDEX IF EXISTS idx_outbox_events_position;
+ALTER TABLE "outbox_events" DROP COLUMN IF EXISTS "transaction_id";
@@ -0,0 +1,11 @@
+-- Entries are read in the order of the transactions that wrote them
+-- instead of serialising writers. Existing entries, all final, come first.
+ALTER TABLE "outbox_events" ADD COLUMN "transaction_id" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "outbox_events" ALTER COLUMN "transaction_id" SET DEFAULT (pg_current_xact_id())::text::bigint;
+CREATE INDEX idx_outbox_events_position ON "outbox_events"("transaction_id", "id");
+
+ALTER TABLE "outbox_subscriptions" ADD COLUMN "transaction_id" BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE "change_log" ADD COLUMN "transaction_id" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "change_log" ALTER COLUMN "transaction_id" SET DEFAULT (pg_current_xact_id())::text::bigint;
+CREATE INDEX idx_change_log_position ON "change_log"("transaction_id", "seq");
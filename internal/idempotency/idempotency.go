@@ -0,0 +1,173 @@
+// Package idempotency stores the responses to requests made with an
+// idempotency key, so that a client retrying a request gets the original
+// response instead of repeating its effect.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+)
+
+// lockTimeout is how long a key stays claimed by a request that has not
+// completed since its last heartbeat. Past it the request is assumed to
+// have died and the key is free again.
+const lockTimeout = time.Minute
+
+// heartbeatInterval is how often a running request refreshes its claim,
+// well within lockTimeout.
+const heartbeatInterval = lockTimeout / 4
+
+var (
+	ErrFingerprintMismatch = errors.New("idempotency key was already used with a different request")
+	ErrInProgress          = errors.New("a request with this idempotency key is still being processed")
+)
+
+// Record is a key used by a user. Fingerprint identifies the request the
+// key was first used with; the response is stored once it completed.
+type Record struct {
+	Username        string            `gorm:"column:username;primaryKey;size:255"`
+	Key             string            `gorm:"column:idempotency_key;primaryKey;size:255"`
+	Fingerprint     string            `gorm:"column:fingerprint;size:64;not null"`
+	Status          string            `gorm:"column:status;size:16;not null"`
+	ResponseStatus  int               `gorm:"column:response_status"`
+	ResponseHeaders map[string]string `gorm:"column:response_headers;type:jsonb;serializer:json"`
+	ResponseBody    []byte            `gorm:"column:response_body"`
+	CreatedAt       time.Time         `gorm:"column:created_at;not null"`
+	HeartbeatAt     time.Time         `gorm:"column:heartbeat_at;not null"`
+	ExpiresAt       time.Time         `gorm:"column:expires_at;not null;index"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+type Store struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewStore returns a store keeping keys for ttl after their first use.
+func NewStore(db *gorm.DB, ttl time.Duration) *Store {
+	return &Store{db: db, ttl: ttl}
+}
+
+// Begin claims a key for the request with the given fingerprint. It returns
+// the record of a completed request to replay, or nil when the caller now
+// holds the key and must Complete or Release it. A key used with another
+// request fails with ErrFingerprintMismatch, and one held by a request
+// still running with ErrInProgress.
+func (store *Store) Begin(ctx context.Context, username string, key string, fingerprint string) (*Record, error) {
+	now := time.Now()
+	claim := Record{
+		Username:    username,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      StatusProcessing,
+		CreatedAt:   now,
+		HeartbeatAt: now,
+		ExpiresAt:   now.Add(store.ttl),
+	}
+
+	var completed *Record
+	err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&claim)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		var existing Record
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Take(&existing, "username = ? AND idempotency_key = ?", username, key).Error; err != nil {
+			return err
+		}
+
+		expired := existing.ExpiresAt.Before(now)
+		abandoned := existing.Status == StatusProcessing && existing.HeartbeatAt.Before(now.Add(-lockTimeout))
+		if expired || abandoned {
+			return tx.Model(&Record{}).Where("username = ? AND idempotency_key = ?", username, key).
+				Select("fingerprint", "status", "response_status", "response_headers", "response_body", "created_at", "heartbeat_at", "expires_at").
+				Updates(&claim).Error
+		}
+
+		if existing.Fingerprint != fingerprint {
+			return ErrFingerprintMismatch
+		}
+		if existing.Status == StatusProcessing {
+			return ErrInProgress
+		}
+
+		completed = &existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return completed, nil
+}
+
+// Hold keeps the key claimed while the request holding it runs, by
+// refreshing its heartbeat in the background until the returned function
+// is called. A slow request is thus never taken for an abandoned one.
+func (store *Store) Hold(username string, key string) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = store.db.WithContext(ctx).Model(&Record{}).
+					Where("username = ? AND idempotency_key = ? AND status = ?", username, key, StatusProcessing).
+					Update("heartbeat_at", time.Now()).Error
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Complete stores the response of the request holding the key.
+func (store *Store) Complete(ctx context.Context, username string, key string, status int, headers map[string]string, body []byte) error {
+	return store.db.WithContext(ctx).Model(&Record{}).
+		Where("username = ? AND idempotency_key = ? AND status = ?", username, key, StatusProcessing).
+		Select("status", "response_status", "response_headers", "response_body").
+		Updates(&Record{
+			Status:          StatusCompleted,
+			ResponseStatus:  status,
+			ResponseHeaders: headers,
+			ResponseBody:    body,
+		}).Error
+}
+
+// Release frees a key whose request failed, so that it can be retried.
+func (store *Store) Release(ctx context.Context, username string, key string) error {
+	return store.db.WithContext(ctx).
+		Where("username = ? AND idempotency_key = ? AND status = ?", username, key, StatusProcessing).
+		Delete(&Record{}).Error
+}
+
+// Prune deletes the expired keys.
+func (store *Store) Prune(ctx context.Context) error {
+	return store.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&Record{}).Error
+}
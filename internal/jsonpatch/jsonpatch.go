@@ -0,0 +1,418 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported patch format, expected " + MediaTypeMergePatch + " or " + MediaTypeJSONPatch)
+	ErrInvalidPatch         = errors.New("invalid patch")
+	ErrTestFailed           = errors.New("patch test operation failed")
+)
+
+// Patch is a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902)
+// document, as selected by the request's content type.
+type Patch struct {
+	MediaType string
+	Body      []byte
+}
+
+// New returns the patch sent with contentType. A plain application/json
+// body is read as a merge patch.
+func New(contentType string, body []byte) (Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Patch{}, ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case MediaTypeMergePatch, "application/json":
+		return Patch{MediaType: MediaTypeMergePatch, Body: body}, nil
+	case MediaTypeJSONPatch:
+		return Patch{MediaType: MediaTypeJSONPatch, Body: body}, nil
+	default:
+		return Patch{}, ErrUnsupportedMediaType
+	}
+}
+
+// Apply returns doc with the patch applied.
+func (patch Patch) Apply(doc []byte) ([]byte, error) {
+	var target any
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if patch.MediaType == MediaTypeJSONPatch {
+		target, err = applyOperations(target, patch.Body)
+	} else {
+		target, err = applyMerge(target, patch.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(target)
+}
+
+// Unmarshal decodes a patched document into dst, rejecting members that dst
+// does not have.
+func Unmarshal(data []byte, dst any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+	return nil
+}
+
+func applyMerge(target any, body []byte) (any, error) {
+	var patch any
+	if err := decode(body, &patch); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+	return merge(target, patch), nil
+}
+
+// merge implements the MergePatch function of RFC 7396: objects are merged
+// member by member, null removes a member and anything else replaces it.
+func merge(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = merge(targetObject[name], value)
+	}
+	return targetObject
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func applyOperations(target any, body []byte) (any, error) {
+	var operations []operation
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+
+	for i, op := range operations {
+		var err error
+		target, err = op.apply(target)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return target, nil
+}
+
+func (op operation) apply(target any) (any, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidPatch)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		var value any
+		if err := decode(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+		}
+
+		switch op.Op {
+		case "add":
+			return add(target, path, value)
+		case "replace":
+			if _, err := get(target, path); err != nil {
+				return nil, err
+			}
+			if target, err = remove(target, path); err != nil {
+				return nil, err
+			}
+			return add(target, path, value)
+		default:
+			current, err := get(target, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, ErrTestFailed
+			}
+			return target, nil
+		}
+	case "remove":
+		return remove(target, path)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(target, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidPatch)
+			}
+			if target, err = remove(target, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+		return add(target, path, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(target any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := target.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path /%s does not exist", ErrInvalidPatch, token)
+			}
+			target = value
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			target = node[index]
+		default:
+			return nil, fmt.Errorf("%w: path /%s does not exist", ErrInvalidPatch, token)
+		}
+	}
+	return target, nil
+}
+
+func add(target any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(target, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return target, nil
+	case []any:
+		index := len(node)
+		if last != "-" {
+			if index, err = arrayIndex(last, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return replaceParent(target, path[:len(path)-1], node)
+	default:
+		return nil, fmt.Errorf("%w: cannot add to /%s", ErrInvalidPatch, strings.Join(path, "/"))
+	}
+}
+
+func remove(target any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	parent, err := get(target, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		if _, ok := node[last]; !ok {
+			return nil, fmt.Errorf("%w: path /%s does not exist", ErrInvalidPatch, strings.Join(path, "/"))
+		}
+		delete(node, last)
+		return target, nil
+	case []any:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node[:index], node[index+1:]...)
+		return replaceParent(target, path[:len(path)-1], node)
+	default:
+		return nil, fmt.Errorf("%w: path /%s does not exist", ErrInvalidPatch, strings.Join(path, "/"))
+	}
+}
+
+// replaceParent stores an array that was resized at path, since slices
+// cannot be grown or shrunk in place.
+func replaceParent(target any, path []string, array []any) (any, error) {
+	if len(path) == 0 {
+		return array, nil
+	}
+
+	parent, err := get(target, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = array
+	case []any:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = array
+	}
+	return target, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	return index, nil
+}
+
+func isPrefix(prefix []string, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopy(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(node))
+		for name, child := range node {
+			copied[name] = deepCopy(child)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(node))
+		for i, child := range node {
+			copied[i] = deepCopy(child)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// equal compares JSON values as RFC 6902 prescribes for test: numbers by
+// value, so that 1 and 1.0 are equal, objects regardless of member order
+// and arrays element by element.
+func equal(a any, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okX := new(big.Rat).SetString(a.String())
+		y, okY := new(big.Rat).SetString(b.String())
+		return okX && okY && x.Cmp(y) == 0
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, value := range a {
+			other, ok := b[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// decode unmarshals data keeping numbers exact, so that test operations
+// compare them without rounding.
+func decode(data []byte, dst *any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(dst)
+}
@@ -0,0 +1,140 @@
+package jsonpatch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+		wantErr     error
+	}{
+		{contentType: "application/merge-patch+json", want: MediaTypeMergePatch},
+		{contentType: "application/json; charset=utf-8", want: MediaTypeMergePatch},
+		{contentType: "application/json-patch+json", want: MediaTypeJSONPatch},
+		{contentType: "text/plain", wantErr: ErrUnsupportedMediaType},
+		{contentType: "", wantErr: ErrUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			patch, err := New(tt.contentType, []byte("{}"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+			if patch.MediaType != tt.want {
+				t.Errorf("New() media type = %q, want %q", patch.MediaType, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "null removes member", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "arrays are replaced", doc: `{"a":["b"]}`, patch: `{"a":["c","d"]}`, want: `{"a":["c","d"]}`},
+		{name: "nested objects are merged", doc: `{"a":{"b":"c","d":"e"}}`, patch: `{"a":{"b":"f","d":null}}`, want: `{"a":{"b":"f"}}`},
+		{name: "non-object patch replaces", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "large numbers are kept", doc: `{"a":1}`, patch: `{"a":12345678901234567890}`, want: `{"a":12345678901234567890}`},
+		{name: "invalid patch", doc: `{"a":"b"}`, patch: `{"a":`, wantErr: ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Patch{MediaType: MediaTypeMergePatch, Body: []byte(tt.patch)}.Apply([]byte(tt.doc))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				assertJSONEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{name: "add member", doc: `{"a":1}`, patch: `[{"op":"add","path":"/b","value":2}]`, want: `{"a":1,"b":2}`},
+		{name: "add to array", doc: `{"a":[1,3]}`, patch: `[{"op":"add","path":"/a/1","value":2}]`, want: `{"a":[1,2,3]}`},
+		{name: "append to array", doc: `{"a":[1]}`, patch: `[{"op":"add","path":"/a/-","value":2}]`, want: `{"a":[1,2]}`},
+		{name: "add past the end of an array", doc: `{"a":[1]}`, patch: `[{"op":"add","path":"/a/2","value":2}]`, wantErr: ErrInvalidPatch},
+		{name: "add to a missing parent", doc: `{}`, patch: `[{"op":"add","path":"/a/b","value":1}]`, wantErr: ErrInvalidPatch},
+		{name: "remove member", doc: `{"a":1,"b":2}`, patch: `[{"op":"remove","path":"/a"}]`, want: `{"b":2}`},
+		{name: "remove from array", doc: `{"a":[1,2,3]}`, patch: `[{"op":"remove","path":"/a/0"}]`, want: `{"a":[2,3]}`},
+		{name: "remove missing member", doc: `{}`, patch: `[{"op":"remove","path":"/a"}]`, wantErr: ErrInvalidPatch},
+		{name: "replace member", doc: `{"a":1}`, patch: `[{"op":"replace","path":"/a","value":"x"}]`, want: `{"a":"x"}`},
+		{name: "replace missing member", doc: `{}`, patch: `[{"op":"replace","path":"/a","value":1}]`, wantErr: ErrInvalidPatch},
+		{name: "move member", doc: `{"a":{"b":1},"c":{}}`, patch: `[{"op":"move","from":"/a/b","path":"/c/d"}]`, want: `{"a":{},"c":{"d":1}}`},
+		{name: "move into itself", doc: `{"a":{"b":1}}`, patch: `[{"op":"move","from":"/a","path":"/a/b/c"}]`, wantErr: ErrInvalidPatch},
+		{name: "copy is deep", doc: `{"a":{"b":1}}`, patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, want: `{"a":{"b":1},"c":{"b":2}}`},
+		{name: "escaped pointer", doc: `{"a/b":1,"c~d":2}`, patch: `[{"op":"remove","path":"/a~1b"},{"op":"remove","path":"/c~0d"}]`, want: `{}`},
+		{name: "test string", doc: `{"a":"b"}`, patch: `[{"op":"test","path":"/a","value":"b"}]`, want: `{"a":"b"}`},
+		{name: "test fails", doc: `{"a":"b"}`, patch: `[{"op":"test","path":"/a","value":"c"}]`, wantErr: ErrTestFailed},
+		{name: "test integer against decimal", doc: `{"a":1}`, patch: `[{"op":"test","path":"/a","value":1.0}]`, want: `{"a":1}`},
+		{name: "test integer against exponent", doc: `{"a":100}`, patch: `[{"op":"test","path":"/a","value":1e2}]`, want: `{"a":100}`},
+		{name: "test different numbers", doc: `{"a":1}`, patch: `[{"op":"test","path":"/a","value":1.0000000000000001}]`, wantErr: ErrTestFailed},
+		{name: "test number against string", doc: `{"a":1}`, patch: `[{"op":"test","path":"/a","value":"1"}]`, wantErr: ErrTestFailed},
+		{name: "test object ignores member order", doc: `{"a":{"b":1,"c":[1,2]}}`, patch: `[{"op":"test","path":"/a","value":{"c":[1.0,2],"b":1}}]`, want: `{"a":{"b":1,"c":[1,2]}}`},
+		{name: "test array order matters", doc: `{"a":[1,2]}`, patch: `[{"op":"test","path":"/a","value":[2,1]}]`, wantErr: ErrTestFailed},
+		{name: "test null", doc: `{"a":null}`, patch: `[{"op":"test","path":"/a","value":null}]`, want: `{"a":null}`},
+		{name: "test sees earlier operations", doc: `{"a":1}`, patch: `[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":1}]`, wantErr: ErrTestFailed},
+		{name: "missing path", doc: `{}`, patch: `[{"op":"remove"}]`, wantErr: ErrInvalidPatch},
+		{name: "missing value", doc: `{}`, patch: `[{"op":"add","path":"/a"}]`, wantErr: ErrInvalidPatch},
+		{name: "unknown op", doc: `{}`, patch: `[{"op":"frobnicate","path":"/a"}]`, wantErr: ErrInvalidPatch},
+		{name: "invalid array index", doc: `{"a":[1,2]}`, patch: `[{"op":"remove","path":"/a/01"}]`, wantErr: ErrInvalidPatch},
+		{name: "relative path", doc: `{"a":1}`, patch: `[{"op":"remove","path":"a"}]`, wantErr: ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Patch{MediaType: MediaTypeJSONPatch, Body: []byte(tt.patch)}.Apply([]byte(tt.doc))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				assertJSONEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRejectsUnknownMembers(t *testing.T) {
+	var dst struct {
+		Name string `json:"name"`
+	}
+	if err := Unmarshal([]byte(`{"name":"a","other":1}`), &dst); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("Unmarshal() error = %v, want %v", err, ErrInvalidPatch)
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := decode(got, &gotValue); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := decode([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("Apply() = %s, want %s", got, want)
+	}
+}
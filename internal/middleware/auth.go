@@ -10,17 +10,7 @@ import (
 
 func RequireAuth(userRepo repository.UserRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		raw := strings.TrimSpace(c.Get("Authorization"))
-		if raw == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(map[string]any{
-				"errors": "Unauthorized",
-			})
-		}
-
-		token := raw
-		if strings.HasPrefix(strings.ToLower(raw), "bearer ") {
-			token = strings.TrimSpace(raw[7:])
-		}
+		token := bearerToken(c)
 		if token == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(map[string]any{
 				"errors": "Unauthorized",
@@ -39,3 +29,29 @@ func RequireAuth(userRepo repository.UserRepository) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// bearerToken returns the token sent in the Authorization header, with or
+// without the Bearer scheme.
+func bearerToken(c *fiber.Ctx) string {
+	token := strings.TrimSpace(c.Get("Authorization"))
+	if strings.HasPrefix(strings.ToLower(token), "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	return token
+}
+
+// QueryCredentials lets clients that cannot set request headers, such as
+// EventSource and WebSocket in browsers, send the token and the workspace
+// as the access_token and workspace query parameters. It must be
+// registered before the routes it serves.
+func QueryCredentials() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token := c.Query("access_token"); token != "" && c.Get("Authorization") == "" {
+			c.Request().Header.Set("Authorization", token)
+		}
+		if selector := c.Query("workspace"); selector != "" && c.Get("X-Workspace") == "" {
+			c.Request().Header.Set("X-Workspace", selector)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"golang-contact-management-restful-api/internal/idempotency"
+	"golang-contact-management-restful-api/modules/user/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers stored with the response and
+// sent again on replay.
+var replayedHeaders = []string{fiber.HeaderContentType, fiber.HeaderLocation, fiber.HeaderETag}
+
+// Idempotency makes POST requests sent with an Idempotency-Key header safe
+// to retry. The first request with a key runs and, unless it fails with a
+// server error, its response is stored for the user; retries with the same
+// key and request get that response again, marked Idempotent-Replayed.
+// Reusing a key for a different request fails with 422, and retrying while
+// the first request is still running fails with 409.
+//
+// It runs before the route's own authentication, so it identifies the user
+// from the token itself and leaves requests it cannot authenticate to the
+// route.
+func Idempotency(userRepo repository.UserRepository, store *idempotency.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if c.Method() != fiber.MethodPost || key == "" {
+			return c.Next()
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(map[string]any{
+				"errors": "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		token := bearerToken(c)
+		if token == "" {
+			return c.Next()
+		}
+		user, err := userRepo.FindByToken(c.Context(), token)
+		if err != nil {
+			return c.Next()
+		}
+
+		key = strings.Clone(key)
+		record, err := store.Begin(c.Context(), user.Username, key, fingerprint(c))
+		switch {
+		case errors.Is(err, idempotency.ErrFingerprintMismatch):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(map[string]any{
+				"errors": err.Error(),
+			})
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(fiber.StatusConflict).JSON(map[string]any{
+				"errors": err.Error(),
+			})
+		case err != nil:
+			return err
+		}
+
+		if record != nil {
+			for name, value := range record.ResponseHeaders {
+				c.Set(name, value)
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			return c.Status(record.ResponseStatus).Send(record.ResponseBody)
+		}
+
+		// The claim is held for as long as the route runs, however long.
+		stop := store.Hold(user.Username, key)
+		err = func() error {
+			defer stop()
+			return c.Next()
+		}()
+		if err != nil {
+			_ = store.Release(c.Context(), user.Username, key)
+			return err
+		}
+
+		// The response stands whatever happens to the key from here on. A
+		// key that cannot be completed stays claimed until the lock times
+		// out, which turns retries away rather than running them twice.
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			_ = store.Release(c.Context(), user.Username, key)
+			return nil
+		}
+
+		headers := map[string]string{}
+		for _, name := range replayedHeaders {
+			if value := c.GetRespHeader(name); value != "" {
+				headers[name] = value
+			}
+		}
+		body := append([]byte(nil), c.Response().Body()...)
+
+		_ = store.Complete(c.Context(), user.Username, key, status, headers, body)
+		return nil
+	}
+}
+
+// fingerprint identifies a request by its target, workspace and body, so
+// that a key reused for anything else is told apart from a retry.
+func fingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	for _, part := range []string{c.OriginalURL(), c.Get("X-Workspace"), c.Get(fiber.HeaderContentType)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"golang-contact-management-restful-api/internal/requestinfo"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestInfo identifies every request: it keeps the client's
+// X-Request-ID when it is well formed, generates one otherwise, and echoes
+// it in the response. The id, the client's IP and user agent are stored in
+// the request context for the audit log.
+func RequestInfo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := strings.Clone(c.Get(HeaderRequestID))
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(HeaderRequestID, requestID)
+
+		c.Locals(requestinfo.ContextKey{}, requestinfo.Info{
+			RequestID: requestID,
+			IP:        c.IP(),
+			UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		})
+
+		return c.Next()
+	}
+}
+
+// RequireAdmin lets through the users named in usernames only. It must
+// follow RequireAuth.
+func RequireAdmin(usernames []string) fiber.Handler {
+	admins := map[string]bool{}
+	for _, username := range usernames {
+		admins[username] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		username, _ := c.Locals("username").(string)
+		if !admins[username] {
+			return c.Status(fiber.StatusForbidden).JSON(map[string]any{
+				"errors": "Forbidden",
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"strings"
+
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/organization/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireWorkspace resolves the X-Workspace header to the workspace the
+// request operates in. It must run after RequireAuth. An empty header or
+// "personal" selects the user's own contacts; otherwise the header holds
+// the id or slug of an organization the user is a member of.
+func RequireWorkspace(organizationRepo repository.OrganizationRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		selector := strings.TrimSpace(c.Get("X-Workspace"))
+		if selector == "" || strings.EqualFold(selector, "personal") {
+			c.Locals(workspace.ContextKey{}, workspace.Workspace{})
+			return c.Next()
+		}
+
+		username, _ := c.Locals("username").(string)
+		member, err := organizationRepo.FindMembership(c.Context(), username, selector)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(map[string]any{
+				"errors": workspace.ErrNotFound.Error(),
+			})
+		}
+
+		c.Locals(workspace.ContextKey{}, workspace.Workspace{
+			OrganizationID: member.OrganizationID,
+			Role:           member.Role,
+		})
+
+		return c.Next()
+	}
+}
+
+// WorkspacePath lets clients select the workspace with a path segment
+// instead of the header: /api/workspaces/<id or slug>/contacts is served as
+// /api/contacts with X-Workspace set. It must be registered before the
+// routes it rewrites to.
+func WorkspacePath() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		prefix := "/api/workspaces/" + c.Params("workspace")
+		c.Request().Header.Set("X-Workspace", c.Params("workspace"))
+		c.Path("/api" + strings.TrimPrefix(c.Path(), prefix))
+		return c.Next()
+	}
+}
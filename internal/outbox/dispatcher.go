@@ -0,0 +1,190 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"golang-contact-management-restful-api/internal/pubsub"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Retention is how long events are kept once every subscriber has handled
+// them.
+const Retention = 7 * 24 * time.Hour
+
+const batchSize = 100
+
+// Handler handles an event. An error makes the dispatcher try the event
+// again later, so handlers must tolerate seeing an event more than once.
+type Handler func(ctx context.Context, event Event) error
+
+// Subscription is the position of a subscriber in the outbox: the
+// transaction and the id of the last event it handled.
+type Subscription struct {
+	Name          string    `gorm:"column:name;primaryKey;size:100"`
+	TransactionID int64     `gorm:"column:transaction_id;not null;default:0"`
+	Cursor        int64     `gorm:"column:cursor;not null;default:0"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Subscription) TableName() string {
+	return "outbox_subscriptions"
+}
+
+type subscriber struct {
+	name    string
+	types   map[string]bool
+	handler Handler
+}
+
+func (subscriber subscriber) accepts(eventType string) bool {
+	return len(subscriber.types) == 0 || subscriber.types[eventType]
+}
+
+// Dispatcher delivers the events of the outbox to in-process subscribers,
+// at least once and in the order their transactions were written. Each
+// subscriber is delivered to by a single instance of the application at a
+// time.
+type Dispatcher struct {
+	db     *gorm.DB
+	broker pubsub.Broker
+
+	mutex       sync.Mutex
+	subscribers []subscriber
+}
+
+func NewDispatcher(db *gorm.DB, broker pubsub.Broker) *Dispatcher {
+	return &Dispatcher{db: db, broker: broker}
+}
+
+// Subscribe registers handler for events of the given types, or of every
+// type when none is given. Name keys the subscriber's position in the
+// outbox and must not change across restarts; a new subscriber starts with
+// the events published after it first runs.
+func (dispatcher *Dispatcher) Subscribe(name string, handler Handler, types ...string) {
+	subscriber := subscriber{name: name, types: map[string]bool{}, handler: handler}
+	for _, eventType := range types {
+		subscriber.types[eventType] = true
+	}
+
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	dispatcher.subscribers = append(dispatcher.subscribers, subscriber)
+}
+
+// Run dispatches events until ctx is done, whenever the broker reports new
+// ones and at least every interval. Events wait there while an earlier
+// transaction is still running. Errors are passed to onError and the
+// events retried on the next round.
+func (dispatcher *Dispatcher) Run(ctx context.Context, interval time.Duration, onError func(err error)) {
+	notifications, unsubscribe := dispatcher.broker.Subscribe(Channel)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, subscriber := range dispatcher.snapshot() {
+			if err := dispatcher.drain(ctx, subscriber); err != nil && ctx.Err() == nil {
+				onError(fmt.Errorf("subscriber %s: %w", subscriber.name, err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-notifications:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the events published before the given time that every
+// subscriber has handled.
+func (dispatcher *Dispatcher) Prune(ctx context.Context, before time.Time) error {
+	var names []string
+	for _, subscriber := range dispatcher.snapshot() {
+		names = append(names, subscriber.name)
+	}
+
+	return dispatcher.db.WithContext(ctx).
+		Where("created_at < ?", before).
+		Where(`NOT EXISTS (SELECT 1 FROM "outbox_subscriptions" s WHERE s.name IN ?
+			AND (s.transaction_id, s.cursor) < ("outbox_events".transaction_id, "outbox_events".id))`, names).
+		Delete(&Event{}).Error
+}
+
+func (dispatcher *Dispatcher) snapshot() []subscriber {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	return append([]subscriber(nil), dispatcher.subscribers...)
+}
+
+// drain delivers batches to subscriber until it has caught up.
+func (dispatcher *Dispatcher) drain(ctx context.Context, subscriber subscriber) error {
+	for {
+		count, err := dispatcher.deliver(ctx, subscriber)
+		if err != nil || count < batchSize {
+			return err
+		}
+	}
+}
+
+// deliver hands the next batch of events to subscriber and moves its
+// position past the events it handled. Only events written before the
+// oldest running transaction are read, as one still running may yet commit
+// events ordered before those of later transactions. The subscription row
+// stays locked meanwhile, and a subscriber locked by another instance is
+// skipped.
+func (dispatcher *Dispatcher) deliver(ctx context.Context, subscriber subscriber) (int, error) {
+	var count int
+	var handlerErr error
+	err := dispatcher.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var subscriptions []Subscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("name = ?", subscriber.name).Find(&subscriptions).Error; err != nil {
+			return err
+		}
+
+		if len(subscriptions) == 0 {
+			return tx.Exec(`INSERT INTO "outbox_subscriptions" ("name", "transaction_id", "cursor", "updated_at")
+				VALUES (?, `+Horizon+`, 0, now())
+				ON CONFLICT ("name") DO NOTHING`, subscriber.name).Error
+		}
+		subscription := subscriptions[0]
+
+		var events []Event
+		if err := tx.Where("(transaction_id, id) > (?, ?)", subscription.TransactionID, subscription.Cursor).
+			Where("transaction_id < " + Horizon).
+			Order("transaction_id, id").Limit(batchSize).Find(&events).Error; err != nil {
+			return err
+		}
+		count = len(events)
+
+		position := subscription
+		for _, event := range events {
+			if subscriber.accepts(event.Type) {
+				if handlerErr = subscriber.handler(ctx, event); handlerErr != nil {
+					break
+				}
+			}
+			position.TransactionID, position.Cursor = event.TransactionID, event.ID
+		}
+
+		if position == subscription {
+			return nil
+		}
+		return tx.Model(&Subscription{}).Where("name = ?", subscriber.name).
+			Updates(map[string]any{"transaction_id": position.TransactionID, "cursor": position.Cursor, "updated_at": time.Now()}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	if handlerErr != nil {
+		return 0, handlerErr
+	}
+	return count, nil
+}
@@ -0,0 +1,131 @@
+// Package outbox publishes domain events. Repositories append events inside
+// the transaction that makes the change, so an event exists if and only if
+// its change committed, and a Dispatcher hands them to the subscribers of
+// the application afterwards.
+//
+// Publishers do not wait for each other. Events are instead read in the
+// order of the transactions that wrote them, and only once every earlier
+// transaction has ended, so a reader that has seen one never misses one
+// committed later.
+package outbox
+
+import (
+	"encoding/json"
+	"golang-contact-management-restful-api/internal/requestinfo"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ContactCreated = "contact.created"
+	ContactUpdated = "contact.updated"
+	ContactDeleted = "contact.deleted"
+
+	AddressCreated = "address.created"
+	AddressUpdated = "address.updated"
+	AddressDeleted = "address.deleted"
+
+	UserRegistered      = "user.registered"
+	UserLoggedIn        = "user.logged_in"
+	UserLoginFailed     = "user.login_failed"
+	UserLoggedOut       = "user.logged_out"
+	UserPasswordChanged = "user.password_changed"
+	UserTokenRevoked    = "user.token_revoked"
+)
+
+// ContactEvents are the types of the events about contacts and their
+// addresses.
+var ContactEvents = []string{
+	ContactCreated, ContactUpdated, ContactDeleted,
+	AddressCreated, AddressUpdated, AddressDeleted,
+}
+
+// Horizon is the SQL for the id of the oldest transaction still running.
+// Rows written by earlier transactions are final: committed, or rolled
+// back and gone.
+const Horizon = "pg_snapshot_xmin(pg_current_snapshot())::text::bigint"
+
+// Channel is the notification channel told about new events when they
+// commit.
+const Channel = "outbox"
+
+// Event is a domain event. AggregateID is the id of the entity the event is
+// about, Username and OrganizationID its owner, and Payload its state as
+// written, or the ids it had for a deleted entity that is already gone.
+// Metadata describes the request that caused the event, as a
+// requestinfo.Info. TransactionID is set by the database to the id of the
+// transaction that published it.
+type Event struct {
+	ID             int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement;index:idx_outbox_events_position,priority:2"`
+	TransactionID  int64     `json:"-" gorm:"column:transaction_id;not null;default:(pg_current_xact_id())::text::bigint;index:idx_outbox_events_position,priority:1"`
+	Type           string    `json:"type" gorm:"column:type;size:64;not null"`
+	AggregateID    string    `json:"aggregate_id" gorm:"column:aggregate_id;size:255;not null"`
+	Username       string    `json:"username" gorm:"column:username;size:255;not null"`
+	OrganizationID *int      `json:"organization_id,omitempty" gorm:"column:organization_id"`
+	Payload        string    `json:"payload" gorm:"column:payload;type:jsonb;not null"`
+	Metadata       string    `json:"metadata" gorm:"column:metadata;type:jsonb;not null;default:'{}'"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime;index"`
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// NewEvent returns an event with payload encoded as JSON.
+func NewEvent(eventType string, aggregateID string, username string, organizationID *int, payload any) (Event, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:           eventType,
+		AggregateID:    aggregateID,
+		Username:       username,
+		OrganizationID: organizationID,
+		Payload:        string(encoded),
+	}, nil
+}
+
+// Info decodes the event's metadata.
+func (event Event) Info() requestinfo.Info {
+	var info requestinfo.Info
+	_ = json.Unmarshal([]byte(event.Metadata), &info)
+	return info
+}
+
+// Publish appends events to the outbox in tx. Events without metadata get
+// that of the request tx serves.
+func Publish(tx *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := Begin(tx); err != nil {
+		return err
+	}
+
+	for i := range events {
+		if events[i].Metadata == "" {
+			events[i].Metadata = Metadata(tx)
+		}
+	}
+	return tx.Create(&events).Error
+}
+
+// Metadata encodes the info of the request tx serves, for publishers that
+// insert events with their own statements.
+func Metadata(tx *gorm.DB) string {
+	encoded, err := json.Marshal(requestinfo.FromContext(tx.Statement.Context))
+	if err != nil {
+		return "{}"
+	}
+	return string(encoded)
+}
+
+// Begin prepares tx for appending to the outbox. Publishers that insert
+// events with their own statements must call it first.
+func Begin(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_notify(?, '')", Channel).Error
+}
@@ -0,0 +1,70 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type postgresBroker struct {
+	*memoryBroker
+	db *gorm.DB
+}
+
+// NewPostgresBroker returns a broker built on Postgres LISTEN/NOTIFY, which
+// delivers notifications to every instance of the application. It listens
+// on channels over a dedicated connection to url, reconnecting when it is
+// lost, until ctx is done; notifications are sent through db. Notifications
+// sent with NOTIFY inside a transaction are only delivered once it commits.
+func NewPostgresBroker(ctx context.Context, db *gorm.DB, url string, log *logrus.Logger, channels ...string) Broker {
+	broker := &postgresBroker{
+		memoryBroker: NewMemoryBroker().(*memoryBroker),
+		db:           db,
+	}
+
+	go func() {
+		for ctx.Err() == nil {
+			if err := broker.listen(ctx, url, channels); err != nil && ctx.Err() == nil {
+				log.WithError(err).Error("Lost the notification listener connection")
+				select {
+				case <-ctx.Done():
+				case <-time.After(5 * time.Second):
+				}
+			}
+		}
+	}()
+
+	return broker
+}
+
+func (broker *postgresBroker) Publish(ctx context.Context, channel string, payload string) error {
+	return broker.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+func (broker *postgresBroker) listen(ctx context.Context, url string, channels []string) error {
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	for _, channel := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+		// Notifications sent while reconnecting are lost, so subscribers
+		// are told to catch up.
+		_ = broker.memoryBroker.Publish(ctx, channel, "")
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		_ = broker.memoryBroker.Publish(ctx, notification.Channel, notification.Payload)
+	}
+}
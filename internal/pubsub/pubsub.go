@@ -0,0 +1,62 @@
+// Package pubsub delivers notifications between the parts of the
+// application. Payloads are short hints, such as the position of the latest
+// change, that subscribers act on by reading the database; a notification
+// may be dropped for a slow subscriber, which is expected to catch up on
+// the next one.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+type Broker interface {
+	Publish(ctx context.Context, channel string, payload string) error
+	// Subscribe returns the notifications published on channel until the
+	// returned function is called.
+	Subscribe(channel string) (<-chan string, func())
+}
+
+type memoryBroker struct {
+	mutex       sync.Mutex
+	subscribers map[string]map[chan string]struct{}
+}
+
+// NewMemoryBroker returns a broker delivering notifications within the
+// process only.
+func NewMemoryBroker() Broker {
+	return &memoryBroker{subscribers: map[string]map[chan string]struct{}{}}
+}
+
+func (broker *memoryBroker) Publish(ctx context.Context, channel string, payload string) error {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	for subscriber := range broker.subscribers[channel] {
+		select {
+		case subscriber <- payload:
+		default:
+		}
+	}
+	return nil
+}
+
+func (broker *memoryBroker) Subscribe(channel string) (<-chan string, func()) {
+	subscriber := make(chan string, 1)
+
+	broker.mutex.Lock()
+	if broker.subscribers[channel] == nil {
+		broker.subscribers[channel] = map[chan string]struct{}{}
+	}
+	broker.subscribers[channel][subscriber] = struct{}{}
+	broker.mutex.Unlock()
+
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			broker.mutex.Lock()
+			delete(broker.subscribers[channel], subscriber)
+			broker.mutex.Unlock()
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Package requestinfo carries what is known about the request being served
+// to the code that records what it did.
+package requestinfo
+
+import "context"
+
+// Info describes a request. Actor is the authenticated user, if any.
+type Info struct {
+	RequestID string `json:"request_id,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Actor     string `json:"actor,omitempty"`
+}
+
+// ContextKey is the key the info is stored under, both in fiber locals and
+// in the request context handed to usecases and repositories.
+type ContextKey struct{}
+
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, ContextKey{}, info)
+}
+
+// FromContext returns the info stored in ctx. The actor defaults to the
+// user set by the authentication middleware.
+func FromContext(ctx context.Context) Info {
+	if ctx == nil {
+		return Info{}
+	}
+
+	info, _ := ctx.Value(ContextKey{}).(Info)
+	if info.Actor == "" {
+		info.Actor, _ = ctx.Value("username").(string)
+	}
+	return info
+}
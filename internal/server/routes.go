@@ -2,41 +2,139 @@ package server
 
 import (
 	addressHandlerPkg "golang-contact-management-restful-api/modules/address/handler"
+	auditHandlerPkg "golang-contact-management-restful-api/modules/audit/handler"
+	companyHandlerPkg "golang-contact-management-restful-api/modules/company/handler"
 	contactHandlerPkg "golang-contact-management-restful-api/modules/contact/handler"
+	contactImportHandlerPkg "golang-contact-management-restful-api/modules/contactimport/handler"
+	historyHandlerPkg "golang-contact-management-restful-api/modules/history/handler"
+	organizationHandlerPkg "golang-contact-management-restful-api/modules/organization/handler"
+	relationshipHandlerPkg "golang-contact-management-restful-api/modules/relationship/handler"
+	savedSearchHandlerPkg "golang-contact-management-restful-api/modules/savedsearch/handler"
+	shareHandlerPkg "golang-contact-management-restful-api/modules/share/handler"
+	syncHandlerPkg "golang-contact-management-restful-api/modules/sync/handler"
 	userHandlerPkg "golang-contact-management-restful-api/modules/user/handler"
+	webhookHandlerPkg "golang-contact-management-restful-api/modules/webhook/handler"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
 )
 
-func RegisterUserRoutes(app *fiber.App, userHandler userHandlerPkg.UserHandler, auth fiber.Handler) {
-	api := app.Group("/api")
+// The Register functions add routes to the /api router they are given.
+// main applies authentication, and the workspace to those that need one,
+// once on shared groups rather than per module.
 
+// RegisterUserRoutes adds the public user routes, and those of the current
+// user behind auth, to a router without authentication. auth is set per
+// route rather than on a /users group, as a group would also match the
+// authenticated /users routes of other modules and run auth twice.
+func RegisterUserRoutes(api fiber.Router, userHandler userHandlerPkg.UserHandler, auth fiber.Handler) {
 	api.Post("/users", userHandler.Register)
 	api.Post("/users/login", userHandler.Login)
-
-	u := api.Group("/users", auth)
-	u.Patch("/current", userHandler.UpdateCurrent)
-	u.Get("/current", userHandler.GetCurrent)
-	u.Delete("/logout", userHandler.Logout)
-
+	api.Patch("/users/current", auth, userHandler.UpdateCurrent)
+	api.Get("/users/current", auth, userHandler.GetCurrent)
+	api.Delete("/users/logout", auth, userHandler.Logout)
 }
 
-func RegisterContactRoutes(app *fiber.App, contactHandler contactHandlerPkg.ContactHandler, auth fiber.Handler) {
-	api := app.Group("/api", auth)
+func RegisterOrganizationRoutes(api fiber.Router, organizationHandler organizationHandlerPkg.OrganizationHandler) {
+	api.Post("/organizations", organizationHandler.Create)
+	api.Get("/organizations", organizationHandler.FindAll)
+	api.Get("/organizations/:orgId", organizationHandler.FindByID)
+	api.Put("/organizations/:orgId", organizationHandler.UpdateByID)
+	api.Delete("/organizations/:orgId", organizationHandler.DeleteByID)
+	api.Get("/organizations/:orgId/members", organizationHandler.FindMembers)
+	api.Post("/organizations/:orgId/members", organizationHandler.AddMember)
+	api.Put("/organizations/:orgId/members/:username", organizationHandler.UpdateMember)
+	api.Delete("/organizations/:orgId/members/:username", organizationHandler.RemoveMember)
+}
 
+func RegisterContactRoutes(api fiber.Router, contactHandler contactHandlerPkg.ContactHandler) {
 	api.Post("/contacts", contactHandler.Create)
 	api.Get("/contacts", contactHandler.Search)
+	api.Post("/contacts/bulk", contactHandler.Bulk)
+	api.Get("/contacts/export", compress.New(), contactHandler.Export)
 	api.Get("/contacts/:id", contactHandler.GetByID)
 	api.Put("/contacts/:id", contactHandler.UpdateByID)
+	api.Patch("/contacts/:id", contactHandler.PatchByID)
 	api.Delete("/contacts/:id", contactHandler.DeleteByID)
 }
 
-func RegisterAddressRoutes(app *fiber.App, addressHandler addressHandlerPkg.AddressHandler, auth fiber.Handler) {
-	api := app.Group("/api", auth)
-
+func RegisterAddressRoutes(api fiber.Router, addressHandler addressHandlerPkg.AddressHandler) {
 	api.Post("/contacts/:contactId/addresses", addressHandler.Create)
 	api.Get("/contacts/:contactId/addresses", addressHandler.FindAll)
 	api.Get("/contacts/:contactId/addresses/:addressId", addressHandler.FindByID)
 	api.Put("/contacts/:contactId/addresses/:addressId", addressHandler.UpdateByID)
+	api.Patch("/contacts/:contactId/addresses/:addressId", addressHandler.PatchByID)
 	api.Delete("/contacts/:contactId/addresses/:addressId", addressHandler.DeleteByID)
 }
+
+func RegisterContactRelationshipRoutes(api fiber.Router, contactRelationshipHandler relationshipHandlerPkg.ContactRelationshipHandler) {
+	api.Post("/contacts/:contactId/relationships", contactRelationshipHandler.Create)
+	api.Get("/contacts/:contactId/relationships", contactRelationshipHandler.FindAll)
+	api.Delete("/contacts/:contactId/relationships/:relationshipId", contactRelationshipHandler.DeleteByID)
+	api.Get("/contacts/:contactId/graph", contactRelationshipHandler.Graph)
+}
+
+func RegisterContactVersionRoutes(api fiber.Router, contactVersionHandler historyHandlerPkg.ContactVersionHandler) {
+	api.Get("/contacts/:contactId/history", contactVersionHandler.FindAll)
+	api.Post("/contacts/:contactId/revert", contactVersionHandler.Revert)
+}
+
+func RegisterCompanyRoutes(api fiber.Router, companyHandler companyHandlerPkg.CompanyHandler) {
+	api.Post("/companies", companyHandler.Create)
+	api.Get("/companies", companyHandler.Search)
+	api.Get("/companies/:companyId", companyHandler.GetByID)
+	api.Put("/companies/:companyId", companyHandler.UpdateByID)
+	api.Delete("/companies/:companyId", companyHandler.DeleteByID)
+	api.Get("/companies/:companyId/contacts", companyHandler.Contacts)
+}
+
+func RegisterContactImportRoutes(api fiber.Router, contactImportHandler contactImportHandlerPkg.ContactImportHandler) {
+	api.Post("/contacts/imports", contactImportHandler.Upload)
+	api.Get("/contacts/imports/:importId", contactImportHandler.FindByID)
+	api.Put("/contacts/imports/:importId/mapping", contactImportHandler.UpdateMapping)
+	api.Post("/contacts/imports/:importId/preview", contactImportHandler.Preview)
+	api.Post("/contacts/imports/:importId/commit", contactImportHandler.Commit)
+}
+
+func RegisterSavedSearchRoutes(api fiber.Router, savedSearchHandler savedSearchHandlerPkg.SavedSearchHandler) {
+	api.Post("/saved-searches", savedSearchHandler.Create)
+	api.Get("/saved-searches", savedSearchHandler.FindAll)
+	api.Get("/saved-searches/:id", savedSearchHandler.FindByID)
+	api.Put("/saved-searches/:id", savedSearchHandler.UpdateByID)
+	api.Delete("/saved-searches/:id", savedSearchHandler.DeleteByID)
+	api.Get("/saved-searches/:id/contacts", savedSearchHandler.Contacts)
+}
+
+func RegisterContactShareRoutes(api fiber.Router, contactShareHandler shareHandlerPkg.ContactShareHandler) {
+	api.Post("/shares", contactShareHandler.Create)
+	api.Get("/shares", contactShareHandler.FindAll)
+	api.Put("/shares/:id", contactShareHandler.UpdateByID)
+	api.Post("/shares/:id/accept", contactShareHandler.Accept)
+	api.Delete("/shares/:id", contactShareHandler.DeleteByID)
+}
+
+func RegisterSyncRoutes(api fiber.Router, syncHandler syncHandlerPkg.SyncHandler) {
+	api.Get("/sync", syncHandler.Sync)
+	api.Get("/events", syncHandler.Events)
+	api.Get("/events/ws", syncHandler.WebSocket)
+}
+
+func RegisterWebhookRoutes(api fiber.Router, webhookHandler webhookHandlerPkg.WebhookHandler) {
+	api.Post("/webhooks", webhookHandler.Create)
+	api.Get("/webhooks", webhookHandler.FindAll)
+	api.Get("/webhooks/:id", webhookHandler.FindByID)
+	api.Put("/webhooks/:id", webhookHandler.UpdateByID)
+	api.Delete("/webhooks/:id", webhookHandler.DeleteByID)
+	api.Get("/webhooks/:id/deliveries", webhookHandler.Deliveries)
+	api.Get("/webhooks/:id/deliveries/:deliveryId", webhookHandler.Delivery)
+	api.Post("/webhooks/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+}
+
+func RegisterAuditRoutes(api fiber.Router, auditHandler auditHandlerPkg.AuditHandler, admin fiber.Handler) {
+	api.Get("/users/current/audit", auditHandler.FindCurrent)
+
+	a := api.Group("/admin", admin)
+	a.Get("/audit", auditHandler.FindAll)
+	a.Get("/audit/export", auditHandler.Export)
+	a.Get("/audit/verify", auditHandler.Verify)
+}
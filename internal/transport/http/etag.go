@@ -0,0 +1,90 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag returns the entity tag of a stored version of a resource.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatch returns the version required by the If-Match header, or zero when
+// the header is absent or "*". ok is false when the header does not name a
+// single version, which can never match.
+func IfMatch(c *fiber.Ctx) (version int, ok bool) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	version, ok = parseETag(header)
+	return version, ok && version > 0
+}
+
+// NotModified reports whether the If-None-Match header matches version, in
+// which case the client's copy is current.
+func NotModified(c *fiber.Ctx, version int) bool {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfNoneMatch))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if candidate, ok := parseETag(strings.TrimSpace(tag)); ok && candidate == version {
+			return true
+		}
+	}
+	return false
+}
+
+// RepresentationETag returns the weak entity tag of a representation that
+// is more than the stored version of a resource, such as one with included
+// relations or only some fields. It changes with body, and never matches
+// If-Match, which needs the tag from ETag.
+func RepresentationETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// NoneMatch reports whether the If-None-Match header lists tag, comparing
+// tags weakly.
+func NoneMatch(c *fiber.Ctx, tag string) bool {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfNoneMatch))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseETag reads a version out of a tag written by ETag. Weak tags are
+// accepted as the version does not depend on the representation.
+func parseETag(tag string) (int, bool) {
+	tag = strings.TrimPrefix(tag, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
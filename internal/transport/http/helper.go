@@ -21,6 +21,13 @@ func PointerToString(p *string) string {
 	return ""
 }
 
+func PointerToInt(p *int) int {
+	if p != nil {
+		return *p
+	}
+	return 0
+}
+
 func StringToPointerIfNotEmpty(s string) *string {
 	if s == "" {
 		return nil
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"bufio"
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// streamBuffer items are read ahead of the client.
+const streamBuffer = 100
+
+// StreamWriter writes the items of a streamed response.
+type StreamWriter[T any] interface {
+	Write(item T) error
+	// Fail ends a response that could not be completed with a record
+	// telling the client so, as the status has already been sent.
+	Fail(err error) error
+	Close() error
+}
+
+// Stream answers with the items produce yields, written by the writer
+// newWriter returns. produce runs in the background with ctx, which must
+// not be request-scoped as the response is written after the handler has
+// returned. The status is only committed once the first item has been
+// produced or produce has finished: an error until then is returned, for
+// the caller to answer with, and later errors are passed to Fail. Headers
+// for the stream are best set once Stream returned nil.
+func Stream[T any](c *fiber.Ctx, ctx context.Context, produce func(ctx context.Context, yield func(item T) error) error, newWriter func(w *bufio.Writer) StreamWriter[T]) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	items := make(chan T, streamBuffer)
+	done := make(chan error, 1)
+	go func() {
+		defer close(items)
+		done <- produce(ctx, func(item T) error {
+			select {
+			case items <- item:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	first, ok := <-items
+	if !ok {
+		if err := <-done; err != nil {
+			cancel()
+			return err
+		}
+	}
+
+	c.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Stops the producer when the client goes away.
+		defer cancel()
+
+		writer := newWriter(w)
+		if ok {
+			if err := writer.Write(first); err != nil {
+				return
+			}
+			for item := range items {
+				if err := writer.Write(item); err != nil {
+					return
+				}
+			}
+
+			if err := <-done; err != nil {
+				_ = writer.Fail(err)
+				return
+			}
+		}
+		_ = writer.Close()
+	})
+	return nil
+}
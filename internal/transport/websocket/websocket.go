@@ -0,0 +1,257 @@
+// Package websocket implements the server side of the WebSocket protocol
+// (RFC 6455) on top of fiber, enough to push messages to browsers: text and
+// binary messages, pings and the closing handshake. Extensions and
+// subprotocols are not negotiated.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	CloseNormal    = 1000
+	CloseGoingAway = 1001
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// acceptGUID is appended to the client's key to prove the handshake was
+// understood.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	maxMessageSize = 64 << 10
+	// maxControlSize bounds the payload of close, ping and pong frames.
+	maxControlSize = 125
+	writeTimeout   = 10 * time.Second
+)
+
+var (
+	ErrNotUpgrade = errors.New("expected a websocket upgrade request")
+	ErrProtocol   = errors.New("websocket protocol error")
+)
+
+// IsUpgrade reports whether the request asks to switch to WebSocket.
+func IsUpgrade(c *fiber.Ctx) bool {
+	return strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket") &&
+		containsToken(c.Get(fiber.HeaderConnection), "upgrade")
+}
+
+// Upgrade completes the opening handshake and serves the connection with
+// handler once the response is sent. The connection is closed when handler
+// returns. Nothing is written when the request is not a valid handshake.
+func Upgrade(c *fiber.Ctx, handler func(conn *Conn)) error {
+	key := c.Get("Sec-WebSocket-Key")
+	if c.Method() != fiber.MethodGet || !IsUpgrade(c) || key == "" || c.Get("Sec-WebSocket-Version") != "13" {
+		return ErrNotUpgrade
+	}
+
+	c.Set(fiber.HeaderUpgrade, "websocket")
+	c.Set(fiber.HeaderConnection, "Upgrade")
+	c.Set("Sec-WebSocket-Accept", acceptKey(key))
+	c.Status(fiber.StatusSwitchingProtocols).Context().Hijack(func(netConn net.Conn) {
+		conn := &Conn{conn: netConn, reader: bufio.NewReader(netConn)}
+		defer netConn.Close()
+		handler(conn)
+	})
+	return nil
+}
+
+// Conn is an upgraded connection. Writes may be called concurrently with
+// each other and with ReadMessage.
+type Conn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	idleTimeout time.Duration
+	mutex       sync.Mutex
+}
+
+// SetIdleTimeout makes reads fail when the peer sends no frame, pongs
+// included, for d. Zero disables the timeout.
+func (conn *Conn) SetIdleTimeout(d time.Duration) {
+	conn.idleTimeout = d
+}
+
+func (conn *Conn) WriteText(data []byte) error {
+	return conn.writeFrame(opText, data)
+}
+
+// Ping asks the peer for a pong, which keeps an idle connection alive.
+func (conn *Conn) Ping() error {
+	return conn.writeFrame(opPing, nil)
+}
+
+// Close starts the closing handshake with the given status code. Reasons
+// longer than a control frame allows are cut.
+func (conn *Conn) Close(code int, reason string) error {
+	if len(reason) > maxControlSize-2 {
+		reason = reason[:maxControlSize-2]
+	}
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	return conn.writeFrame(opClose, append(payload, reason...))
+}
+
+// ReadMessage returns the next text or binary message, answering pings on
+// the way. It returns io.EOF once the peer closed the connection.
+func (conn *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := conn.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := conn.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			_ = conn.writeFrame(opClose, payload)
+			return nil, io.EOF
+		case opText, opBinary:
+			if message != nil {
+				return nil, ErrProtocol
+			}
+			message = []byte{}
+		case opContinuation:
+			if message == nil {
+				return nil, ErrProtocol
+			}
+		default:
+			return nil, ErrProtocol
+		}
+
+		if len(message)+len(payload) > maxMessageSize {
+			return nil, ErrProtocol
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (conn *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	if conn.idleTimeout > 0 {
+		if err := conn.conn.SetReadDeadline(time.Now().Add(conn.idleTimeout)); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	var header [2]byte
+	if _, err := io.ReadFull(conn.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0f
+
+	// No extension is negotiated, so the reserved bits must be clear, and
+	// frames from clients are always masked.
+	if header[0]&0x70 != 0 || header[1]&0x80 == 0 {
+		return false, 0, nil, ErrProtocol
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(conn.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(conn.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > maxMessageSize {
+		return false, 0, nil, ErrProtocol
+	}
+
+	// Control frames may be sent between the fragments of a message, so
+	// they cannot be fragmented themselves, and are kept short.
+	if opcode&0x8 != 0 && (!fin || length > maxControlSize) {
+		return false, 0, nil, ErrProtocol
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(conn.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(conn.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+func (conn *Conn) writeFrame(opcode byte, payload []byte) error {
+	frame := make([]byte, 0, 10+len(payload))
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, byte(len(payload)))
+	case len(payload) <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+	frame = append(frame, payload...)
+
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	if err := conn.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err := conn.conn.Write(frame)
+	return err
+}
+
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func containsToken(header string, token string) bool {
+	for _, value := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(value), token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeConn serves the frames of a client from in and collects what the
+// server writes in out.
+type fakeConn struct {
+	net.Conn
+	in  *bytes.Reader
+	out bytes.Buffer
+}
+
+func (c *fakeConn) Read(p []byte) (int, error)       { return c.in.Read(p) }
+func (c *fakeConn) Write(p []byte) (int, error)      { return c.out.Write(p) }
+func (c *fakeConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *fakeConn) SetWriteDeadline(time.Time) error { return nil }
+
+func newTestConn(frames ...[]byte) (*Conn, *fakeConn) {
+	fake := &fakeConn{in: bytes.NewReader(bytes.Join(frames, nil))}
+	return &Conn{conn: fake, reader: bufio.NewReader(fake)}, fake
+}
+
+var testMask = [4]byte{0x37, 0xfa, 0x21, 0x3d}
+
+// clientFrame encodes a masked frame as a client sends it.
+func clientFrame(fin bool, opcode byte, payload []byte) []byte {
+	return encodeFrame(fin, opcode, payload, true)
+}
+
+func encodeFrame(fin bool, opcode byte, payload []byte, masked bool) []byte {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, maskBit|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+
+	if !masked {
+		return append(frame, payload...)
+	}
+	frame = append(frame, testMask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^testMask[i%4])
+	}
+	return frame
+}
+
+// serverFrame encodes an unmasked frame as the server sends it.
+func serverFrame(opcode byte, payload []byte) []byte {
+	return encodeFrame(true, opcode, payload, false)
+}
+
+func TestReadMessage(t *testing.T) {
+	oversize := bytes.Repeat([]byte("a"), maxMessageSize+1)
+	half := bytes.Repeat([]byte("a"), maxMessageSize/2+1)
+
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		want    string
+		wantErr error
+		written []byte
+	}{
+		{
+			name:   "masked text",
+			frames: [][]byte{clientFrame(true, opText, []byte("hello"))},
+			want:   "hello",
+		},
+		{
+			name:   "masked binary with a 16-bit length",
+			frames: [][]byte{clientFrame(true, opBinary, bytes.Repeat([]byte("b"), 300))},
+			want:   string(bytes.Repeat([]byte("b"), 300)),
+		},
+		{
+			name:    "unmasked frame",
+			frames:  [][]byte{encodeFrame(true, opText, []byte("hello"), false)},
+			wantErr: ErrProtocol,
+		},
+		{
+			name:    "reserved bit set",
+			frames:  [][]byte{append([]byte{0xc1}, clientFrame(true, opText, []byte("hello"))[1:]...)},
+			wantErr: ErrProtocol,
+		},
+		{
+			name: "fragmented text",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(false, opContinuation, []byte("l")),
+				clientFrame(true, opContinuation, []byte("o")),
+			},
+			want: "hello",
+		},
+		{
+			name:    "continuation without a message",
+			frames:  [][]byte{clientFrame(true, opContinuation, []byte("o"))},
+			wantErr: ErrProtocol,
+		},
+		{
+			name: "new message before the last one ended",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(true, opText, []byte("lo")),
+			},
+			wantErr: ErrProtocol,
+		},
+		{
+			name:    "unknown opcode",
+			frames:  [][]byte{clientFrame(true, 0x3, nil)},
+			wantErr: ErrProtocol,
+		},
+		{
+			name:    "oversize frame",
+			frames:  [][]byte{clientFrame(true, opBinary, oversize)},
+			wantErr: ErrProtocol,
+		},
+		{
+			name: "oversize fragmented message",
+			frames: [][]byte{
+				clientFrame(false, opBinary, half),
+				clientFrame(true, opContinuation, half),
+			},
+			wantErr: ErrProtocol,
+		},
+		{
+			name: "ping between fragments",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(true, opPing, []byte("are you there")),
+				clientFrame(true, opContinuation, []byte("lo")),
+			},
+			want:    "hello",
+			written: serverFrame(opPong, []byte("are you there")),
+		},
+		{
+			name: "pong between fragments",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(true, opPong, nil),
+				clientFrame(true, opContinuation, []byte("lo")),
+			},
+			want: "hello",
+		},
+		{
+			name:    "fragmented ping",
+			frames:  [][]byte{clientFrame(false, opPing, []byte("are you")), clientFrame(true, opContinuation, []byte(" there"))},
+			wantErr: ErrProtocol,
+		},
+		{
+			name:    "oversize ping",
+			frames:  [][]byte{clientFrame(true, opPing, bytes.Repeat([]byte("p"), maxControlSize+1))},
+			wantErr: ErrProtocol,
+		},
+		{
+			name:    "close",
+			frames:  [][]byte{clientFrame(true, opClose, []byte{0x03, 0xe8, 'b', 'y', 'e'})},
+			wantErr: io.EOF,
+			written: serverFrame(opClose, []byte{0x03, 0xe8}),
+		},
+		{
+			name:    "close between fragments",
+			frames:  [][]byte{clientFrame(false, opText, []byte("hel")), clientFrame(true, opClose, nil)},
+			wantErr: io.EOF,
+			written: serverFrame(opClose, nil),
+		},
+		{
+			name:    "truncated frame",
+			frames:  [][]byte{clientFrame(true, opText, []byte("hello"))[:5]},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, fake := newTestConn(tt.frames...)
+
+			message, err := conn.ReadMessage()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if string(message) != tt.want {
+				t.Errorf("ReadMessage() = %q, want %q", message, tt.want)
+			}
+			if !bytes.Equal(fake.out.Bytes(), tt.written) {
+				t.Errorf("written = %x, want %x", fake.out.Bytes(), tt.written)
+			}
+		})
+	}
+}
+
+func TestWriteFrames(t *testing.T) {
+	long := bytes.Repeat([]byte("r"), 200)
+
+	tests := []struct {
+		name  string
+		write func(conn *Conn) error
+		want  []byte
+	}{
+		{
+			name:  "text",
+			write: func(conn *Conn) error { return conn.WriteText([]byte("hello")) },
+			want:  serverFrame(opText, []byte("hello")),
+		},
+		{
+			name:  "text with a 64-bit length",
+			write: func(conn *Conn) error { return conn.WriteText(make([]byte, 0x10000)) },
+			want:  serverFrame(opText, make([]byte, 0x10000)),
+		},
+		{
+			name:  "ping",
+			write: func(conn *Conn) error { return conn.Ping() },
+			want:  serverFrame(opPing, nil),
+		},
+		{
+			name:  "close",
+			write: func(conn *Conn) error { return conn.Close(CloseGoingAway, "bye") },
+			want:  serverFrame(opClose, []byte{0x03, 0xe9, 'b', 'y', 'e'}),
+		},
+		{
+			name:  "close with a long reason",
+			write: func(conn *Conn) error { return conn.Close(CloseNormal, string(long)) },
+			want:  serverFrame(opClose, append([]byte{0x03, 0xe8}, long[:maxControlSize-2]...)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, fake := newTestConn()
+			if err := tt.write(conn); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			if !bytes.Equal(fake.out.Bytes(), tt.want) {
+				t.Errorf("written = %x, want %x", fake.out.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// The example of RFC 6455, section 1.3.
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("acceptKey() = %q", got)
+	}
+}
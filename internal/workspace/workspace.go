@@ -0,0 +1,58 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+var ErrNotFound = errors.New("workspace not found")
+
+// Workspace is the tenant a request operates in. The zero value is the
+// user's personal workspace.
+type Workspace struct {
+	OrganizationID int
+	Role           string
+}
+
+func (workspace Workspace) IsPersonal() bool {
+	return workspace.OrganizationID == 0
+}
+
+// CanWrite reports whether the user may change data in the workspace.
+func (workspace Workspace) CanWrite() bool {
+	return workspace.IsPersonal() || workspace.Role != RoleViewer
+}
+
+// ContextKey is the key the workspace is stored under, both in fiber locals
+// and in the request context handed to usecases and repositories.
+type ContextKey struct{}
+
+func NewContext(ctx context.Context, workspace Workspace) context.Context {
+	return context.WithValue(ctx, ContextKey{}, workspace)
+}
+
+// FromContext returns the workspace stored in ctx, or the personal
+// workspace when there is none.
+func FromContext(ctx context.Context) Workspace {
+	workspace, _ := ctx.Value(ContextKey{}).(Workspace)
+	return workspace
+}
+
+// Owned returns the condition selecting the rows of table owned by the
+// workspace in ctx: the user's own rows without an organization in the
+// personal workspace, and every row of the organization otherwise. Tables
+// scoped this way need username and organization_id columns.
+func Owned(ctx context.Context, table string, username string) (string, []any) {
+	workspace := FromContext(ctx)
+	if workspace.IsPersonal() {
+		return table + ".organization_id IS NULL AND " + table + ".username = ?", []any{username}
+	}
+	return table + ".organization_id = ?", []any{workspace.OrganizationID}
+}
@@ -1,25 +1,71 @@
 package main
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"golang-contact-management-restful-api/config"
 	"golang-contact-management-restful-api/internal/database"
+	"golang-contact-management-restful-api/internal/idempotency"
 	"golang-contact-management-restful-api/internal/middleware"
+	"golang-contact-management-restful-api/internal/outbox"
+	"golang-contact-management-restful-api/internal/pubsub"
 	"golang-contact-management-restful-api/internal/server"
 	addressHandler "golang-contact-management-restful-api/modules/address/handler"
 	addressRepository "golang-contact-management-restful-api/modules/address/repository"
 	addressUsecase "golang-contact-management-restful-api/modules/address/usecase"
+	auditHandler "golang-contact-management-restful-api/modules/audit/handler"
+	auditRepository "golang-contact-management-restful-api/modules/audit/repository"
+	auditUsecase "golang-contact-management-restful-api/modules/audit/usecase"
+	companyHandler "golang-contact-management-restful-api/modules/company/handler"
+	companyRepository "golang-contact-management-restful-api/modules/company/repository"
+	companyUsecase "golang-contact-management-restful-api/modules/company/usecase"
 	contactHandler "golang-contact-management-restful-api/modules/contact/handler"
 	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
 	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	contactImportHandler "golang-contact-management-restful-api/modules/contactimport/handler"
+	contactImportRepository "golang-contact-management-restful-api/modules/contactimport/repository"
+	contactImportUsecase "golang-contact-management-restful-api/modules/contactimport/usecase"
+	historyHandler "golang-contact-management-restful-api/modules/history/handler"
+	historyRepository "golang-contact-management-restful-api/modules/history/repository"
+	historyUsecase "golang-contact-management-restful-api/modules/history/usecase"
+	organizationHandler "golang-contact-management-restful-api/modules/organization/handler"
+	organizationRepository "golang-contact-management-restful-api/modules/organization/repository"
+	organizationUsecase "golang-contact-management-restful-api/modules/organization/usecase"
+	relationshipHandler "golang-contact-management-restful-api/modules/relationship/handler"
+	relationshipRepository "golang-contact-management-restful-api/modules/relationship/repository"
+	relationshipUsecase "golang-contact-management-restful-api/modules/relationship/usecase"
+	savedSearchHandler "golang-contact-management-restful-api/modules/savedsearch/handler"
+	savedSearchRepository "golang-contact-management-restful-api/modules/savedsearch/repository"
+	savedSearchUsecase "golang-contact-management-restful-api/modules/savedsearch/usecase"
+	shareHandler "golang-contact-management-restful-api/modules/share/handler"
+	shareRepository "golang-contact-management-restful-api/modules/share/repository"
+	shareUsecase "golang-contact-management-restful-api/modules/share/usecase"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	syncHandler "golang-contact-management-restful-api/modules/sync/handler"
+	syncRepository "golang-contact-management-restful-api/modules/sync/repository"
+	syncUsecase "golang-contact-management-restful-api/modules/sync/usecase"
 	userHandler "golang-contact-management-restful-api/modules/user/handler"
 	userRepository "golang-contact-management-restful-api/modules/user/repository"
 	userUsecase "golang-contact-management-restful-api/modules/user/usecase"
+	webhookHandler "golang-contact-management-restful-api/modules/webhook/handler"
+	webhookRepository "golang-contact-management-restful-api/modules/webhook/repository"
+	webhookUsecase "golang-contact-management-restful-api/modules/webhook/usecase"
 
 	addressEntity "golang-contact-management-restful-api/modules/address/entities"
+	auditEntity "golang-contact-management-restful-api/modules/audit/entities"
+	companyEntity "golang-contact-management-restful-api/modules/company/entities"
 	contactEntity "golang-contact-management-restful-api/modules/contact/entities"
+	contactImportEntity "golang-contact-management-restful-api/modules/contactimport/entities"
+	historyEntity "golang-contact-management-restful-api/modules/history/entities"
+	organizationEntity "golang-contact-management-restful-api/modules/organization/entities"
+	relationshipEntity "golang-contact-management-restful-api/modules/relationship/entities"
+	savedSearchEntity "golang-contact-management-restful-api/modules/savedsearch/entities"
+	shareEntity "golang-contact-management-restful-api/modules/share/entities"
+	syncEntity "golang-contact-management-restful-api/modules/sync/entities"
 	userEntity "golang-contact-management-restful-api/modules/user/entities"
+	webhookEntity "golang-contact-management-restful-api/modules/webhook/entities"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -45,8 +91,26 @@ func main() {
 	log.Info("Running AutoMigrate...")
 	if err := db.Gorm.AutoMigrate(
 		&userEntity.User{},
+		&organizationEntity.Organization{},
+		&organizationEntity.OrganizationMember{},
+		&companyEntity.Company{},
+		&companyEntity.CompanyAddress{},
 		&contactEntity.Contact{},
 		&addressEntity.Address{},
+		&relationshipEntity.ContactRelationship{},
+		&historyEntity.ContactVersion{},
+		&contactImportEntity.ContactImport{},
+		&savedSearchEntity.SavedSearch{},
+		&savedSearchEntity.SavedSearchMember{},
+		&shareEntity.ContactShare{},
+		&syncEntity.Change{},
+		&outbox.Event{},
+		&outbox.Subscription{},
+		&auditEntity.AuditEntry{},
+		&idempotency.Record{},
+		&webhookEntity.Webhook{},
+		&webhookEntity.WebhookDelivery{},
+		&webhookEntity.WebhookAttempt{},
 	); err != nil {
 		log.WithError(err).Fatal("Failed to run auto migration")
 	}
@@ -77,28 +141,148 @@ func main() {
 
 	srv.GetEngine().Use(cors.New(cors.Config{
 		AllowOrigins:     allowOrigins,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Workspace, If-Match, If-None-Match, X-Request-ID, Idempotency-Key",
 		AllowMethods:     "GET, POST, PUT, DELETE, PATCH, OPTIONS",
+		ExposeHeaders:    "ETag, X-Request-ID, Idempotent-Replayed",
 		AllowCredentials: true,
 	}))
+	srv.GetEngine().Use(middleware.RequestInfo())
+	srv.GetEngine().Use("/api/workspaces/:workspace", middleware.WorkspacePath())
+	srv.GetEngine().Use("/api/events", middleware.QueryCredentials())
 
 	uRepo := userRepository.NewUserRepository(db.Gorm)
 	uUC := userUsecase.NewUserUsecase(uRepo, validate)
 	uH := userHandler.NewUserHttpHandler(srv.GetEngine(), uUC)
 
-	cRepo := contactRepository.NewContactRepository(db.Gorm)
-	cUC := contactUsecase.NewContactUsecase(cRepo, validate)
+	// Retried POST requests are answered from the stored response. It must
+	// be registered before the routes.
+	idempotencyStore := idempotency.NewStore(db.Gorm, time.Duration(cfg.Idempotency.TTLHours)*time.Hour)
+	srv.GetEngine().Use("/api", middleware.Idempotency(uRepo, idempotencyStore))
+
+	oRepo := organizationRepository.NewOrganizationRepository(db.Gorm)
+	oUC := organizationUsecase.NewOrganizationUsecase(oRepo, uRepo, validate)
+	oH := organizationHandler.NewOrganizationHttpHandler(srv.GetEngine(), oUC)
+
+	// Members keeps shared saved searches in step with every write that can
+	// change which contacts they match.
+	members := contactRepository.NewMembers(cfg.Search.SimilarityThreshold)
+
+	cRepo := contactRepository.NewContactRepository(db.Gorm, cfg.Search.SimilarityThreshold)
+	cUC := contactUsecase.NewContactUsecase(cRepo, uRepo, validate)
 	cH := contactHandler.NewContactHttpHandler(srv.GetEngine(), cUC)
 
-	hRepo := addressRepository.NewAddressRepository(db.Gorm)
+	hRepo := addressRepository.NewAddressRepository(db.Gorm, members)
 	hUC := addressUsecase.NewAddressUsecase(hRepo, validate)
 	hH := addressHandler.NewAddressHttpHandler(srv.GetEngine(), hUC)
 
+	rRepo := relationshipRepository.NewContactRelationshipRepository(db.Gorm)
+	rUC := relationshipUsecase.NewContactRelationshipUsecase(rRepo, validate)
+	rH := relationshipHandler.NewContactRelationshipHttpHandler(srv.GetEngine(), rUC)
+
+	vRepo := historyRepository.NewContactVersionRepository(db.Gorm, members)
+	vUC := historyUsecase.NewContactVersionUsecase(vRepo, cUC)
+	vH := historyHandler.NewContactVersionHttpHandler(srv.GetEngine(), vUC)
+
+	coRepo := companyRepository.NewCompanyRepository(db.Gorm, members)
+	coUC := companyUsecase.NewCompanyUsecase(coRepo, cUC, validate)
+	coH := companyHandler.NewCompanyHttpHandler(srv.GetEngine(), coUC)
+
+	iRepo := contactImportRepository.NewContactImportRepository(db.Gorm)
+	iUC := contactImportUsecase.NewContactImportUsecase(iRepo, cUC, hUC, validate, func(err error) {
+		log.WithError(err).Error("Failed to import contacts")
+	})
+	iH := contactImportHandler.NewContactImportHttpHandler(srv.GetEngine(), iUC)
+
+	sRepo := savedSearchRepository.NewSavedSearchRepository(db.Gorm, members)
+	sUC := savedSearchUsecase.NewSavedSearchUsecase(sRepo, cUC, validate)
+	sH := savedSearchHandler.NewSavedSearchHttpHandler(srv.GetEngine(), sUC)
+
+	shRepo := shareRepository.NewContactShareRepository(db.Gorm, members)
+	shUC := shareUsecase.NewContactShareUsecase(shRepo, cRepo, sRepo, uRepo, validate)
+	shH := shareHandler.NewContactShareHttpHandler(srv.GetEngine(), shUC)
+
+	// Event streams wait for changes on the broker. Postgres delivers them
+	// to every instance as they commit; in memory they are noticed by
+	// polling the change log.
+	var broker pubsub.Broker
+	if cfg.Events.Broker == "postgres" {
+		broker = pubsub.NewPostgresBroker(context.Background(), db.Gorm, cfg.Database.URL, log, changelog.Channel, outbox.Channel)
+	} else {
+		broker = pubsub.NewMemoryBroker()
+	}
+
+	syRepo := syncRepository.NewChangeRepository(db.Gorm)
+	syUC := syncUsecase.NewSyncUsecase(syRepo, broker, time.Duration(cfg.Sync.RetentionDays)*24*time.Hour)
+	syH := syncHandler.NewSyncHttpHandler(srv.GetEngine(), syUC)
+
+	if cfg.Events.Broker != "postgres" {
+		go syUC.Watch(context.Background(), time.Second)
+	}
+
+	// Domain events are handed to the subscribers registered on the
+	// dispatcher once they commit.
+	dispatcher := outbox.NewDispatcher(db.Gorm, broker)
+
+	auRepo := auditRepository.NewAuditRepository(db.Gorm)
+	auUC := auditUsecase.NewAuditUsecase(auRepo)
+	auH := auditHandler.NewAuditHttpHandler(srv.GetEngine(), auUC)
+	dispatcher.Subscribe("audit", auUC.Record)
+
+	whRepo := webhookRepository.NewWebhookRepository(db.Gorm)
+	whUC := webhookUsecase.NewWebhookUsecase(whRepo, validate)
+	whH := webhookHandler.NewWebhookHttpHandler(srv.GetEngine(), whUC)
+	dispatcher.Subscribe("webhooks", whUC.Enqueue, outbox.ContactEvents...)
+
+	go whUC.Run(context.Background(), func(err error) {
+		log.WithError(err).Error("Failed to deliver webhooks")
+	})
+
+	go dispatcher.Run(context.Background(), time.Second, func(err error) {
+		log.WithError(err).Error("Failed to dispatch domain events")
+	})
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := syUC.Prune(context.Background()); err != nil {
+				log.WithError(err).Error("Failed to prune the change log")
+			}
+			if err := dispatcher.Prune(context.Background(), time.Now().Add(-outbox.Retention)); err != nil {
+				log.WithError(err).Error("Failed to prune the outbox")
+			}
+			if err := idempotencyStore.Prune(context.Background()); err != nil {
+				log.WithError(err).Error("Failed to prune idempotency keys")
+			}
+			if err := iUC.FailStalled(context.Background()); err != nil {
+				log.WithError(err).Error("Failed to mark stalled imports as failed")
+			}
+		}
+	}()
+
 	auth := middleware.RequireAuth(uRepo)
+	workspace := middleware.RequireWorkspace(oRepo)
+
+	server.RegisterUserRoutes(srv.GetEngine().Group("/api"), uH, auth)
+
+	// Groups apply their middleware to every route registered after them
+	// under /api, so authentication runs once per request, and the
+	// workspace is only resolved for the routes registered after it.
+	api := srv.GetEngine().Group("/api", auth)
+	server.RegisterOrganizationRoutes(api, oH)
+	server.RegisterAuditRoutes(api, auH, middleware.RequireAdmin(cfg.Audit.Admins))
 
-	server.RegisterUserRoutes(srv.GetEngine(), uH, auth)
-	server.RegisterContactRoutes(srv.GetEngine(), cH, auth)
-	server.RegisterAddressRoutes(srv.GetEngine(), hH, auth)
+	inWorkspace := api.Group("", workspace)
+	server.RegisterContactRoutes(inWorkspace, cH)
+	server.RegisterAddressRoutes(inWorkspace, hH)
+	server.RegisterContactRelationshipRoutes(inWorkspace, rH)
+	server.RegisterContactVersionRoutes(inWorkspace, vH)
+	server.RegisterCompanyRoutes(inWorkspace, coH)
+	server.RegisterContactImportRoutes(inWorkspace, iH)
+	server.RegisterSavedSearchRoutes(inWorkspace, sH)
+	server.RegisterContactShareRoutes(inWorkspace, shH)
+	server.RegisterSyncRoutes(inWorkspace, syH)
+	server.RegisterWebhookRoutes(inWorkspace, whH)
 
 	log.WithField("port", cfg.Server.Port).Info("Server is running")
 	if err := srv.Start(); err != nil {
@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrAddressNotFound = errors.New("address not found")
+	ErrVersionMismatch = errors.New("address has been modified since it was read")
 )
@@ -1,13 +1,20 @@
 package entities
 
+import "time"
+
 type Address struct {
-	ID         int     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	Street     *string `json:"street,omitempty" gorm:"column:street;size:255"`
-	City       *string `json:"city,omitempty" gorm:"column:city;size:100"`
-	Province   *string `json:"province,omitempty" gorm:"column:province;size:100"`
-	Country    string  `json:"country" gorm:"column:country;size:100;not null"`
-	PostalCode string  `json:"postal_code" gorm:"column:postal_code;size:10;not null"`
-	ContactID  int     `json:"contact_id" gorm:"column:contact_id;not null"`
+	ID         int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Street     *string   `json:"street,omitempty" gorm:"column:street;size:255"`
+	City       *string   `json:"city,omitempty" gorm:"column:city;size:100"`
+	Province   *string   `json:"province,omitempty" gorm:"column:province;size:100"`
+	Country    string    `json:"country" gorm:"column:country;size:100;not null"`
+	PostalCode string    `json:"postal_code" gorm:"column:postal_code;size:10;not null"`
+	ContactID  int       `json:"contact_id" gorm:"column:contact_id;not null"`
+	Version    int       `json:"version" gorm:"column:version;not null;default:1"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at;not null;default:now();autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:updated_at;not null;default:now();autoUpdateTime"`
+	CreatedBy  string    `json:"created_by" gorm:"column:created_by;size:255"`
+	UpdatedBy  string    `json:"updated_by" gorm:"column:updated_by;size:255"`
 }
 
 func (Address) TableName() string {
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"golang-contact-management-restful-api/internal/jsonpatch"
 	"golang-contact-management-restful-api/internal/transport/http"
 	"golang-contact-management-restful-api/modules/address/domain"
 	"golang-contact-management-restful-api/modules/address/models"
@@ -45,11 +46,17 @@ func (handler *addressHandlerHttp) Create(ctx *fiber.Ctx) error {
 
 	response, err := handler.usecase.Create(ctx.Context(), username, contactID, request)
 	if err != nil {
+		if errors.Is(err, domain2.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{
 			Errors: err.Error(),
 		})
 	}
 
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
 	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.AddressResponse]{
 		Data: response,
 	})
@@ -76,18 +83,93 @@ func (handler *addressHandlerHttp) UpdateByID(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Address ID"})
 	}
 
-	response, err := handler.usecase.Update(ctx.Context(), username, contactID, addressID, request)
+	version, ok := http.IfMatch(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: domain.ErrVersionMismatch.Error()})
+	}
+
+	response, err := handler.usecase.Update(ctx.Context(), username, contactID, addressID, version, request)
 	if err != nil {
-		if errors.Is(err, domain.ErrAddressNotFound) {
+		if errors.Is(err, domain2.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrAddressNotFound) || errors.Is(err, domain2.ErrContactNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{
+			Errors: err.Error(),
+		})
+	}
+
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.AddressResponse]{
+		Data: response,
+	})
+}
+
+func (handler *addressHandlerHttp) PatchByID(ctx *fiber.Ctx) error {
+	patch, err := jsonpatch.New(ctx.Get(fiber.HeaderContentType), ctx.Body())
+	if err != nil {
+		return ctx.Status(fiber.StatusUnsupportedMediaType).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	addressID, err := strconv.Atoi(ctx.Params("addressId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Address ID"})
+	}
+
+	version, ok := http.IfMatch(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: domain.ErrVersionMismatch.Error()})
+	}
+
+	response, err := handler.usecase.Patch(ctx.Context(), username, contactID, addressID, version, patch)
+	if err != nil {
+		if errors.Is(err, domain2.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrAddressNotFound) || errors.Is(err, domain2.ErrContactNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{
 				Errors: err.Error(),
 			})
 		}
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{
 			Errors: err.Error(),
 		})
 	}
 
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
 	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.AddressResponse]{
 		Data: response,
 	})
@@ -120,6 +202,12 @@ func (handler *addressHandlerHttp) FindByID(ctx *fiber.Ctx) error {
 			Errors: err.Error(),
 		})
 	}
+
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
+	if http.NotModified(ctx, response.Version) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
 	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.AddressResponse]{
 		Data: response,
 	})
@@ -170,9 +258,24 @@ func (handler *addressHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Address ID"})
 	}
 
-	err = handler.usecase.DeleteByID(ctx.Context(), username, contactID, addressID)
+	version, ok := http.IfMatch(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: domain.ErrVersionMismatch.Error()})
+	}
+
+	err = handler.usecase.DeleteByID(ctx.Context(), username, contactID, addressID, version)
 	if err != nil {
-		if errors.Is(err, domain.ErrAddressNotFound) {
+		if errors.Is(err, domain2.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{
+				Errors: err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrAddressNotFound) || errors.Is(err, domain2.ErrContactNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{
 				Errors: err.Error(),
 			})
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type AddressCreateRequest struct {
 	Street     string `json:"street" validate:"omitempty,min=3,max=255"`
 	City       string `json:"city" validate:"omitempty,min=3,max=100"`
@@ -16,6 +18,17 @@ type AddressUpdateRequest struct {
 	PostalCode string `json:"postal_code" validate:"omitempty,min=3,max=10"`
 }
 
+// AddressPatchDocument is the address as edited by PATCH. The patched
+// result is validated like a new address; optional fields that end up null
+// or empty are cleared.
+type AddressPatchDocument struct {
+	Street     *string `json:"street" validate:"omitempty,min=3,max=255"`
+	City       *string `json:"city" validate:"omitempty,min=3,max=100"`
+	Province   *string `json:"province" validate:"omitempty,min=3,max=100"`
+	Country    string  `json:"country" validate:"required,min=3,max=100"`
+	PostalCode string  `json:"postal_code" validate:"required,min=3,max=10"`
+}
+
 type AddressResponse struct {
 	ID         int    `json:"id"`
 	Street     string `json:"street"`
@@ -23,4 +36,10 @@ type AddressResponse struct {
 	Province   string `json:"province"`
 	Country    string `json:"country"`
 	PostalCode string `json:"postal_code"`
+	Version    int    `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
 }
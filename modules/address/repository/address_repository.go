@@ -7,8 +7,9 @@ import (
 
 type AddressRepository interface {
 	Save(ctx context.Context, username string, contactID int, address entities.Address) (entities.Address, error)
-	UpdateByID(ctx context.Context, username string, contactID int, addressID int, address entities.Address) (entities.Address, error)
+	UpdateByID(ctx context.Context, username string, contactID int, addressID int, version int, address entities.Address) (entities.Address, error)
+	ReplaceByID(ctx context.Context, username string, contactID int, addressID int, version int, address entities.Address) (entities.Address, error)
 	FindByID(ctx context.Context, username string, contactID int, addressID int) (entities.Address, error)
-	DeleteByID(ctx context.Context, username string, contactID int, addressID int) error
+	DeleteByID(ctx context.Context, username string, contactID int, addressID int, version int) error
 	FindAll(ctx context.Context, username string, contactID int) ([]entities.Address, error)
 }
@@ -7,29 +7,96 @@ import (
 	"golang-contact-management-restful-api/modules/address/entities"
 	"golang-contact-management-restful-api/modules/contact/domain"
 	entities2 "golang-contact-management-restful-api/modules/contact/entities"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	historyEntities "golang-contact-management-restful-api/modules/history/entities"
+	"golang-contact-management-restful-api/modules/history/recorder"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	syncEntities "golang-contact-management-restful-api/modules/sync/entities"
 
 	"gorm.io/gorm"
 )
 
 type addressRepositoryImpl struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	members *contactRepository.Members
 }
 
-func NewAddressRepository(db *gorm.DB) AddressRepository {
-	return &addressRepositoryImpl{DB: db}
+func NewAddressRepository(db *gorm.DB, members *contactRepository.Members) AddressRepository {
+	return &addressRepositoryImpl{DB: db, members: members}
 }
 
 func (repository *addressRepositoryImpl) Save(ctx context.Context, username string, contactID int, address entities.Address) (entities.Address, error) {
-	var contact entities2.Contact
-	err := repository.DB.WithContext(ctx).Take(&contact, "username = ? AND id = ?", username, contactID).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return entities.Address{}, domain.ErrContactNotFound
+	var saved entities.Address
+	err := repository.recorded(ctx, username, contactID, historyEntities.ActionAddressCreate, func(repository *addressRepositoryImpl) error {
+		var err error
+		saved, err = repository.save(ctx, username, contactID, address)
+		return err
+	})
+	return saved, err
+}
+
+func (repository *addressRepositoryImpl) UpdateByID(ctx context.Context, username string, contactID int, addressID int, version int, address entities.Address) (entities.Address, error) {
+	var updated entities.Address
+	err := repository.recorded(ctx, username, contactID, historyEntities.ActionAddressUpdate, func(repository *addressRepositoryImpl) error {
+		var err error
+		updated, err = repository.updateByID(ctx, username, contactID, addressID, version, address)
+		return err
+	})
+	return updated, err
+}
+
+// ReplaceByID writes every field of address, clearing the optional ones
+// that are nil. The version works as in UpdateByID.
+func (repository *addressRepositoryImpl) ReplaceByID(ctx context.Context, username string, contactID int, addressID int, version int, address entities.Address) (entities.Address, error) {
+	var updated entities.Address
+	err := repository.recorded(ctx, username, contactID, historyEntities.ActionAddressUpdate, func(repository *addressRepositoryImpl) error {
+		var err error
+		updated, err = repository.replaceByID(ctx, username, contactID, addressID, version, address)
+		return err
+	})
+	return updated, err
+}
+
+func (repository *addressRepositoryImpl) DeleteByID(ctx context.Context, username string, contactID int, addressID int, version int) error {
+	return repository.recorded(ctx, username, contactID, historyEntities.ActionAddressDelete, func(repository *addressRepositoryImpl) error {
+		return repository.deleteByID(ctx, username, contactID, addressID, version)
+	})
+}
+
+// recorded runs a change to the contact's addresses in a transaction that
+// also records it in the contact's history and updates the saved searches
+// the contact matches.
+func (repository *addressRepositoryImpl) recorded(ctx context.Context, username string, contactID int, action string, fn func(repository *addressRepositoryImpl) error) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := recorder.Baseline(tx, username, contactID); err != nil {
+			return err
 		}
+
+		if err := fn(&addressRepositoryImpl{DB: tx, members: repository.members}); err != nil {
+			return err
+		}
+
+		if err := repository.members.Refresh(ctx, tx, contactID); err != nil {
+			return err
+		}
+
+		return recorder.Record(tx, username, contactID, action)
+	})
+}
+
+func (repository *addressRepositoryImpl) save(ctx context.Context, username string, contactID int, address entities.Address) (entities.Address, error) {
+	if err := repository.takeWritableContact(ctx, username, contactID); err != nil {
 		return entities.Address{}, err
 	}
+
 	address.ContactID = contactID
-	if err = repository.DB.WithContext(ctx).Create(&address).Error; err != nil {
+	address.CreatedBy = username
+	address.UpdatedBy = username
+	if err := repository.DB.WithContext(ctx).Create(&address).Error; err != nil {
+		return entities.Address{}, err
+	}
+
+	if err := changelog.Address(repository.DB.WithContext(ctx), contactID, address.ID, syncEntities.ActionCreate); err != nil {
 		return entities.Address{}, err
 	}
 
@@ -37,14 +104,10 @@ func (repository *addressRepositoryImpl) Save(ctx context.Context, username stri
 
 }
 
-func (repository *addressRepositoryImpl) UpdateByID(ctx context.Context, username string, contactID int, addressID int, address entities.Address) (entities.Address, error) {
-	var contact entities2.Contact
-	err := repository.DB.WithContext(ctx).Where("id= ? AND username = ?", contactID, username).
-		Take(&contact).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return entities.Address{}, domain.ErrContactNotFound
-		}
+// updateByID applies the set fields of address. A non-zero version makes the
+// update conditional on the address still being at that version.
+func (repository *addressRepositoryImpl) updateByID(ctx context.Context, username string, contactID int, addressID int, version int, address entities.Address) (entities.Address, error) {
+	if err := repository.takeWritableContact(ctx, username, contactID); err != nil {
 		return entities.Address{}, err
 	}
 
@@ -74,23 +137,55 @@ func (repository *addressRepositoryImpl) UpdateByID(ctx context.Context, usernam
 		return entities.Address{}, nil
 	}
 
-	result := repository.DB.WithContext(ctx).Model(&entities.Address{}).
+	return repository.update(ctx, username, contactID, addressID, version, updateMap)
+}
+
+func (repository *addressRepositoryImpl) replaceByID(ctx context.Context, username string, contactID int, addressID int, version int, address entities.Address) (entities.Address, error) {
+	if err := repository.takeWritableContact(ctx, username, contactID); err != nil {
+		return entities.Address{}, err
+	}
+
+	return repository.update(ctx, username, contactID, addressID, version, map[string]any{
+		"street":      address.Street,
+		"city":        address.City,
+		"province":    address.Province,
+		"country":     address.Country,
+		"postal_code": address.PostalCode,
+	})
+}
+
+// update applies updateMap to the address and returns the updated address.
+func (repository *addressRepositoryImpl) update(ctx context.Context, username string, contactID int, addressID int, version int, updateMap map[string]any) (entities.Address, error) {
+	updateMap["version"] = gorm.Expr("version + 1")
+	updateMap["updated_by"] = username
+
+	writable, writableArgs := contactRepository.WritableBy(ctx, username)
+	db := repository.DB.WithContext(ctx).Model(&entities.Address{}).
 		Where("id = ? AND contact_id = ? AND contact_id IN (?)", addressID, contactID,
-			repository.DB.Model(&entities2.Contact{}).Select("id").Where("username = ?", username),
-		).Updates(updateMap)
+			repository.DB.Model(&entities2.Contact{}).Select("id").Where(writable, writableArgs...),
+		)
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+	result := db.Updates(updateMap)
 
 	if result.Error != nil {
 		return entities.Address{}, result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return entities.Address{}, domain2.ErrAddressNotFound
+		return entities.Address{}, repository.writeError(ctx, contactID, addressID, version)
+	}
+
+	if err := changelog.Address(repository.DB.WithContext(ctx), contactID, addressID, syncEntities.ActionUpdate); err != nil {
+		return entities.Address{}, err
 	}
 
+	readable, readableArgs := contactRepository.ReadableBy(ctx, username)
 	var updated entities.Address
-	if err = repository.DB.WithContext(ctx).
+	if err := repository.DB.WithContext(ctx).
 		Joins("JOIN contacts ON contacts.id = addresses.contact_id").
-		Where("addresses.id = ? AND addresses.contact_id = ? AND contacts.username = ?", addressID, contactID, username).
+		Where("addresses.id = ? AND addresses.contact_id = ?", addressID, contactID).Where(readable, readableArgs...).
 		Take(&updated).Error; err != nil {
 		return entities.Address{}, err
 	}
@@ -99,9 +194,10 @@ func (repository *addressRepositoryImpl) UpdateByID(ctx context.Context, usernam
 }
 
 func (repository *addressRepositoryImpl) FindByID(ctx context.Context, username string, contactID int, addressID int) (entities.Address, error) {
+	readable, args := contactRepository.ReadableBy(ctx, username)
 	var address entities.Address
 	if err := repository.DB.WithContext(ctx).Model(&entities.Address{}).Joins("JOIN contacts ON contacts.id = addresses.contact_id").
-		Where("addresses.id = ? AND addresses.contact_id = ? AND contacts.username = ?", addressID, contactID, username).
+		Where("addresses.id = ? AND addresses.contact_id = ?", addressID, contactID).Where(readable, args...).
 		Take(&address).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entities.Address{}, domain2.ErrAddressNotFound
@@ -111,11 +207,14 @@ func (repository *addressRepositoryImpl) FindByID(ctx context.Context, username
 	return address, nil
 }
 
-func (repository *addressRepositoryImpl) DeleteByID(ctx context.Context, username string, contactID int, addressID int) error {
+func (repository *addressRepositoryImpl) deleteByID(ctx context.Context, username string, contactID int, addressID int, version int) error {
+	if err := repository.takeWritableContact(ctx, username, contactID); err != nil {
+		return err
+	}
+
 	var address entities.Address
 	err := repository.DB.WithContext(ctx).
-		Joins("JOIN contacts ON contacts.id = addresses.contact_id").
-		Where("addresses.id = ? AND addresses.contact_id = ? AND contacts.username = ?", addressID, contactID, username).
+		Where("id = ? AND contact_id = ?", addressID, contactID).
 		First(&address).Error
 
 	if err != nil {
@@ -125,22 +224,27 @@ func (repository *addressRepositoryImpl) DeleteByID(ctx context.Context, usernam
 		return err
 	}
 
-	result := repository.DB.WithContext(ctx).Delete(&entities.Address{}, address.ID)
+	db := repository.DB.WithContext(ctx)
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+	result := db.Delete(&entities.Address{}, address.ID)
 	if result.Error != nil {
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return domain2.ErrAddressNotFound
+		return repository.writeError(ctx, contactID, addressID, version)
 	}
 
-	return nil
+	return changelog.Address(repository.DB.WithContext(ctx), contactID, addressID, syncEntities.ActionDelete)
 }
 
 func (repository *addressRepositoryImpl) FindAll(ctx context.Context, username string, contactID int) ([]entities.Address, error) {
+	readable, args := contactRepository.ReadableBy(ctx, username)
 	var addresses []entities.Address
 	if err := repository.DB.WithContext(ctx).Model(&entities.Address{}).Joins("JOIN contacts ON contacts.id = addresses.contact_id").
-		Where("contacts.id = ? AND contacts.username = ?", contactID, username).Find(&addresses).Error; err != nil {
+		Where("contacts.id = ?", contactID).Where(readable, args...).Find(&addresses).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrContactNotFound
 		}
@@ -150,3 +254,34 @@ func (repository *addressRepositoryImpl) FindAll(ctx context.Context, username s
 	return addresses, nil
 
 }
+
+// takeWritableContact checks that the user may change the contact's
+// addresses, either as its owner or through a read-write share.
+func (repository *addressRepositoryImpl) takeWritableContact(ctx context.Context, username string, contactID int) error {
+	writable, args := contactRepository.WritableBy(ctx, username)
+
+	var contact entities2.Contact
+	err := repository.DB.WithContext(ctx).Where("id = ?", contactID).Where(writable, args...).Take(&contact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return contactRepository.AccessError(ctx, repository.DB, username, contactID)
+	}
+	return err
+}
+
+// writeError explains why a write to an address of a writable contact
+// matched nothing, telling a stale version apart from a missing address.
+func (repository *addressRepositoryImpl) writeError(ctx context.Context, contactID int, addressID int, version int) error {
+	if version == 0 {
+		return domain2.ErrAddressNotFound
+	}
+
+	var count int64
+	if err := repository.DB.WithContext(ctx).Model(&entities.Address{}).
+		Where("id = ? AND contact_id = ?", addressID, contactID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain2.ErrVersionMismatch
+	}
+	return domain2.ErrAddressNotFound
+}
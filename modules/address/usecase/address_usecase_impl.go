@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"golang-contact-management-restful-api/internal/jsonpatch"
 	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/address/domain"
 	"golang-contact-management-restful-api/modules/address/entities"
 	"golang-contact-management-restful-api/modules/address/models"
 	"golang-contact-management-restful-api/modules/address/repository"
@@ -40,18 +43,11 @@ func (usecase *addressUsecaseImpl) Create(ctx context.Context, username string,
 		return models.AddressResponse{}, err
 	}
 
-	return models.AddressResponse{
-		ID:         saved.ID,
-		Street:     http.PointerToString(saved.Street),
-		City:       http.PointerToString(saved.City),
-		Province:   http.PointerToString(saved.Province),
-		Country:    saved.Country,
-		PostalCode: saved.PostalCode,
-	}, nil
+	return ToAddressResponse(saved), nil
 
 }
 
-func (usecase *addressUsecaseImpl) Update(ctx context.Context, username string, contactID int, addressID int, request models.AddressUpdateRequest) (models.AddressResponse, error) {
+func (usecase *addressUsecaseImpl) Update(ctx context.Context, username string, contactID int, addressID int, version int, request models.AddressUpdateRequest) (models.AddressResponse, error) {
 	if err := usecase.validator.Struct(request); err != nil {
 		return models.AddressResponse{}, err
 	}
@@ -64,20 +60,68 @@ func (usecase *addressUsecaseImpl) Update(ctx context.Context, username string,
 		PostalCode: request.PostalCode,
 	}
 
-	updatedAddress, err := usecase.addressRepository.UpdateByID(ctx, username, contactID, addressID, address)
+	updatedAddress, err := usecase.addressRepository.UpdateByID(ctx, username, contactID, addressID, version, address)
 	if err != nil {
 		return models.AddressResponse{}, err
 	}
 
-	return models.AddressResponse{
-		ID:         updatedAddress.ID,
-		Street:     http.PointerToString(updatedAddress.Street),
-		City:       http.PointerToString(updatedAddress.City),
-		Province:   http.PointerToString(updatedAddress.Province),
-		Country:    updatedAddress.Country,
-		PostalCode: updatedAddress.PostalCode,
-	}, nil
+	return ToAddressResponse(updatedAddress), nil
+
+}
+
+// Patch applies a merge patch or JSON patch to the stored address, failing
+// with ErrVersionMismatch if the address changes in the meantime.
+func (usecase *addressUsecaseImpl) Patch(ctx context.Context, username string, contactID int, addressID int, version int, patch jsonpatch.Patch) (models.AddressResponse, error) {
+	current, err := usecase.addressRepository.FindByID(ctx, username, contactID, addressID)
+	if err != nil {
+		return models.AddressResponse{}, err
+	}
+
+	if version == 0 {
+		version = current.Version
+	} else if version != current.Version {
+		return models.AddressResponse{}, domain.ErrVersionMismatch
+	}
+
+	doc, err := json.Marshal(models.AddressPatchDocument{
+		Street:     current.Street,
+		City:       current.City,
+		Province:   current.Province,
+		Country:    current.Country,
+		PostalCode: current.PostalCode,
+	})
+	if err != nil {
+		return models.AddressResponse{}, err
+	}
+
+	patched, err := patch.Apply(doc)
+	if err != nil {
+		return models.AddressResponse{}, err
+	}
 
+	var document models.AddressPatchDocument
+	if err := jsonpatch.Unmarshal(patched, &document); err != nil {
+		return models.AddressResponse{}, err
+	}
+
+	if err := usecase.validator.Struct(document); err != nil {
+		return models.AddressResponse{}, err
+	}
+
+	address := entities.Address{
+		Street:     http.StringToPointerIfNotEmpty(http.PointerToString(document.Street)),
+		City:       http.StringToPointerIfNotEmpty(http.PointerToString(document.City)),
+		Province:   http.StringToPointerIfNotEmpty(http.PointerToString(document.Province)),
+		Country:    document.Country,
+		PostalCode: document.PostalCode,
+	}
+
+	updatedAddress, err := usecase.addressRepository.ReplaceByID(ctx, username, contactID, addressID, version, address)
+	if err != nil {
+		return models.AddressResponse{}, err
+	}
+
+	return ToAddressResponse(updatedAddress), nil
 }
 
 func (usecase *addressUsecaseImpl) FindByID(ctx context.Context, username string, contactID int, addressID int) (models.AddressResponse, error) {
@@ -86,14 +130,7 @@ func (usecase *addressUsecaseImpl) FindByID(ctx context.Context, username string
 		return models.AddressResponse{}, err
 	}
 
-	return models.AddressResponse{
-		ID:         result.ID,
-		Street:     http.PointerToString(result.Street),
-		City:       http.PointerToString(result.City),
-		Province:   http.PointerToString(result.Province),
-		Country:    result.Country,
-		PostalCode: result.PostalCode,
-	}, nil
+	return ToAddressResponse(result), nil
 }
 
 func (usecase *addressUsecaseImpl) FindAll(ctx context.Context, username string, contactID int) ([]models.AddressResponse, error) {
@@ -104,19 +141,29 @@ func (usecase *addressUsecaseImpl) FindAll(ctx context.Context, username string,
 
 	responses := make([]models.AddressResponse, len(results))
 	for i, result := range results {
-		responses[i] = models.AddressResponse{
-			ID:         result.ID,
-			Street:     http.PointerToString(result.Street),
-			City:       http.PointerToString(result.City),
-			Province:   http.PointerToString(result.Province),
-			Country:    result.Country,
-			PostalCode: result.PostalCode,
-		}
+		responses[i] = ToAddressResponse(result)
 	}
 
 	return responses, nil
 }
 
-func (usecase *addressUsecaseImpl) DeleteByID(ctx context.Context, username string, contactID int, addressID int) error {
-	return usecase.addressRepository.DeleteByID(ctx, username, contactID, addressID)
+func (usecase *addressUsecaseImpl) DeleteByID(ctx context.Context, username string, contactID int, addressID int, version int) error {
+	return usecase.addressRepository.DeleteByID(ctx, username, contactID, addressID, version)
+}
+
+// ToAddressResponse returns the representation of address.
+func ToAddressResponse(address entities.Address) models.AddressResponse {
+	return models.AddressResponse{
+		ID:         address.ID,
+		Street:     http.PointerToString(address.Street),
+		City:       http.PointerToString(address.City),
+		Province:   http.PointerToString(address.Province),
+		Country:    address.Country,
+		PostalCode: address.PostalCode,
+		CreatedAt:  address.CreatedAt,
+		UpdatedAt:  address.UpdatedAt,
+		CreatedBy:  address.CreatedBy,
+		UpdatedBy:  address.UpdatedBy,
+		Version:    address.Version,
+	}
 }
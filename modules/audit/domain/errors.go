@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	ErrInvalidTimeRange = errors.New("from must be before to")
+)
@@ -0,0 +1,121 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	EntityContact = "contact"
+	EntityAddress = "address"
+	EntityUser    = "user"
+)
+
+// FieldChange records one field before and after a change.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// AuditEntry is an entry of the append-only audit log, recorded from the
+// domain event EventID. Snapshot is the entity's state after the change,
+// which the next change of the entity is compared with. Hash covers the
+// entry and PrevHash, the hash of the entry before it, so altering or
+// removing an entry breaks the chain from there on.
+type AuditEntry struct {
+	ID             int64          `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	EventID        int64          `json:"event_id" gorm:"column:event_id;not null;uniqueIndex"`
+	Action         string         `json:"action" gorm:"column:action;size:64;not null;index"`
+	Actor          string         `json:"actor" gorm:"column:actor;size:255;not null;index"`
+	Username       string         `json:"username" gorm:"column:username;size:255;not null;index"`
+	OrganizationID *int           `json:"organization_id,omitempty" gorm:"column:organization_id"`
+	EntityType     string         `json:"entity_type" gorm:"column:entity_type;size:32;not null;index:idx_audit_log_entity,priority:1"`
+	EntityID       string         `json:"entity_id" gorm:"column:entity_id;size:255;not null;index:idx_audit_log_entity,priority:2"`
+	IP             string         `json:"ip" gorm:"column:ip;size:64;not null"`
+	UserAgent      string         `json:"user_agent" gorm:"column:user_agent;size:512;not null"`
+	RequestID      string         `json:"request_id" gorm:"column:request_id;size:128;not null;index"`
+	Changes        []FieldChange  `json:"changes" gorm:"column:changes;type:jsonb;serializer:json;not null"`
+	Snapshot       map[string]any `json:"-" gorm:"column:snapshot;type:jsonb;serializer:json"`
+	PrevHash       string         `json:"prev_hash" gorm:"column:prev_hash;size:64;not null"`
+	Hash           string         `json:"hash" gorm:"column:hash;size:64;not null"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"column:created_at;not null;index"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+// ComputeHash returns the SHA-256, in hex, of PrevHash followed by the JSON
+// encoding of every other field but the id. Values read back from the
+// database encode the same way, so the hash can be recomputed to verify the
+// chain.
+func (entry AuditEntry) ComputeHash() string {
+	content, _ := json.Marshal(struct {
+		EventID        int64          `json:"event_id"`
+		Action         string         `json:"action"`
+		Actor          string         `json:"actor"`
+		Username       string         `json:"username"`
+		OrganizationID *int           `json:"organization_id"`
+		EntityType     string         `json:"entity_type"`
+		EntityID       string         `json:"entity_id"`
+		IP             string         `json:"ip"`
+		UserAgent      string         `json:"user_agent"`
+		RequestID      string         `json:"request_id"`
+		Changes        []FieldChange  `json:"changes"`
+		Snapshot       map[string]any `json:"snapshot"`
+		CreatedAt      string         `json:"created_at"`
+	}{
+		EventID:        entry.EventID,
+		Action:         entry.Action,
+		Actor:          entry.Actor,
+		Username:       entry.Username,
+		OrganizationID: entry.OrganizationID,
+		EntityType:     entry.EntityType,
+		EntityID:       entry.EntityID,
+		IP:             entry.IP,
+		UserAgent:      entry.UserAgent,
+		RequestID:      entry.RequestID,
+		Changes:        entry.Changes,
+		Snapshot:       entry.Snapshot,
+		CreatedAt:      entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	sum := sha256.Sum256(append([]byte(entry.PrevHash), content...))
+	return hex.EncodeToString(sum[:])
+}
+
+// ignoredFields change with every write and are left out of diffs.
+var ignoredFields = map[string]bool{"version": true, "updated_at": true, "updated_by": true}
+
+// Diff lists the fields that differ between two states of an entity, in
+// name order; a nil state stands for an entity that does not exist.
+func Diff(before map[string]any, after map[string]any) []FieldChange {
+	fields := map[string]bool{}
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		if !ignoredFields[field] {
+			names = append(names, field)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, field := range names {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, FieldChange{Field: field, Before: before[field], After: after[field]})
+		}
+	}
+	return changes
+}
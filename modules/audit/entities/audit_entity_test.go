@@ -0,0 +1,164 @@
+package entities
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before map[string]any
+		after  map[string]any
+		want   []FieldChange
+	}{
+		{
+			name:   "created",
+			before: nil,
+			after:  map[string]any{"first_name": "Ann", "email": "ann@example.com"},
+			want: []FieldChange{
+				{Field: "email", Before: nil, After: "ann@example.com"},
+				{Field: "first_name", Before: nil, After: "Ann"},
+			},
+		},
+		{
+			name:   "deleted",
+			before: map[string]any{"first_name": "Ann"},
+			after:  nil,
+			want:   []FieldChange{{Field: "first_name", Before: "Ann", After: nil}},
+		},
+		{
+			name:   "updated",
+			before: map[string]any{"first_name": "Ann", "last_name": "Lee", "phone": "1"},
+			after:  map[string]any{"first_name": "Anne", "last_name": "Lee"},
+			want: []FieldChange{
+				{Field: "first_name", Before: "Ann", After: "Anne"},
+				{Field: "phone", Before: "1", After: nil},
+			},
+		},
+		{
+			name:   "bookkeeping fields are ignored",
+			before: map[string]any{"version": float64(1), "updated_at": "2026-01-01T00:00:00Z", "updated_by": "ann"},
+			after:  map[string]any{"version": float64(2), "updated_at": "2026-01-02T00:00:00Z", "updated_by": "bob"},
+			want:   []FieldChange{},
+		},
+		{
+			name:   "nested values are compared deeply",
+			before: map[string]any{"tags": []any{"a", "b"}, "meta": map[string]any{"x": float64(1)}},
+			after:  map[string]any{"tags": []any{"a", "b"}, "meta": map[string]any{"x": float64(2)}},
+			want: []FieldChange{
+				{Field: "meta", Before: map[string]any{"x": float64(1)}, After: map[string]any{"x": float64(2)}},
+			},
+		},
+		{
+			name:   "unchanged",
+			before: map[string]any{"first_name": "Ann"},
+			after:  map[string]any{"first_name": "Ann"},
+			want:   []FieldChange{},
+		},
+		{
+			name: "both missing",
+			want: []FieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testEntry() AuditEntry {
+	organizationID := 7
+	return AuditEntry{
+		ID:             42,
+		EventID:        1001,
+		Action:         "contact.updated",
+		Actor:          "ann",
+		Username:       "ann",
+		OrganizationID: &organizationID,
+		EntityType:     EntityContact,
+		EntityID:       "12",
+		IP:             "203.0.113.9",
+		UserAgent:      "curl/8.0",
+		RequestID:      "req-1",
+		Changes:        []FieldChange{{Field: "first_name", Before: "Ann", After: "Anne"}},
+		Snapshot:       map[string]any{"first_name": "Anne", "id": float64(12)},
+		PrevHash:       "0000000000000000000000000000000000000000000000000000000000000000",
+		CreatedAt:      time.Date(2026, 10, 1, 12, 30, 0, 123456000, time.UTC),
+	}
+}
+
+func TestComputeHash(t *testing.T) {
+	base := testEntry()
+	hash := base.ComputeHash()
+
+	// The hash of stored entries must not change, or their chain could no
+	// longer be verified.
+	if want := "7626cbf55911f54e11c7ab9973ea6b6b1b5c9f43c90b481c1d1fc7de46da2e41"; hash != want {
+		t.Errorf("ComputeHash() = %s, want %s", hash, want)
+	}
+
+	tests := []struct {
+		name   string
+		change func(entry *AuditEntry)
+		same   bool
+	}{
+		{name: "id", change: func(entry *AuditEntry) { entry.ID = 43 }, same: true},
+		{name: "stored hash", change: func(entry *AuditEntry) { entry.Hash = "x" }, same: true},
+		{name: "time zone", change: func(entry *AuditEntry) {
+			entry.CreatedAt = entry.CreatedAt.In(time.FixedZone("WIB", 7*60*60))
+		}, same: true},
+		{name: "previous hash", change: func(entry *AuditEntry) { entry.PrevHash = "1" + entry.PrevHash[1:] }},
+		{name: "event", change: func(entry *AuditEntry) { entry.EventID++ }},
+		{name: "action", change: func(entry *AuditEntry) { entry.Action = "contact.deleted" }},
+		{name: "actor", change: func(entry *AuditEntry) { entry.Actor = "bob" }},
+		{name: "username", change: func(entry *AuditEntry) { entry.Username = "bob" }},
+		{name: "organization", change: func(entry *AuditEntry) { entry.OrganizationID = nil }},
+		{name: "entity type", change: func(entry *AuditEntry) { entry.EntityType = EntityAddress }},
+		{name: "entity id", change: func(entry *AuditEntry) { entry.EntityID = "13" }},
+		{name: "ip", change: func(entry *AuditEntry) { entry.IP = "203.0.113.10" }},
+		{name: "user agent", change: func(entry *AuditEntry) { entry.UserAgent = "curl/8.1" }},
+		{name: "request id", change: func(entry *AuditEntry) { entry.RequestID = "req-2" }},
+		{name: "changes", change: func(entry *AuditEntry) { entry.Changes[0].After = "Annie" }},
+		{name: "snapshot", change: func(entry *AuditEntry) { entry.Snapshot["first_name"] = "Annie" }},
+		{name: "created at", change: func(entry *AuditEntry) { entry.CreatedAt = entry.CreatedAt.Add(time.Microsecond) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := testEntry()
+			tt.change(&entry)
+
+			if got := entry.ComputeHash(); (got == hash) != tt.same {
+				t.Errorf("ComputeHash() = %s, base %s, want same = %v", got, hash, tt.same)
+			}
+		})
+	}
+}
+
+func TestComputeHashSurvivesStorage(t *testing.T) {
+	entry := testEntry()
+	entry.Hash = entry.ComputeHash()
+
+	// Changes and the snapshot are stored as JSON, and read back with
+	// generic values.
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored AuditEntry
+	if err := json.Unmarshal(encoded, &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored.Snapshot = entry.Snapshot
+
+	if got := stored.ComputeHash(); got != entry.Hash {
+		t.Errorf("ComputeHash() after storage = %s, want %s", got, entry.Hash)
+	}
+}
@@ -0,0 +1,10 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type AuditHandler interface {
+	FindCurrent(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	Export(ctx *fiber.Ctx) error
+	Verify(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/audit/domain"
+	"golang-contact-management-restful-api/modules/audit/models"
+	"golang-contact-management-restful-api/modules/audit/usecase"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const exportFlushInterval = 500
+
+// exportErrorID marks the line, in place of an entry id, that ends an
+// export which failed halfway.
+const exportErrorID = "#error"
+
+var exportHeader = []string{
+	"id", "created_at", "action", "actor", "username", "organization_id", "entity_type", "entity_id",
+	"ip", "user_agent", "request_id", "changes", "prev_hash", "hash",
+}
+
+type auditHandlerHttp struct {
+	app     *fiber.App
+	usecase usecase.AuditUsecase
+}
+
+func NewAuditHttpHandler(app *fiber.App, usecase usecase.AuditUsecase) AuditHandler {
+	return &auditHandlerHttp{
+		app:     app,
+		usecase: usecase,
+	}
+}
+
+func (handler *auditHandlerHttp) FindCurrent(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	query, err := auditQuery(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	results, paging, err := handler.usecase.FindCurrent(ctx.Context(), username, query)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(struct {
+		Data   []models.AuditResponse `json:"data"`
+		Paging contactModels.Paging   `json:"paging"`
+	}{
+		Data:   results,
+		Paging: paging,
+	})
+}
+
+func (handler *auditHandlerHttp) FindAll(ctx *fiber.Ctx) error {
+	query, err := auditQuery(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+	query.Actor = ctx.Query("actor")
+	query.Username = ctx.Query("username")
+
+	results, paging, err := handler.usecase.FindAll(ctx.Context(), query)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(struct {
+		Data   []models.AuditResponse `json:"data"`
+		Paging contactModels.Paging   `json:"paging"`
+	}{
+		Data:   results,
+		Paging: paging,
+	})
+}
+
+// Export streams the entries matching the same filters as FindAll as CSV,
+// oldest first, with the changes as a JSON column.
+func (handler *auditHandlerHttp) Export(ctx *fiber.Ctx) error {
+	query, err := auditQuery(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+	query.Actor = strings.Clone(ctx.Query("actor"))
+	query.Username = strings.Clone(ctx.Query("username"))
+
+	// The export outlives the handler, so it must not touch the fiber
+	// context or the request-scoped context.
+	err = http.Stream(ctx, context.Background(), func(exportCtx context.Context, yield func(entry models.AuditResponse) error) error {
+		return handler.usecase.Export(exportCtx, query, yield)
+	}, func(w *bufio.Writer) http.StreamWriter[models.AuditResponse] {
+		return &auditExportWriter{w: w, csv: csv.NewWriter(w)}
+	})
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="audit.csv"`)
+	return nil
+}
+
+func (handler *auditHandlerHttp) Verify(ctx *fiber.Ctx) error {
+	response, err := handler.usecase.Verify(ctx.Context())
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.AuditVerifyResponse]{
+		Data: response,
+	})
+}
+
+func (handler *auditHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidTimeRange):
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusInternalServerError).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
+
+// auditQuery reads the filters shared by the audit endpoints. Timestamps
+// are RFC 3339 or dates; from is inclusive and to exclusive. Values are
+// copied, since the export reads them after the request is done.
+func auditQuery(ctx *fiber.Ctx) (models.AuditQuery, error) {
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	size, _ := strconv.Atoi(ctx.Query("size", "10"))
+
+	query := models.AuditQuery{
+		Action:     strings.Clone(ctx.Query("action")),
+		EntityType: strings.Clone(ctx.Query("entity_type")),
+		EntityID:   strings.Clone(ctx.Query("entity_id")),
+		RequestID:  strings.Clone(ctx.Query("request_id")),
+		Page:       page,
+		Size:       size,
+	}
+
+	params := []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"from", &query.From},
+		{"to", &query.To},
+	}
+
+	for _, param := range params {
+		value := strings.TrimSpace(ctx.Query(param.name))
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			if parsed, err = time.Parse(time.DateOnly, value); err != nil {
+				return models.AuditQuery{}, fmt.Errorf("%w for %s: %q, expected an RFC 3339 timestamp or a date", domain.ErrInvalidTimestamp, param.name, value)
+			}
+		}
+		*param.dst = parsed
+	}
+
+	return query, nil
+}
+
+// auditExportWriter writes the audit log as CSV. An export failing halfway
+// ends with a line holding exportErrorID and the error.
+type auditExportWriter struct {
+	w             *bufio.Writer
+	csv           *csv.Writer
+	headerWritten bool
+	written       int
+}
+
+func (writer *auditExportWriter) Write(entry models.AuditResponse) error {
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	if err := writer.csv.Write(exportRow(entry)); err != nil {
+		return err
+	}
+
+	writer.written++
+	if writer.written%exportFlushInterval == 0 {
+		return writer.flush()
+	}
+	return nil
+}
+
+func (writer *auditExportWriter) Fail(err error) error {
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	if err := writer.csv.Write([]string{exportErrorID, err.Error()}); err != nil {
+		return err
+	}
+	return writer.flush()
+}
+
+func (writer *auditExportWriter) Close() error {
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	return writer.flush()
+}
+
+func (writer *auditExportWriter) writeHeader() error {
+	if writer.headerWritten {
+		return nil
+	}
+	writer.headerWritten = true
+	return writer.csv.Write(exportHeader)
+}
+
+func (writer *auditExportWriter) flush() error {
+	writer.csv.Flush()
+	if err := writer.csv.Error(); err != nil {
+		return err
+	}
+	return writer.w.Flush()
+}
+
+func exportRow(entry models.AuditResponse) []string {
+	changes, _ := json.Marshal(entry.Changes)
+
+	organizationID := ""
+	if entry.OrganizationID != nil {
+		organizationID = strconv.Itoa(*entry.OrganizationID)
+	}
+
+	return []string{
+		strconv.FormatInt(entry.ID, 10),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.Action,
+		entry.Actor,
+		entry.Username,
+		organizationID,
+		entry.EntityType,
+		entry.EntityID,
+		entry.IP,
+		entry.UserAgent,
+		entry.RequestID,
+		string(changes),
+		entry.PrevHash,
+		entry.Hash,
+	}
+}
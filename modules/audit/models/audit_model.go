@@ -0,0 +1,48 @@
+package models
+
+import (
+	"golang-contact-management-restful-api/modules/audit/entities"
+	"time"
+)
+
+// AuditQuery filters the audit log. Subject limits it to the entries a
+// user performed or that are about the user's own data. Zero values do
+// not filter.
+type AuditQuery struct {
+	Subject    string
+	Actor      string
+	Username   string
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       time.Time
+	To         time.Time
+	Page       int
+	Size       int
+}
+
+type AuditResponse struct {
+	ID             int64                  `json:"id"`
+	Action         string                 `json:"action"`
+	Actor          string                 `json:"actor"`
+	Username       string                 `json:"username"`
+	OrganizationID *int                   `json:"organization_id,omitempty"`
+	EntityType     string                 `json:"entity_type"`
+	EntityID       string                 `json:"entity_id"`
+	IP             string                 `json:"ip,omitempty"`
+	UserAgent      string                 `json:"user_agent,omitempty"`
+	RequestID      string                 `json:"request_id,omitempty"`
+	Changes        []entities.FieldChange `json:"changes"`
+	PrevHash       string                 `json:"prev_hash"`
+	Hash           string                 `json:"hash"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// AuditVerifyResponse is the result of checking the hash chain. BrokenAt
+// is the first entry whose hash does not match.
+type AuditVerifyResponse struct {
+	Valid    bool  `json:"valid"`
+	Checked  int   `json:"checked"`
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
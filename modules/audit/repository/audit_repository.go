@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/audit/entities"
+	"golang-contact-management-restful-api/modules/audit/models"
+)
+
+type AuditRepository interface {
+	Append(ctx context.Context, entry entities.AuditEntry) error
+	FindSnapshot(ctx context.Context, entityType string, entityID string) (map[string]any, error)
+	FindAll(ctx context.Context, query models.AuditQuery) ([]entities.AuditEntry, int, error)
+	Each(ctx context.Context, query models.AuditQuery, fn func(entry entities.AuditEntry) error) error
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/audit/entities"
+	"golang-contact-management-restful-api/modules/audit/models"
+
+	"gorm.io/gorm"
+)
+
+// lockKey names the advisory lock that serialises appends to the audit
+// log, so every entry is chained to the one committed before it.
+const lockKey = 7342003
+
+const eachBatchSize = 500
+
+type auditRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepositoryImpl{DB: db}
+}
+
+// Append chains entry to the last entry of the log and stores it. An entry
+// for an event that is already in the log is skipped, since events may be
+// delivered more than once.
+func (repository *auditRepositoryImpl) Append(ctx context.Context, entry entities.AuditEntry) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entities.AuditEntry{}).Where("event_id = ?", entry.EventID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		var last []entities.AuditEntry
+		if err := tx.Select("hash").Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		entry.PrevHash = ""
+		if len(last) > 0 {
+			entry.PrevHash = last[0].Hash
+		}
+		entry.Hash = entry.ComputeHash()
+
+		return tx.Create(&entry).Error
+	})
+}
+
+// FindSnapshot returns the state of an entity recorded by its latest entry,
+// or nil when there is none or the entity was deleted.
+func (repository *auditRepositoryImpl) FindSnapshot(ctx context.Context, entityType string, entityID string) (map[string]any, error) {
+	var entries []entities.AuditEntry
+	if err := repository.DB.WithContext(ctx).Select("snapshot").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("id DESC").Limit(1).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return entries[0].Snapshot, nil
+}
+
+func (repository *auditRepositoryImpl) FindAll(ctx context.Context, query models.AuditQuery) ([]entities.AuditEntry, int, error) {
+	db := repository.filter(ctx, query)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entities.AuditEntry
+	if err := db.Omit("snapshot").Order("id DESC").
+		Offset((query.Page - 1) * query.Size).Limit(query.Size).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, int(total), nil
+}
+
+// Each calls fn with the entries matching query in the order they were
+// appended, reading them in batches.
+func (repository *auditRepositoryImpl) Each(ctx context.Context, query models.AuditQuery, fn func(entry entities.AuditEntry) error) error {
+	var batch []entities.AuditEntry
+	return repository.filter(ctx, query).FindInBatches(&batch, eachBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, entry := range batch {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (repository *auditRepositoryImpl) filter(ctx context.Context, query models.AuditQuery) *gorm.DB {
+	db := repository.DB.WithContext(ctx).Model(&entities.AuditEntry{})
+
+	if query.Subject != "" {
+		db = db.Where("(actor = ? OR username = ?)", query.Subject, query.Subject)
+	}
+	if query.Actor != "" {
+		db = db.Where("actor = ?", query.Actor)
+	}
+	if query.Username != "" {
+		db = db.Where("username = ?", query.Username)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.EntityType != "" {
+		db = db.Where("entity_type = ?", query.EntityType)
+	}
+	if query.EntityID != "" {
+		db = db.Where("entity_id = ?", query.EntityID)
+	}
+	if query.RequestID != "" {
+		db = db.Where("request_id = ?", query.RequestID)
+	}
+	if !query.From.IsZero() {
+		db = db.Where("created_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		db = db.Where("created_at < ?", query.To)
+	}
+
+	return db
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/outbox"
+	"golang-contact-management-restful-api/modules/audit/models"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+)
+
+type AuditUsecase interface {
+	Record(ctx context.Context, event outbox.Event) error
+	FindCurrent(ctx context.Context, username string, query models.AuditQuery) ([]models.AuditResponse, contactModels.Paging, error)
+	FindAll(ctx context.Context, query models.AuditQuery) ([]models.AuditResponse, contactModels.Paging, error)
+	Export(ctx context.Context, query models.AuditQuery, write func(entry models.AuditResponse) error) error
+	Verify(ctx context.Context) (models.AuditVerifyResponse, error)
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-contact-management-restful-api/internal/outbox"
+	"golang-contact-management-restful-api/modules/audit/domain"
+	"golang-contact-management-restful-api/modules/audit/entities"
+	"golang-contact-management-restful-api/modules/audit/models"
+	"golang-contact-management-restful-api/modules/audit/repository"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"math"
+	"strings"
+	"time"
+)
+
+// errChainBroken stops the walk over the log at the first broken entry.
+var errChainBroken = errors.New("audit chain broken")
+
+type auditUsecaseImpl struct {
+	auditRepository repository.AuditRepository
+}
+
+func NewAuditUsecase(auditRepository repository.AuditRepository) AuditUsecase {
+	return &auditUsecaseImpl{auditRepository: auditRepository}
+}
+
+// Record appends the entry for a domain event. Changes to contacts and
+// addresses are diffed against the state recorded by the entity's previous
+// entry; an update to an entity with no entry yet has no changes.
+func (usecase *auditUsecaseImpl) Record(ctx context.Context, event outbox.Event) error {
+	entityType, operation, _ := strings.Cut(event.Type, ".")
+	info := event.Info()
+
+	entry := entities.AuditEntry{
+		EventID:        event.ID,
+		Action:         event.Type,
+		Actor:          info.Actor,
+		Username:       event.Username,
+		OrganizationID: event.OrganizationID,
+		EntityType:     entityType,
+		EntityID:       event.AggregateID,
+		IP:             info.IP,
+		UserAgent:      truncate(info.UserAgent, 512),
+		RequestID:      info.RequestID,
+		Changes:        []entities.FieldChange{},
+		CreatedAt:      event.CreatedAt.Truncate(time.Microsecond),
+	}
+
+	// Logins and registrations happen before anyone is authenticated; the
+	// user they are about is the one acting.
+	if entityType == entities.EntityUser {
+		if entry.Actor == "" {
+			entry.Actor = event.Username
+		}
+		return usecase.auditRepository.Append(ctx, entry)
+	}
+
+	var state map[string]any
+	if err := json.Unmarshal([]byte(event.Payload), &state); err != nil {
+		return err
+	}
+
+	previous, err := usecase.auditRepository.FindSnapshot(ctx, entityType, event.AggregateID)
+	if err != nil {
+		return err
+	}
+
+	switch operation {
+	case "created":
+		entry.Changes = entities.Diff(nil, state)
+		entry.Snapshot = state
+	case "deleted":
+		if previous == nil {
+			previous = state
+		}
+		entry.Changes = entities.Diff(previous, nil)
+	default:
+		if previous != nil {
+			entry.Changes = entities.Diff(previous, state)
+		}
+		entry.Snapshot = state
+	}
+
+	return usecase.auditRepository.Append(ctx, entry)
+}
+
+// FindCurrent returns the entries the user performed or that are about the
+// user's own data, newest first.
+func (usecase *auditUsecaseImpl) FindCurrent(ctx context.Context, username string, query models.AuditQuery) ([]models.AuditResponse, contactModels.Paging, error) {
+	query.Subject = username
+	return usecase.FindAll(ctx, query)
+}
+
+// FindAll returns the entries matching query, newest first.
+func (usecase *auditUsecaseImpl) FindAll(ctx context.Context, query models.AuditQuery) ([]models.AuditResponse, contactModels.Paging, error) {
+	if err := validateRange(query); err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Size < 1 {
+		query.Size = 10
+	} else if query.Size > 100 {
+		query.Size = 100
+	}
+
+	entries, total, err := usecase.auditRepository.FindAll(ctx, query)
+	if err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	responses := make([]models.AuditResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = toAuditResponse(entry)
+	}
+
+	totalPage := int(math.Ceil(float64(total) / float64(query.Size)))
+	if totalPage == 0 {
+		totalPage = 1
+	}
+
+	return responses, contactModels.Paging{
+		Page:      query.Page,
+		TotalPage: totalPage,
+		TotalItem: total,
+	}, nil
+}
+
+// Export passes every entry matching query to write, oldest first.
+func (usecase *auditUsecaseImpl) Export(ctx context.Context, query models.AuditQuery, write func(entry models.AuditResponse) error) error {
+	if err := validateRange(query); err != nil {
+		return err
+	}
+
+	return usecase.auditRepository.Each(ctx, query, func(entry entities.AuditEntry) error {
+		return write(toAuditResponse(entry))
+	})
+}
+
+// Verify walks the whole log and checks that every entry is chained to the
+// one before it and still has the hash it was stored with.
+func (usecase *auditUsecaseImpl) Verify(ctx context.Context) (models.AuditVerifyResponse, error) {
+	response := models.AuditVerifyResponse{Valid: true}
+	previous := ""
+
+	err := usecase.auditRepository.Each(ctx, models.AuditQuery{}, func(entry entities.AuditEntry) error {
+		response.Checked++
+		if entry.PrevHash != previous || entry.ComputeHash() != entry.Hash {
+			response.Valid = false
+			response.BrokenAt = entry.ID
+			return errChainBroken
+		}
+		previous = entry.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		return models.AuditVerifyResponse{}, err
+	}
+
+	return response, nil
+}
+
+func validateRange(query models.AuditQuery) error {
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return domain.ErrInvalidTimeRange
+	}
+	return nil
+}
+
+func toAuditResponse(entry entities.AuditEntry) models.AuditResponse {
+	return models.AuditResponse{
+		ID:             entry.ID,
+		Action:         entry.Action,
+		Actor:          entry.Actor,
+		Username:       entry.Username,
+		OrganizationID: entry.OrganizationID,
+		EntityType:     entry.EntityType,
+		EntityID:       entry.EntityID,
+		IP:             entry.IP,
+		UserAgent:      entry.UserAgent,
+		RequestID:      entry.RequestID,
+		Changes:        entry.Changes,
+		PrevHash:       entry.PrevHash,
+		Hash:           entry.Hash,
+		CreatedAt:      entry.CreatedAt,
+	}
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return strings.ToValidUTF8(value[:length], "")
+}
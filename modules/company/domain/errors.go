@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrCompanyNotFound = errors.New("company not found")
+	ErrCompanyReadOnly = errors.New("you only have read access to this workspace")
+)
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+type Company struct {
+	ID             int              `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name           string           `json:"name" gorm:"column:name;size:100;not null"`
+	Domain         *string          `json:"domain,omitempty" gorm:"column:domain;size:255"`
+	Industry       *string          `json:"industry,omitempty" gorm:"column:industry;size:100"`
+	Website        *string          `json:"website,omitempty" gorm:"column:website;size:255"`
+	Username       string           `json:"username" gorm:"column:username;size:255;not null;index"`
+	OrganizationID *int             `json:"organization_id,omitempty" gorm:"column:organization_id;index"`
+	CreatedAt      time.Time        `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	Addresses      []CompanyAddress `json:"addresses" gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+}
+
+func (Company) TableName() string {
+	return "companies"
+}
+
+// CompanyAddress has the same shape as a contact address.
+type CompanyAddress struct {
+	ID         int     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Street     *string `json:"street,omitempty" gorm:"column:street;size:255"`
+	City       *string `json:"city,omitempty" gorm:"column:city;size:100"`
+	Province   *string `json:"province,omitempty" gorm:"column:province;size:100"`
+	Country    string  `json:"country" gorm:"column:country;size:100;not null"`
+	PostalCode string  `json:"postal_code" gorm:"column:postal_code;size:10;not null"`
+	CompanyID  int     `json:"company_id" gorm:"column:company_id;not null;index"`
+}
+
+func (CompanyAddress) TableName() string {
+	return "company_addresses"
+}
@@ -0,0 +1,12 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type CompanyHandler interface {
+	Create(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+	GetByID(ctx *fiber.Ctx) error
+	UpdateByID(ctx *fiber.Ctx) error
+	DeleteByID(ctx *fiber.Ctx) error
+	Contacts(ctx *fiber.Ctx) error
+}
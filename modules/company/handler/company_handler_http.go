@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/company/domain"
+	"golang-contact-management-restful-api/modules/company/models"
+	"golang-contact-management-restful-api/modules/company/usecase"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type companyHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.CompanyUsecase
+	validate *validator.Validate
+}
+
+func NewCompanyHttpHandler(app *fiber.App, usecase usecase.CompanyUsecase) CompanyHandler {
+	return &companyHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *companyHandlerHttp) Create(ctx *fiber.Ctx) error {
+	var request models.CompanyCreateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Create(ctx.Context(), username, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.CompanyResponse]{
+		Data: response,
+	})
+}
+
+func (handler *companyHandlerHttp) Search(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	size, _ := strconv.Atoi(ctx.Query("size", "10"))
+
+	results, paging, err := handler.usecase.Search(ctx.Context(), username, models.CompanySearchQuery{
+		Name: ctx.Query("name"),
+		Page: page,
+		Size: size,
+	})
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(struct {
+		Data   []models.CompanyResponse `json:"data"`
+		Paging contactModels.Paging     `json:"paging"`
+	}{
+		Data:   results,
+		Paging: paging,
+	})
+}
+
+func (handler *companyHandlerHttp) GetByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("companyId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.FindByID(ctx.Context(), username, id)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.CompanyResponse]{
+		Data: response,
+	})
+}
+
+func (handler *companyHandlerHttp) UpdateByID(ctx *fiber.Ctx) error {
+	var request models.CompanyUpdateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("companyId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.Update(ctx.Context(), username, id, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.CompanyResponse]{
+		Data: response,
+	})
+}
+
+func (handler *companyHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("companyId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	if err := handler.usecase.DeleteByID(ctx.Context(), username, id); err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
+		Data: "OK",
+	})
+}
+
+func (handler *companyHandlerHttp) Contacts(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("companyId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	size, _ := strconv.Atoi(ctx.Query("size", "10"))
+
+	results, paging, err := handler.usecase.SearchContacts(ctx.Context(), username, id, page, size)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(struct {
+		Data   []contactModels.ContactResponse `json:"data"`
+		Paging contactModels.Paging            `json:"paging"`
+	}{
+		Data:   results,
+		Paging: paging,
+	})
+}
+
+func (handler *companyHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrCompanyNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrCompanyReadOnly):
+		return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
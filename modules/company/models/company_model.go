@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	addressModels "golang-contact-management-restful-api/modules/address/models"
+)
+
+type CompanyCreateRequest struct {
+	Name      string                               `json:"name" validate:"required,min=1,max=100"`
+	Domain    string                               `json:"domain" validate:"omitempty,fqdn,max=255"`
+	Industry  string                               `json:"industry" validate:"omitempty,max=100"`
+	Website   string                               `json:"website" validate:"omitempty,url,max=255"`
+	Addresses []addressModels.AddressCreateRequest `json:"addresses" validate:"omitempty,max=20,dive"`
+}
+
+// CompanyUpdateRequest changes the non-empty attributes. Addresses, when
+// present, replace the company's addresses.
+type CompanyUpdateRequest struct {
+	Name      string                                `json:"name" validate:"omitempty,min=1,max=100"`
+	Domain    string                                `json:"domain" validate:"omitempty,fqdn,max=255"`
+	Industry  string                                `json:"industry" validate:"omitempty,max=100"`
+	Website   string                                `json:"website" validate:"omitempty,url,max=255"`
+	Addresses *[]addressModels.AddressCreateRequest `json:"addresses" validate:"omitempty,max=20,dive"`
+}
+
+type CompanyResponse struct {
+	ID        int                             `json:"id"`
+	Name      string                          `json:"name"`
+	Domain    string                          `json:"domain"`
+	Industry  string                          `json:"industry"`
+	Website   string                          `json:"website"`
+	CreatedAt time.Time                       `json:"created_at"`
+	Addresses []addressModels.AddressResponse `json:"addresses"`
+}
+
+type CompanySearchQuery struct {
+	Name string
+	Page int
+	Size int
+}
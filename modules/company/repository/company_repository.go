@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/company/entities"
+	"golang-contact-management-restful-api/modules/company/models"
+)
+
+type CompanyRepository interface {
+	Save(ctx context.Context, username string, company entities.Company) (entities.Company, error)
+	UpdateByID(ctx context.Context, username string, id int, company entities.Company, replaceAddresses bool) (entities.Company, error)
+	FindByID(ctx context.Context, username string, id int) (entities.Company, error)
+	Search(ctx context.Context, username string, query models.CompanySearchQuery) ([]entities.Company, int, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/company/domain"
+	"golang-contact-management-restful-api/modules/company/entities"
+	"golang-contact-management-restful-api/modules/company/models"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type companyRepositoryImpl struct {
+	DB      *gorm.DB
+	members *contactRepository.Members
+}
+
+func NewCompanyRepository(db *gorm.DB, members *contactRepository.Members) CompanyRepository {
+	return &companyRepositoryImpl{DB: db, members: members}
+}
+
+func (repository *companyRepositoryImpl) Save(ctx context.Context, username string, company entities.Company) (entities.Company, error) {
+	current := workspace.FromContext(ctx)
+	if !current.CanWrite() {
+		return entities.Company{}, domain.ErrCompanyReadOnly
+	}
+
+	company.Username = username
+	if !current.IsPersonal() {
+		company.OrganizationID = &current.OrganizationID
+	}
+
+	if err := repository.DB.WithContext(ctx).Create(&company).Error; err != nil {
+		return entities.Company{}, err
+	}
+	return company, nil
+}
+
+func (repository *companyRepositoryImpl) UpdateByID(ctx context.Context, username string, id int, company entities.Company, replaceAddresses bool) (entities.Company, error) {
+	if !workspace.FromContext(ctx).CanWrite() {
+		return entities.Company{}, domain.ErrCompanyReadOnly
+	}
+
+	updateMap := map[string]any{}
+
+	if company.Name != "" {
+		updateMap["name"] = company.Name
+	}
+
+	if company.Domain != nil {
+		updateMap["domain"] = *company.Domain
+	}
+
+	if company.Industry != nil {
+		updateMap["industry"] = *company.Industry
+	}
+
+	if company.Website != nil {
+		updateMap["website"] = *company.Website
+	}
+
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owned, args := workspace.Owned(ctx, "companies", username)
+
+		var existing entities.Company
+		if err := tx.Where("companies.id = ?", id).Where(owned, args...).Take(&existing).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrCompanyNotFound
+			}
+			return err
+		}
+
+		if len(updateMap) > 0 {
+			if err := tx.Model(&existing).Updates(updateMap).Error; err != nil {
+				return err
+			}
+		}
+
+		// Saved searches match contacts by the name of their company.
+		if company.Name != "" && company.Name != existing.Name {
+			contactIDs, err := companyContactIDs(tx, id)
+			if err != nil {
+				return err
+			}
+			if err := repository.members.Refresh(ctx, tx, contactIDs...); err != nil {
+				return err
+			}
+		}
+
+		if !replaceAddresses {
+			return nil
+		}
+
+		if err := tx.Where("company_id = ?", id).Delete(&entities.CompanyAddress{}).Error; err != nil {
+			return err
+		}
+
+		if len(company.Addresses) == 0 {
+			return nil
+		}
+		for i := range company.Addresses {
+			company.Addresses[i].CompanyID = id
+		}
+		return tx.Create(&company.Addresses).Error
+	})
+	if err != nil {
+		return entities.Company{}, err
+	}
+
+	return repository.FindByID(ctx, username, id)
+}
+
+func (repository *companyRepositoryImpl) FindByID(ctx context.Context, username string, id int) (entities.Company, error) {
+	owned, args := workspace.Owned(ctx, "companies", username)
+
+	var company entities.Company
+	if err := repository.DB.WithContext(ctx).Preload("Addresses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("companies.id = ?", id).Where(owned, args...).Take(&company).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Company{}, domain.ErrCompanyNotFound
+		}
+		return entities.Company{}, err
+	}
+	return company, nil
+}
+
+func (repository *companyRepositoryImpl) Search(ctx context.Context, username string, query models.CompanySearchQuery) ([]entities.Company, int, error) {
+	owned, args := workspace.Owned(ctx, "companies", username)
+	db := repository.DB.WithContext(ctx).Model(&entities.Company{}).Where(owned, args...)
+
+	if space := strings.TrimSpace(query.Name); space != "" {
+		like := "%" + space + "%"
+		db = db.Where("companies.name ILIKE ? OR companies.domain ILIKE ?", like, like)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var companies []entities.Company
+	if err := db.Preload("Addresses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Order("companies.name ASC, companies.id ASC").
+		Offset((query.Page - 1) * query.Size).Limit(query.Size).
+		Find(&companies).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return companies, int(total), nil
+}
+
+// DeleteByID deletes the company and its addresses. Its contacts are kept
+// and lose the link through the foreign key, which is recorded as a change
+// to each of them for sync and for the saved searches they match.
+func (repository *companyRepositoryImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	if !workspace.FromContext(ctx).CanWrite() {
+		return domain.ErrCompanyReadOnly
+	}
+
+	owned, args := workspace.Owned(ctx, "companies", username)
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := changelog.CompanyContacts(tx, id); err != nil {
+			return err
+		}
+
+		contactIDs, err := companyContactIDs(tx, id)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("companies.id = ?", id).Where(owned, args...).Delete(&entities.Company{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrCompanyNotFound
+		}
+		return repository.members.Refresh(ctx, tx, contactIDs...)
+	})
+}
+
+// companyContactIDs returns the ids of the contacts linked to a company.
+func companyContactIDs(tx *gorm.DB, id int) ([]int, error) {
+	var contactIDs []int
+	if err := tx.Table("contacts").Where("company_id = ?", id).Order("id").Pluck("id", &contactIDs).Error; err != nil {
+		return nil, err
+	}
+	return contactIDs, nil
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/company/models"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+)
+
+type CompanyUsecase interface {
+	Create(ctx context.Context, username string, request models.CompanyCreateRequest) (models.CompanyResponse, error)
+	Update(ctx context.Context, username string, id int, request models.CompanyUpdateRequest) (models.CompanyResponse, error)
+	FindByID(ctx context.Context, username string, id int) (models.CompanyResponse, error)
+	Search(ctx context.Context, username string, query models.CompanySearchQuery) ([]models.CompanyResponse, contactModels.Paging, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+	SearchContacts(ctx context.Context, username string, id int, page int, size int) ([]contactModels.ContactResponse, contactModels.Paging, error)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/transport/http"
+	addressModels "golang-contact-management-restful-api/modules/address/models"
+	"golang-contact-management-restful-api/modules/company/entities"
+	"golang-contact-management-restful-api/modules/company/models"
+	"golang-contact-management-restful-api/modules/company/repository"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	"math"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type companyUsecaseImpl struct {
+	companyRepository repository.CompanyRepository
+	contactUsecase    contactUsecase.ContactUsecase
+	validator         *validator.Validate
+}
+
+func NewCompanyUsecase(companyRepository repository.CompanyRepository, contactUsecase contactUsecase.ContactUsecase, validator *validator.Validate) CompanyUsecase {
+	return &companyUsecaseImpl{
+		companyRepository: companyRepository,
+		contactUsecase:    contactUsecase,
+		validator:         validator,
+	}
+}
+
+func (usecase *companyUsecaseImpl) Create(ctx context.Context, username string, request models.CompanyCreateRequest) (models.CompanyResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.CompanyResponse{}, err
+	}
+
+	saved, err := usecase.companyRepository.Save(ctx, username, entities.Company{
+		Name:      request.Name,
+		Domain:    http.StringToPointerIfNotEmpty(request.Domain),
+		Industry:  http.StringToPointerIfNotEmpty(request.Industry),
+		Website:   http.StringToPointerIfNotEmpty(request.Website),
+		Addresses: toAddressEntities(request.Addresses),
+	})
+	if err != nil {
+		return models.CompanyResponse{}, err
+	}
+
+	return toCompanyResponse(saved), nil
+}
+
+func (usecase *companyUsecaseImpl) Update(ctx context.Context, username string, id int, request models.CompanyUpdateRequest) (models.CompanyResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.CompanyResponse{}, err
+	}
+
+	company := entities.Company{
+		Name:     request.Name,
+		Domain:   http.StringToPointerIfNotEmpty(request.Domain),
+		Industry: http.StringToPointerIfNotEmpty(request.Industry),
+		Website:  http.StringToPointerIfNotEmpty(request.Website),
+	}
+	if request.Addresses != nil {
+		company.Addresses = toAddressEntities(*request.Addresses)
+	}
+
+	updated, err := usecase.companyRepository.UpdateByID(ctx, username, id, company, request.Addresses != nil)
+	if err != nil {
+		return models.CompanyResponse{}, err
+	}
+
+	return toCompanyResponse(updated), nil
+}
+
+func (usecase *companyUsecaseImpl) FindByID(ctx context.Context, username string, id int) (models.CompanyResponse, error) {
+	company, err := usecase.companyRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.CompanyResponse{}, err
+	}
+	return toCompanyResponse(company), nil
+}
+
+func (usecase *companyUsecaseImpl) Search(ctx context.Context, username string, query models.CompanySearchQuery) ([]models.CompanyResponse, contactModels.Paging, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Size < 1 {
+		query.Size = 10
+	} else if query.Size > 100 {
+		query.Size = 100
+	}
+
+	companies, total, err := usecase.companyRepository.Search(ctx, username, query)
+	if err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	responses := make([]models.CompanyResponse, len(companies))
+	for i, company := range companies {
+		responses[i] = toCompanyResponse(company)
+	}
+
+	totalPage := int(math.Ceil(float64(total) / float64(query.Size)))
+	if totalPage == 0 {
+		totalPage = 1
+	}
+
+	return responses, contactModels.Paging{
+		Page:      query.Page,
+		TotalPage: totalPage,
+		TotalItem: total,
+	}, nil
+}
+
+func (usecase *companyUsecaseImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	return usecase.companyRepository.DeleteByID(ctx, username, id)
+}
+
+// SearchContacts lists the contacts working at the company through the
+// contact usecase, so they are scoped and shaped like any other search.
+func (usecase *companyUsecaseImpl) SearchContacts(ctx context.Context, username string, id int, page int, size int) ([]contactModels.ContactResponse, contactModels.Paging, error) {
+	if _, err := usecase.companyRepository.FindByID(ctx, username, id); err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	return usecase.contactUsecase.Search(ctx, username, contactModels.ContactSearchQuery{
+		CompanyID: id,
+		Page:      page,
+		Size:      size,
+	})
+}
+
+func toAddressEntities(requests []addressModels.AddressCreateRequest) []entities.CompanyAddress {
+	addresses := make([]entities.CompanyAddress, len(requests))
+	for i, request := range requests {
+		addresses[i] = entities.CompanyAddress{
+			Street:     http.StringToPointerIfNotEmpty(request.Street),
+			City:       http.StringToPointerIfNotEmpty(request.City),
+			Province:   http.StringToPointerIfNotEmpty(request.Province),
+			Country:    request.Country,
+			PostalCode: request.PostalCode,
+		}
+	}
+	return addresses
+}
+
+func toCompanyResponse(company entities.Company) models.CompanyResponse {
+	addresses := make([]addressModels.AddressResponse, len(company.Addresses))
+	for i, address := range company.Addresses {
+		addresses[i] = addressModels.AddressResponse{
+			ID:         address.ID,
+			Street:     http.PointerToString(address.Street),
+			City:       http.PointerToString(address.City),
+			Province:   http.PointerToString(address.Province),
+			Country:    address.Country,
+			PostalCode: address.PostalCode,
+		}
+	}
+
+	return models.CompanyResponse{
+		ID:        company.ID,
+		Name:      company.Name,
+		Domain:    http.PointerToString(company.Domain),
+		Industry:  http.PointerToString(company.Industry),
+		Website:   http.PointerToString(company.Website),
+		CreatedAt: company.CreatedAt,
+		Addresses: addresses,
+	}
+}
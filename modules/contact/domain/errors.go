@@ -1,7 +1,42 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrContactNotFound = errors.New("contact not found")
+	ErrContactNotFound         = errors.New("contact not found")
+	ErrContactReadOnly         = errors.New("you only have read access to this contact")
+	ErrUnsupportedExportFormat = errors.New("unsupported export format, expected csv or jsonl")
+	ErrBulkInvalidOperation    = errors.New("invalid bulk operation")
+	ErrInvalidSort             = errors.New("invalid sort field")
+	ErrInvalidCursor           = errors.New("invalid cursor")
+	ErrInvalidFilter           = errors.New("invalid filter")
+	ErrInvalidFields           = errors.New("invalid fields")
+	ErrInvalidInclude          = errors.New("invalid include")
+	ErrInvalidPhone            = errors.New("invalid phone number")
+	ErrDuplicateEmail          = errors.New("a contact with this email already exists")
+	ErrCompanyNotFound         = errors.New("company not found")
+	ErrBulkRolledBack          = errors.New("rolled back because another operation in the atomic batch failed")
+	ErrVersionMismatch         = errors.New("contact has been modified since it was read")
+	ErrInvalidTimestamp        = errors.New("invalid timestamp")
 )
+
+// DuplicateEmailError is returned when per-user email uniqueness is enabled
+// and another contact already uses the email. ContactID is zero when the
+// conflicting contact could not be looked up.
+type DuplicateEmailError struct {
+	ContactID int
+}
+
+func (err *DuplicateEmailError) Error() string {
+	if err.ContactID == 0 {
+		return ErrDuplicateEmail.Error()
+	}
+	return fmt.Sprintf("%s (contact %d)", ErrDuplicateEmail.Error(), err.ContactID)
+}
+
+func (err *DuplicateEmailError) Unwrap() error {
+	return ErrDuplicateEmail
+}
@@ -1,12 +1,26 @@
 package entities
 
+import "time"
+
 type Contact struct {
-	ID        int     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	FirstName string  `json:"first_name" gorm:"column:first_name;size:100;not null"`
-	LastName  *string `json:"last_name,omitempty" gorm:"column:last_name;size:100"`
-	Email     *string `json:"email,omitempty" gorm:"column:email;size:100"`
-	Phone     *string `json:"phone,omitempty" gorm:"column:phone;size:20"`
-	Username  string  `json:"username" gorm:"column:username;size:255;not null"`
+	ID             int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	FirstName      string    `json:"first_name" gorm:"column:first_name;size:100;not null"`
+	LastName       *string   `json:"last_name,omitempty" gorm:"column:last_name;size:100"`
+	Email          *string   `json:"email,omitempty" gorm:"column:email;size:100"`
+	Phone          *string   `json:"phone,omitempty" gorm:"column:phone;size:32"`
+	PhoneE164      *string   `json:"phone_e164,omitempty" gorm:"column:phone_e164;size:16"`
+	CompanyID      *int      `json:"company_id,omitempty" gorm:"column:company_id;index"`
+	JobTitle       *string   `json:"job_title,omitempty" gorm:"column:job_title;size:100"`
+	Department     *string   `json:"department,omitempty" gorm:"column:department;size:100"`
+	Username       string    `json:"username" gorm:"column:username;size:255;not null"`
+	OrganizationID *int      `json:"organization_id,omitempty" gorm:"column:organization_id;index"`
+	Version        int       `json:"version" gorm:"column:version;not null;default:1"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;not null;default:now();autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;not null;default:now();autoUpdateTime"`
+	CreatedBy      string    `json:"created_by" gorm:"column:created_by;size:255"`
+	UpdatedBy      string    `json:"updated_by" gorm:"column:updated_by;size:255"`
+	Rank           float64   `json:"-" gorm:"column:rank;->;-:migration"`
+	Snippet        string    `json:"-" gorm:"column:snippet;->;-:migration"`
 }
 
 func (Contact) TableName() string {
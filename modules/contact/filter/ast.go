@@ -0,0 +1,72 @@
+package filter
+
+// Node is an element of a parsed filter expression.
+type Node interface {
+	node()
+}
+
+type And struct {
+	Left  Node
+	Right Node
+}
+
+type Or struct {
+	Left  Node
+	Right Node
+}
+
+type Not struct {
+	Expr Node
+}
+
+// AddressScope matches contacts having at least one address that satisfies
+// every condition of Expr, e.g. address(country eq "Indonesia" and city eq "Bandung").
+type AddressScope struct {
+	Expr Node
+}
+
+// Comparison applies an operator to a single field. Exists has no values,
+// In has one or more and every other operator has exactly one.
+type Comparison struct {
+	Field    Field
+	Operator string
+	Values   []string
+}
+
+// Field is a whitelisted contact field, or an address field when Address is
+// set. The address relation itself is addressed with an empty Name.
+type Field struct {
+	Name    string
+	Address bool
+}
+
+func (And) node()          {}
+func (Or) node()           {}
+func (Not) node()          {}
+func (AddressScope) node() {}
+func (Comparison) node()   {}
+
+const (
+	OperatorEq         = "eq"
+	OperatorNe         = "ne"
+	OperatorContains   = "contains"
+	OperatorStartsWith = "startswith"
+	OperatorEndsWith   = "endswith"
+	OperatorIn         = "in"
+	OperatorExists     = "exists"
+)
+
+var contactColumns = map[string]string{
+	"first_name": "contacts.first_name",
+	"last_name":  "contacts.last_name",
+	"email":      "contacts.email",
+	"phone":      "contacts.phone",
+}
+
+var addressColumns = map[string]string{
+	"street":      "a.street",
+	"city":        "a.city",
+	"province":    "a.province",
+	"country":     "a.country",
+	"postal_code": "a.postal_code",
+}
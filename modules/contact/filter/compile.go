@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"strings"
+)
+
+// Compile turns a parsed expression into a parameterised SQL condition over
+// the contacts table. Values are always passed as arguments, and column
+// names only ever come from the whitelists, so the result is safe to hand to
+// gorm's Where.
+func Compile(node Node) (string, []any) {
+	c := &compiler{}
+	sql := c.compile(node, false)
+	return sql, c.args
+}
+
+type compiler struct {
+	args []any
+}
+
+func (c *compiler) compile(node Node, inAddress bool) string {
+	switch n := node.(type) {
+	case And:
+		return "(" + c.compile(n.Left, inAddress) + " AND " + c.compile(n.Right, inAddress) + ")"
+	case Or:
+		return "(" + c.compile(n.Left, inAddress) + " OR " + c.compile(n.Right, inAddress) + ")"
+	case Not:
+		return "(NOT " + c.compile(n.Expr, inAddress) + ")"
+	case AddressScope:
+		return "EXISTS (SELECT 1 FROM addresses a WHERE a.contact_id = contacts.id AND " + c.compile(n.Expr, true) + ")"
+	case Comparison:
+		if n.Field.Address && !inAddress {
+			if n.Field.Name == "" {
+				return "EXISTS (SELECT 1 FROM addresses a WHERE a.contact_id = contacts.id)"
+			}
+			return "EXISTS (SELECT 1 FROM addresses a WHERE a.contact_id = contacts.id AND " + c.comparison(n) + ")"
+		}
+		return c.comparison(n)
+	default:
+		return "FALSE"
+	}
+}
+
+// comparison compiles a single condition. Text comparisons are case
+// insensitive, and missing values compare as empty strings so that not
+// behaves as expected on them.
+func (c *compiler) comparison(n Comparison) string {
+	column := contactColumns[n.Field.Name]
+	if n.Field.Address {
+		column = addressColumns[n.Field.Name]
+	}
+	column = "coalesce(" + column + ", '')"
+
+	switch n.Operator {
+	case OperatorEq:
+		c.args = append(c.args, n.Values[0])
+		return "lower(" + column + ") = lower(?)"
+	case OperatorNe:
+		c.args = append(c.args, n.Values[0])
+		return "lower(" + column + ") <> lower(?)"
+	case OperatorContains:
+		c.args = append(c.args, "%"+escapeLike(n.Values[0])+"%")
+		return column + ` ILIKE ? ESCAPE '\'`
+	case OperatorStartsWith:
+		c.args = append(c.args, escapeLike(n.Values[0])+"%")
+		return column + ` ILIKE ? ESCAPE '\'`
+	case OperatorEndsWith:
+		c.args = append(c.args, "%"+escapeLike(n.Values[0]))
+		return column + ` ILIKE ? ESCAPE '\'`
+	case OperatorIn:
+		values := make([]string, len(n.Values))
+		for i, value := range n.Values {
+			values[i] = strings.ToLower(value)
+		}
+		c.args = append(c.args, values)
+		return "lower(" + column + ") IN ?"
+	case OperatorExists:
+		return column + " <> ''"
+	default:
+		return "FALSE"
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
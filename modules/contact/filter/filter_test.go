@@ -0,0 +1,214 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	email := Field{Name: "email"}
+	firstName := Field{Name: "first_name"}
+	country := Field{Name: "country", Address: true}
+	city := Field{Name: "city", Address: true}
+
+	tests := []struct {
+		input string
+		want  Node
+	}{
+		{
+			input: `email eq "a@b.c"`,
+			want:  Comparison{Field: email, Operator: OperatorEq, Values: []string{"a@b.c"}},
+		},
+		{
+			input: `EMAIL NE 'a@b.c'`,
+			want:  Comparison{Field: email, Operator: OperatorNe, Values: []string{"a@b.c"}},
+		},
+		{
+			input: `first_name contains "say \"hi\""`,
+			want:  Comparison{Field: firstName, Operator: OperatorContains, Values: []string{`say "hi"`}},
+		},
+		{
+			input: `phone startswith +62`,
+			want:  Comparison{Field: Field{Name: "phone"}, Operator: OperatorStartsWith, Values: []string{"+62"}},
+		},
+		{
+			input: `email exists`,
+			want:  Comparison{Field: email, Operator: OperatorExists},
+		},
+		{
+			input: `address.country in ("Indonesia", 'Malaysia')`,
+			want:  Comparison{Field: country, Operator: OperatorIn, Values: []string{"Indonesia", "Malaysia"}},
+		},
+		{
+			input: `address exists`,
+			want:  Comparison{Field: Field{Address: true}, Operator: OperatorExists},
+		},
+		{
+			input: `email exists or first_name exists and not address exists`,
+			want: Or{
+				Left: Comparison{Field: email, Operator: OperatorExists},
+				Right: And{
+					Left:  Comparison{Field: firstName, Operator: OperatorExists},
+					Right: Not{Expr: Comparison{Field: Field{Address: true}, Operator: OperatorExists}},
+				},
+			},
+		},
+		{
+			input: `(email exists or first_name exists) and not not email exists`,
+			want: And{
+				Left: Or{
+					Left:  Comparison{Field: email, Operator: OperatorExists},
+					Right: Comparison{Field: firstName, Operator: OperatorExists},
+				},
+				Right: Not{Expr: Not{Expr: Comparison{Field: email, Operator: OperatorExists}}},
+			},
+		},
+		{
+			input: `address(country eq "Indonesia" and city eq "Bandung")`,
+			want: AddressScope{Expr: And{
+				Left:  Comparison{Field: country, Operator: OperatorEq, Values: []string{"Indonesia"}},
+				Right: Comparison{Field: city, Operator: OperatorEq, Values: []string{"Bandung"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		pos     int
+		message string
+	}{
+		{input: ``, pos: 1, message: "filter is empty"},
+		{input: `   `, pos: 1, message: "filter is empty"},
+		{input: `email eq "a`, pos: 10, message: "unterminated string"},
+		{input: `email eq "a" #`, pos: 14, message: "unexpected character"},
+		{input: `nickname eq "a"`, pos: 1, message: `unknown field "nickname"`},
+		{input: `address.planet eq "a"`, pos: 1, message: `unknown address field "planet"`},
+		{input: `email like "a"`, pos: 7, message: `unknown operator "like"`},
+		{input: `email eq`, pos: 9, message: "expected a quoted value"},
+		{input: `email eq "a" email eq "b"`, pos: 14, message: "expected and, or or end of filter"},
+		{input: `email in "a"`, pos: 10, message: `expected "(" after in`},
+		{input: `email in ("a" "b")`, pos: 15, message: "in value list"},
+		{input: `(email exists`, pos: 14, message: `expected ")"`},
+		{input: `address eq "a"`, pos: 9, message: "address only supports the exists operator"},
+		{input: `address(address(city exists))`, pos: 9, message: "address conditions cannot be nested"},
+		{input: `address(email exists)`, pos: 9, message: `unknown address field "email"`},
+		{input: `and email exists`, pos: 1, message: `unknown field "and"`},
+		{input: `) email exists`, pos: 1, message: `expected a field name`},
+		{input: strings.Repeat("(", maxDepth+1) + "email exists" + strings.Repeat(")", maxDepth+1), pos: maxDepth + 1, message: "nested deeper"},
+		{input: strings.Repeat("not ", maxDepth+1) + "email exists", pos: 4*maxDepth + 1, message: "nested deeper"},
+		{input: `email eq "` + strings.Repeat("a", maxFilterLength) + `"`, pos: maxFilterLength, message: "longer than"},
+	}
+
+	for _, tt := range tests {
+		name := tt.input
+		if len(name) > 40 {
+			name = name[:40]
+		}
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+
+			var filterErr *Error
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("Parse() error = %v, want a filter error", err)
+			}
+			if filterErr.Pos != tt.pos || !strings.Contains(filterErr.Message, tt.message) {
+				t.Errorf("Parse() error = %v, want %q at position %d", err, tt.message, tt.pos)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			input:    `email eq "A@B.C"`,
+			wantSQL:  "lower(coalesce(contacts.email, '')) = lower(?)",
+			wantArgs: []any{"A@B.C"},
+		},
+		{
+			input:    `first_name ne "x"`,
+			wantSQL:  "lower(coalesce(contacts.first_name, '')) <> lower(?)",
+			wantArgs: []any{"x"},
+		},
+		{
+			input:    `email contains "50%_off\\"`,
+			wantSQL:  `coalesce(contacts.email, '') ILIKE ? ESCAPE '\'`,
+			wantArgs: []any{`%50\%\_off\\%`},
+		},
+		{
+			input:    `email startswith "a"`,
+			wantSQL:  `coalesce(contacts.email, '') ILIKE ? ESCAPE '\'`,
+			wantArgs: []any{"a%"},
+		},
+		{
+			input:    `email endswith "@acme.com"`,
+			wantSQL:  `coalesce(contacts.email, '') ILIKE ? ESCAPE '\'`,
+			wantArgs: []any{"%@acme.com"},
+		},
+		{
+			input:    `last_name in ("Doe", "SMITH")`,
+			wantSQL:  "lower(coalesce(contacts.last_name, '')) IN ?",
+			wantArgs: []any{[]string{"doe", "smith"}},
+		},
+		{
+			input:   `phone exists`,
+			wantSQL: "coalesce(contacts.phone, '') <> ''",
+		},
+		{
+			input:   `address exists`,
+			wantSQL: "EXISTS (SELECT 1 FROM addresses a WHERE a.contact_id = contacts.id)",
+		},
+		{
+			input:    `address.city eq "Bandung"`,
+			wantSQL:  "EXISTS (SELECT 1 FROM addresses a WHERE a.contact_id = contacts.id AND lower(coalesce(a.city, '')) = lower(?))",
+			wantArgs: []any{"Bandung"},
+		},
+		{
+			input:    `address(country eq "Indonesia" and not city eq "Bandung")`,
+			wantSQL:  "EXISTS (SELECT 1 FROM addresses a WHERE a.contact_id = contacts.id AND (lower(coalesce(a.country, '')) = lower(?) AND (NOT lower(coalesce(a.city, '')) = lower(?))))",
+			wantArgs: []any{"Indonesia", "Bandung"},
+		},
+		{
+			input:    `email exists or first_name eq "a" and last_name eq "b"`,
+			wantSQL:  "(coalesce(contacts.email, '') <> '' OR (lower(coalesce(contacts.first_name, '')) = lower(?) AND lower(coalesce(contacts.last_name, '')) = lower(?)))",
+			wantArgs: []any{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			sql, args := Compile(node)
+			if sql != tt.wantSQL {
+				t.Errorf("Compile() sql = %s, want %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Compile() args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
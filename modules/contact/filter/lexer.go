@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	value string
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// Error reports a syntax or validation problem together with the 1-based
+// character position where it was found.
+type Error struct {
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+func errorAt(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case r == '"' || r == '\'':
+			quote := r
+			var value strings.Builder
+			j := i + 1
+			closed := false
+			for j < len(runes) {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					value.WriteRune(runes[j+1])
+					j += 2
+					continue
+				}
+				if runes[j] == quote {
+					closed = true
+					break
+				}
+				value.WriteRune(runes[j])
+				j++
+			}
+			if !closed {
+				return nil, errorAt(pos, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i : j+1]), pos: pos, value: value.String()})
+			i = j + 1
+		case unicode.IsDigit(r) || r == '+' || r == '-':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			text := string(runes[i:j])
+			tokens = append(tokens, token{kind: tokenNumber, text: text, pos: pos, value: text})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:j]), pos: pos})
+			i = j
+		default:
+			return nil, errorAt(pos, "unexpected character %q", r)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
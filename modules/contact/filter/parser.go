@@ -0,0 +1,289 @@
+package filter
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	maxFilterLength = 2000
+	maxDepth        = 32
+)
+
+// Parse parses a filter expression such as
+//
+//	email endswith "@acme.com" and not address.country eq "Indonesia"
+//
+// Supported operators are eq, ne, contains, startswith, endswith, in (...)
+// and exists, combined with and, or, not and parentheses. Keywords are case
+// insensitive and and binds tighter than or.
+func Parse(input string) (Node, error) {
+	if len([]rune(input)) > maxFilterLength {
+		return nil, errorAt(maxFilterLength, "filter is longer than %d characters", maxFilterLength)
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, errorAt(1, "filter is empty")
+	}
+
+	node, err := p.parseOr(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, errorAt(next.pos, "unexpected %s, expected and, or or end of filter", next.describe())
+	}
+
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	index  int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEOF {
+		p.index++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) enter(pos int) error {
+	p.depth++
+	if p.depth > maxDepth {
+		return errorAt(pos, "filter is nested deeper than %d levels", maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) parseOr(inAddress bool) (Node, error) {
+	left, err := p.parseAnd(inAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd(inAddress)
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd(inAddress bool) (Node, error) {
+	left, err := p.parseNot(inAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot(inAddress)
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot(inAddress bool) (Node, error) {
+	if !p.isKeyword("not") {
+		return p.parsePrimary(inAddress)
+	}
+
+	t := p.next()
+	if err := p.enter(t.pos); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	expr, err := p.parseNot(inAddress)
+	if err != nil {
+		return nil, err
+	}
+	return Not{Expr: expr}, nil
+}
+
+func (p *parser) parsePrimary(inAddress bool) (Node, error) {
+	t := p.peek()
+
+	switch {
+	case t.kind == tokenLParen:
+		p.next()
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+		defer func() { p.depth-- }()
+
+		expr, err := p.parseOr(inAddress)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case t.kind == tokenIdent && strings.EqualFold(t.text, "address") && p.tokens[p.index+1].kind == tokenLParen:
+		if inAddress {
+			return nil, errorAt(t.pos, "address conditions cannot be nested")
+		}
+		p.next()
+		open := p.next()
+		if err := p.enter(open.pos); err != nil {
+			return nil, err
+		}
+		defer func() { p.depth-- }()
+
+		expr, err := p.parseOr(true)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, "\")\" closing address("); err != nil {
+			return nil, err
+		}
+		return AddressScope{Expr: expr}, nil
+	case t.kind == tokenIdent:
+		return p.parseComparison(inAddress)
+	default:
+		return nil, errorAt(t.pos, "unexpected %s, expected a field name, \"not\" or \"(\"", t.describe())
+	}
+}
+
+func (p *parser) parseComparison(inAddress bool) (Node, error) {
+	fieldToken := p.next()
+	field, err := resolveField(fieldToken, inAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	operatorToken := p.next()
+	if operatorToken.kind != tokenIdent {
+		return nil, errorAt(operatorToken.pos, "unexpected %s, expected an operator after %q (%s)", operatorToken.describe(), fieldToken.text, strings.Join(operatorNames(), ", "))
+	}
+
+	operator := strings.ToLower(operatorToken.text)
+	comparison := Comparison{Field: field, Operator: operator}
+
+	if field.Name == "" && operator != OperatorExists {
+		return nil, errorAt(operatorToken.pos, "address only supports the exists operator, use address.<field> or address(...) to compare fields")
+	}
+
+	switch operator {
+	case OperatorExists:
+		return comparison, nil
+	case OperatorIn:
+		if err := p.expect(tokenLParen, "\"(\" after in"); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.parseValue(operator)
+			if err != nil {
+				return nil, err
+			}
+			comparison.Values = append(comparison.Values, value)
+
+			if p.peek().kind == tokenComma {
+				p.next()
+				continue
+			}
+			if err := p.expect(tokenRParen, "\",\" or \")\" in value list"); err != nil {
+				return nil, err
+			}
+			return comparison, nil
+		}
+	case OperatorEq, OperatorNe, OperatorContains, OperatorStartsWith, OperatorEndsWith:
+		value, err := p.parseValue(operator)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []string{value}
+		return comparison, nil
+	default:
+		return nil, errorAt(operatorToken.pos, "unknown operator %q, expected one of %s", operatorToken.text, strings.Join(operatorNames(), ", "))
+	}
+}
+
+func (p *parser) parseValue(operator string) (string, error) {
+	t := p.next()
+	if t.kind != tokenString && t.kind != tokenNumber {
+		return "", errorAt(t.pos, "unexpected %s, expected a quoted value after %q", t.describe(), operator)
+	}
+	return t.value, nil
+}
+
+func (p *parser) expect(kind tokenKind, description string) error {
+	t := p.next()
+	if t.kind != kind {
+		return errorAt(t.pos, "unexpected %s, expected %s", t.describe(), description)
+	}
+	return nil
+}
+
+func resolveField(t token, inAddress bool) (Field, error) {
+	name := strings.ToLower(t.text)
+
+	if name == "address" {
+		if inAddress {
+			return Field{}, errorAt(t.pos, "address conditions cannot be nested")
+		}
+		return Field{Address: true}, nil
+	}
+
+	if strings.HasPrefix(name, "address.") {
+		name = strings.TrimPrefix(name, "address.")
+		if _, ok := addressColumns[name]; !ok {
+			return Field{}, errorAt(t.pos, "unknown address field %q, expected one of %s", name, strings.Join(columnNames(addressColumns), ", "))
+		}
+		return Field{Name: name, Address: true}, nil
+	}
+
+	if inAddress {
+		if _, ok := addressColumns[name]; !ok {
+			return Field{}, errorAt(t.pos, "unknown address field %q, expected one of %s", name, strings.Join(columnNames(addressColumns), ", "))
+		}
+		return Field{Name: name, Address: true}, nil
+	}
+
+	if _, ok := contactColumns[name]; !ok {
+		return Field{}, errorAt(t.pos, "unknown field %q, expected one of %s or address.<field>", name, strings.Join(columnNames(contactColumns), ", "))
+	}
+	return Field{Name: name}, nil
+}
+
+func operatorNames() []string {
+	return []string{OperatorEq, OperatorNe, OperatorContains, OperatorStartsWith, OperatorEndsWith, OperatorIn, OperatorExists}
+}
+
+func columnNames(columns map[string]string) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/contact/models"
+	"strconv"
+)
+
+const exportFlushInterval = 100
+
+// exportErrorID marks the CSV line, in place of a contact id, that ends an
+// export which failed halfway.
+const exportErrorID = "#error"
+
+func newContactExportWriter(format string, includeAddresses bool, w *bufio.Writer) http.StreamWriter[models.ContactExportRow] {
+	if format == models.ExportFormatJSONL {
+		return &jsonlExportWriter{w: w, encoder: json.NewEncoder(w)}
+	}
+	return &csvExportWriter{w: w, csv: csv.NewWriter(w), includeAddresses: includeAddresses}
+}
+
+type jsonlExportWriter struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+	written int
+}
+
+func (writer *jsonlExportWriter) Write(row models.ContactExportRow) error {
+	if err := writer.encoder.Encode(row); err != nil {
+		return err
+	}
+
+	writer.written++
+	if writer.written%exportFlushInterval == 0 {
+		return writer.w.Flush()
+	}
+	return nil
+}
+
+// Fail ends the export with an error object in place of a contact.
+func (writer *jsonlExportWriter) Fail(err error) error {
+	if err := writer.encoder.Encode(http.ErrorResponse{Errors: err.Error()}); err != nil {
+		return err
+	}
+	return writer.w.Flush()
+}
+
+func (writer *jsonlExportWriter) Close() error {
+	return writer.w.Flush()
+}
+
+// csvExportWriter writes one line per contact, or one line per address when
+// addresses are inlined, repeating the contact columns on every line.
+type csvExportWriter struct {
+	w                *bufio.Writer
+	csv              *csv.Writer
+	includeAddresses bool
+	headerWritten    bool
+	written          int
+}
+
+func (writer *csvExportWriter) Write(row models.ContactExportRow) error {
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+
+	contact := []string{
+		strconv.Itoa(row.ID),
+		row.FirstName,
+		row.LastName,
+		row.Email,
+		row.Phone,
+	}
+
+	if !writer.includeAddresses {
+		if err := writer.csv.Write(contact); err != nil {
+			return err
+		}
+	} else if len(row.Addresses) == 0 {
+		if err := writer.csv.Write(append(contact, "", "", "", "", "", "")); err != nil {
+			return err
+		}
+	} else {
+		for _, address := range row.Addresses {
+			line := append(append([]string{}, contact...),
+				strconv.Itoa(address.ID),
+				address.Street,
+				address.City,
+				address.Province,
+				address.Country,
+				address.PostalCode,
+			)
+			if err := writer.csv.Write(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.written++
+	if writer.written%exportFlushInterval == 0 {
+		return writer.flush()
+	}
+	return nil
+}
+
+func (writer *csvExportWriter) Close() error {
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	return writer.flush()
+}
+
+// Fail ends the export with a line holding exportErrorID and the error.
+func (writer *csvExportWriter) Fail(err error) error {
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	if err := writer.csv.Write([]string{exportErrorID, err.Error()}); err != nil {
+		return err
+	}
+	return writer.flush()
+}
+
+func (writer *csvExportWriter) writeHeader() error {
+	if writer.headerWritten {
+		return nil
+	}
+
+	header := []string{"id", "first_name", "last_name", "email", "phone"}
+	if writer.includeAddresses {
+		header = append(header, "address_id", "street", "city", "province", "country", "postal_code")
+	}
+
+	writer.headerWritten = true
+	return writer.csv.Write(header)
+}
+
+func (writer *csvExportWriter) flush() error {
+	writer.csv.Flush()
+	if err := writer.csv.Error(); err != nil {
+		return err
+	}
+	return writer.w.Flush()
+}
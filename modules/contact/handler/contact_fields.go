@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/contact/models"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func readOptions(ctx *fiber.Ctx) models.ContactReadOptions {
+	return models.ContactReadOptions{
+		Fields:  splitList(ctx.Query("fields")),
+		Include: splitList(ctx.Query("include")),
+	}
+}
+
+// timeRange reads the created_since, created_before, updated_since and
+// updated_before parameters, given as RFC 3339 timestamps or dates.
+func timeRange(ctx *fiber.Ctx) (models.ContactTimeRange, error) {
+	var bounds models.ContactTimeRange
+	params := []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"created_since", &bounds.CreatedSince},
+		{"created_before", &bounds.CreatedBefore},
+		{"updated_since", &bounds.UpdatedSince},
+		{"updated_before", &bounds.UpdatedBefore},
+	}
+
+	for _, param := range params {
+		value := strings.TrimSpace(ctx.Query(param.name))
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			if parsed, err = time.Parse(time.DateOnly, value); err != nil {
+				return models.ContactTimeRange{}, fmt.Errorf("%w for %s: %q, expected an RFC 3339 timestamp or a date", domain.ErrInvalidTimestamp, param.name, value)
+			}
+		}
+		*param.dst = parsed
+	}
+
+	return bounds, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sparseContact keeps only the requested attributes. The id and any
+// included relations are always returned.
+func sparseContact(response models.ContactResponse, fields []string) any {
+	if len(fields) == 0 {
+		return response
+	}
+
+	sparse := map[string]any{"id": response.ID}
+	for _, field := range fields {
+		switch field {
+		case "first_name":
+			sparse[field] = response.FirstName
+		case "last_name":
+			sparse[field] = response.LastName
+		case "email":
+			sparse[field] = response.Email
+		case "phone":
+			sparse[field] = response.Phone
+		case "phone_e164":
+			sparse[field] = response.PhoneE164
+		case "company_id":
+			sparse[field] = response.CompanyID
+		case "job_title":
+			sparse[field] = response.JobTitle
+		case "department":
+			sparse[field] = response.Department
+		case "shared_by":
+			sparse[field] = response.SharedBy
+		case "rank":
+			sparse[field] = response.Rank
+		case "snippet":
+			sparse[field] = response.Snippet
+		case "created_at":
+			sparse[field] = response.CreatedAt
+		case "updated_at":
+			sparse[field] = response.UpdatedAt
+		case "created_by":
+			sparse[field] = response.CreatedBy
+		case "updated_by":
+			sparse[field] = response.UpdatedBy
+		}
+	}
+
+	if response.Addresses != nil {
+		sparse[models.ContactIncludeAddresses] = *response.Addresses
+	}
+
+	return sparse
+}
+
+func sparseContacts(responses []models.ContactResponse, fields []string) []any {
+	sparse := make([]any, len(responses))
+	for i, response := range responses {
+		sparse[i] = sparseContact(response, fields)
+	}
+	return sparse
+}
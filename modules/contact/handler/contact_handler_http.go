@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"golang-contact-management-restful-api/internal/jsonpatch"
 	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/internal/workspace"
 	"golang-contact-management-restful-api/modules/contact/domain"
 	"golang-contact-management-restful-api/modules/contact/models"
 	"golang-contact-management-restful-api/modules/contact/usecase"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -39,9 +44,16 @@ func (handler *contactHandlerHttp) Create(ctx *fiber.Ctx) error {
 
 	response, err := handler.usecase.Create(ctx.Context(), username, request)
 	if err != nil {
+		if errors.Is(err, domain.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+		if errors.Is(err, domain.ErrDuplicateEmail) {
+			return conflictResponse(ctx, err)
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
 	}
 
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
 	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.ContactResponse]{
 		Data: response,
 	})
@@ -64,14 +76,76 @@ func (handler *contactHandlerHttp) UpdateByID(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
 	}
 
-	response, err := handler.usecase.Update(ctx.Context(), username, id, request)
+	version, ok := http.IfMatch(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: domain.ErrVersionMismatch.Error()})
+	}
+
+	response, err := handler.usecase.Update(ctx.Context(), username, id, version, request)
+	if err != nil {
+		if errors.Is(err, domain.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+		if errors.Is(err, domain.ErrContactNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+		if errors.Is(err, domain.ErrDuplicateEmail) {
+			return conflictResponse(ctx, err)
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ContactResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactHandlerHttp) PatchByID(ctx *fiber.Ctx) error {
+	patch, err := jsonpatch.New(ctx.Get(fiber.HeaderContentType), ctx.Body())
+	if err != nil {
+		return ctx.Status(fiber.StatusUnsupportedMediaType).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	version, ok := http.IfMatch(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: domain.ErrVersionMismatch.Error()})
+	}
+
+	response, err := handler.usecase.Patch(ctx.Context(), username, id, version, patch)
 	if err != nil {
+		if errors.Is(err, domain.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
 		if errors.Is(err, domain.ErrContactNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
 		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+		if errors.Is(err, domain.ErrDuplicateEmail) {
+			return conflictResponse(ctx, err)
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
 	}
 
+	ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
 	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ContactResponse]{
 		Data: response,
 	})
@@ -86,12 +160,43 @@ func (handler *contactHandlerHttp) Search(ctx *fiber.Ctx) error {
 	page, _ := strconv.Atoi(ctx.Query("page", "1"))
 	size, _ := strconv.Atoi(ctx.Query("size", "10"))
 
+	bounds, err := timeRange(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
 	query := models.ContactSearchQuery{
-		Name:  ctx.Query("name"),
-		Email: ctx.Query("email"),
-		Phone: ctx.Query("phone"),
-		Page:  page,
-		Size:  size,
+		Query:   ctx.Query("q"),
+		Name:    ctx.Query("name"),
+		Email:   ctx.Query("email"),
+		Phone:   ctx.Query("phone"),
+		Company: ctx.Query("company"),
+		Fuzzy:   ctx.QueryBool("fuzzy", false),
+		Filter:  ctx.Query("filter"),
+		Sort:    ctx.Query("sort"),
+		Cursor:  ctx.Query("cursor"),
+		Page:    page,
+		Size:    size,
+
+		ContactTimeRange:   bounds,
+		ContactReadOptions: readOptions(ctx),
+	}
+
+	// Keyset pagination is opted into by sending a cursor parameter, which is
+	// empty for the first page.
+	if ctx.Context().QueryArgs().Has("cursor") {
+		results, paging, err := handler.usecase.SearchCursor(ctx.Context(), username, query)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(struct {
+			Data   []any               `json:"data"`
+			Paging models.CursorPaging `json:"paging"`
+		}{
+			Data:   sparseContacts(results, query.Fields),
+			Paging: paging,
+		})
 	}
 
 	results, paging, err := handler.usecase.Search(ctx.Context(), username, query)
@@ -100,10 +205,10 @@ func (handler *contactHandlerHttp) Search(ctx *fiber.Ctx) error {
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(struct {
-		Data   []models.ContactResponse `json:"data"`
-		Paging models.Paging            `json:"paging"`
+		Data   []any         `json:"data"`
+		Paging models.Paging `json:"paging"`
 	}{
-		Data:   results,
+		Data:   sparseContacts(results, query.Fields),
 		Paging: paging,
 	})
 }
@@ -119,7 +224,8 @@ func (handler *contactHandlerHttp) GetByID(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
 	}
 
-	response, err := handler.usecase.FindByID(ctx.Context(), username, id)
+	options := readOptions(ctx)
+	response, err := handler.usecase.FindByID(ctx.Context(), username, id, options)
 	if err != nil {
 		if errors.Is(err, domain.ErrContactNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
@@ -127,10 +233,34 @@ func (handler *contactHandlerHttp) GetByID(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
 	}
 
-	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ContactResponse]{
-		Data: response,
+	if len(options.Fields) == 0 && len(options.Include) == 0 {
+		ctx.Set(fiber.HeaderETag, http.ETag(response.Version))
+		if http.NotModified(ctx, response.Version) {
+			return ctx.SendStatus(fiber.StatusNotModified)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ContactResponse]{
+			Data: response,
+		})
+	}
+
+	// The version does not cover included relations, so the tag of a
+	// tailored representation is derived from what is sent.
+	body, err := ctx.App().Config().JSONEncoder(http.DataEnvelope[any]{
+		Data: sparseContact(response, options.Fields),
 	})
+	if err != nil {
+		return err
+	}
+
+	tag := http.RepresentationETag(response.Version, body)
+	ctx.Set(fiber.HeaderETag, tag)
+	if http.NoneMatch(ctx, tag) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
 
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return ctx.Status(fiber.StatusOK).Send(body)
 }
 
 func (handler *contactHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
@@ -144,14 +274,146 @@ func (handler *contactHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
 	}
 
-	err = handler.usecase.DeleteByID(ctx.Context(), username, id)
+	version, ok := http.IfMatch(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: domain.ErrVersionMismatch.Error()})
+	}
+
+	err = handler.usecase.DeleteByID(ctx.Context(), username, id, version)
 	if err != nil {
+		if errors.Is(err, domain.ErrContactReadOnly) {
+			return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
 		if errors.Is(err, domain.ErrContactNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
 		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			return ctx.Status(fiber.StatusPreconditionFailed).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
 	}
 	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
 		Data: "OK",
 	})
 }
+
+func (handler *contactHandlerHttp) Export(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	bounds, err := timeRange(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	// The query is cloned as it is read after the request buffers have
+	// been reused.
+	query := models.ContactExportQuery{
+		ContactSearchQuery: models.ContactSearchQuery{
+			Query:   strings.Clone(ctx.Query("q")),
+			Name:    strings.Clone(ctx.Query("name")),
+			Email:   strings.Clone(ctx.Query("email")),
+			Phone:   strings.Clone(ctx.Query("phone")),
+			Company: strings.Clone(ctx.Query("company")),
+			Fuzzy:   ctx.QueryBool("fuzzy", false),
+			Filter:  strings.Clone(ctx.Query("filter")),
+
+			ContactTimeRange: bounds,
+		},
+		Format:           strings.Clone(ctx.Query("format", models.ExportFormatCSV)),
+		IncludeAddresses: ctx.QueryBool("include_addresses", false),
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	switch query.Format {
+	case models.ExportFormatCSV:
+	case models.ExportFormatJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: domain.ErrUnsupportedExportFormat.Error()})
+	}
+
+	// The export outlives the handler, so it must not touch the fiber
+	// context or the request-scoped context. Only the workspace is carried
+	// over.
+	exportCtx := workspace.NewContext(context.Background(), workspace.FromContext(ctx.Context()))
+	err = http.Stream(ctx, exportCtx, func(exportCtx context.Context, yield func(row models.ContactExportRow) error) error {
+		return handler.usecase.Export(exportCtx, username, query, yield)
+	}, func(w *bufio.Writer) http.StreamWriter[models.ContactExportRow] {
+		return newContactExportWriter(query.Format, query.IncludeAddresses, w)
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="contacts.`+query.Format+`"`)
+	return nil
+}
+
+func (handler *contactHandlerHttp) Bulk(ctx *fiber.Ctx) error {
+	var request models.ContactBulkRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+	request.Atomic = request.Atomic || ctx.QueryBool("atomic", false)
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Bulk(ctx.Context(), username, request)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	for i := range response.Results {
+		result := &response.Results[i]
+		result.Status = bulkResultStatus(result.Op, result.Err)
+		if result.Err != nil {
+			result.Error = result.Err.Error()
+		}
+	}
+
+	status := fiber.StatusOK
+	if response.Failed > 0 {
+		status = fiber.StatusMultiStatus
+	}
+
+	return ctx.Status(status).JSON(http.DataEnvelope[models.ContactBulkResponse]{
+		Data: response,
+	})
+}
+
+func bulkResultStatus(op string, err error) int {
+	switch {
+	case err == nil && op == models.BulkOperationCreate:
+		return fiber.StatusCreated
+	case err == nil:
+		return fiber.StatusOK
+	case errors.Is(err, domain.ErrContactNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrContactReadOnly):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrDuplicateEmail):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrBulkRolledBack):
+		return fiber.StatusFailedDependency
+	default:
+		return fiber.StatusBadRequest
+	}
+}
+
+func conflictResponse(ctx *fiber.Ctx, err error) error {
+	response := models.ContactConflictResponse{Errors: err.Error()}
+
+	var duplicate *domain.DuplicateEmailError
+	if errors.As(err, &duplicate) {
+		response.ContactID = duplicate.ContactID
+	}
+
+	return ctx.Status(fiber.StatusConflict).JSON(response)
+}
@@ -1,33 +1,128 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+
+	addressModels "golang-contact-management-restful-api/modules/address/models"
+)
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+
+	BulkOperationCreate = "create"
+	BulkOperationUpdate = "update"
+	BulkOperationDelete = "delete"
+
+	ContactIncludeAddresses = "addresses"
+)
+
 type ContactCreateRequest struct {
 	FirstName string `json:"first_name" validate:"required,min=3,max=100"`
 	LastName  string `json:"last_name" validate:"omitempty,min=3,max=100"`
 	Email     string `json:"email" validate:"omitempty,email,max=100"`
-	Phone     string `json:"phone" validate:"omitempty,min=5,max=20"`
+	Phone     string `json:"phone" validate:"omitempty,max=32"`
+
+	CompanyID  int    `json:"company_id" validate:"omitempty,min=1"`
+	JobTitle   string `json:"job_title" validate:"omitempty,max=100"`
+	Department string `json:"department" validate:"omitempty,max=100"`
 }
 
 type ContactUpdateRequest struct {
 	FirstName string `json:"first_name" validate:"omitempty,min=3,max=100"`
 	LastName  string `json:"last_name" validate:"omitempty,min=3,max=100"`
 	Email     string `json:"email" validate:"omitempty,email,max=100"`
-	Phone     string `json:"phone" validate:"omitempty,min=5,max=20"`
+	Phone     string `json:"phone" validate:"omitempty,max=32"`
+
+	// CompanyID links the contact to a company when positive and unlinks it
+	// when zero; it is left unchanged when omitted.
+	CompanyID  *int   `json:"company_id" validate:"omitempty,gte=0"`
+	JobTitle   string `json:"job_title" validate:"omitempty,max=100"`
+	Department string `json:"department" validate:"omitempty,max=100"`
+}
+
+// ContactPatchDocument is the part of a contact that PATCH edits. The patch
+// is applied to the stored values and the result is validated like a new
+// contact; optional fields that end up null or empty are cleared.
+type ContactPatchDocument struct {
+	FirstName string  `json:"first_name" validate:"required,min=3,max=100"`
+	LastName  *string `json:"last_name" validate:"omitempty,min=3,max=100"`
+	Email     *string `json:"email" validate:"omitempty,email,max=100"`
+	Phone     *string `json:"phone" validate:"omitempty,max=32"`
+
+	CompanyID  *int    `json:"company_id" validate:"omitempty,min=1"`
+	JobTitle   *string `json:"job_title" validate:"omitempty,max=100"`
+	Department *string `json:"department" validate:"omitempty,max=100"`
 }
 
 type ContactResponse struct {
-	ID        int    `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Phone     string `json:"phone"`
+	ID         int     `json:"id"`
+	FirstName  string  `json:"first_name"`
+	LastName   string  `json:"last_name"`
+	Email      string  `json:"email"`
+	Phone      string  `json:"phone"`
+	PhoneE164  string  `json:"phone_e164,omitempty"`
+	CompanyID  int     `json:"company_id,omitempty"`
+	JobTitle   string  `json:"job_title,omitempty"`
+	Department string  `json:"department,omitempty"`
+	SharedBy   string  `json:"shared_by,omitempty"`
+	Rank       float64 `json:"rank,omitempty"`
+	Snippet    string  `json:"snippet,omitempty"`
+	Version    int     `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+
+	Addresses *[]addressModels.AddressResponse `json:"addresses,omitempty"`
+}
+
+// ContactReadOptions shapes contact responses: Fields trims the attributes
+// returned and Include embeds related collections.
+type ContactReadOptions struct {
+	Fields  []string
+	Include []string
+}
+
+type ContactConflictResponse struct {
+	Errors    string `json:"errors"`
+	ContactID int    `json:"contact_id,omitempty"`
 }
 
 type ContactSearchQuery struct {
-	Name  string
-	Email string
-	Phone string
-	Page  int
-	Size  int
+	Query   string
+	Name    string
+	Email   string
+	Phone   string
+	Company string
+	Fuzzy   bool
+	Filter  string
+	Sort    string
+	Cursor  string
+	Page    int
+	Size    int
+
+	// PhoneE164 is the phone parameter normalised with the user's default
+	// region, set by the usecase when it parses as a complete number.
+	PhoneE164 string
+
+	// CompanyID restricts the search to the contacts of one company.
+	CompanyID int
+
+	ContactTimeRange
+	ContactReadOptions
+}
+
+// ContactTimeRange restricts a search to the contacts created or updated
+// within the given bounds. Zero times leave a bound open; the since bounds
+// are inclusive and the before bounds exclusive.
+type ContactTimeRange struct {
+	CreatedSince  time.Time
+	CreatedBefore time.Time
+	UpdatedSince  time.Time
+	UpdatedBefore time.Time
 }
 
 type Paging struct {
@@ -36,7 +131,52 @@ type Paging struct {
 	TotalItem int `json:"total_item"`
 }
 
+type CursorPaging struct {
+	Size       int    `json:"size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
 type ContactListResponse struct {
 	Data   []ContactResponse `json:"data"`
 	Paging Paging            `json:"paging"`
 }
+
+type ContactExportQuery struct {
+	ContactSearchQuery
+	Format           string
+	IncludeAddresses bool
+}
+
+type ContactExportRow struct {
+	ContactResponse
+	Addresses []addressModels.AddressResponse `json:"addresses,omitempty"`
+}
+
+type ContactBulkOperation struct {
+	Op   string          `json:"op" validate:"required,oneof=create update delete"`
+	ID   int             `json:"id" validate:"omitempty,min=1"`
+	Data json.RawMessage `json:"data"`
+}
+
+type ContactBulkRequest struct {
+	Atomic     bool                   `json:"atomic"`
+	Operations []ContactBulkOperation `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+type ContactBulkResult struct {
+	Index  int              `json:"index"`
+	Op     string           `json:"op"`
+	ID     int              `json:"id,omitempty"`
+	Status int              `json:"status"`
+	Data   *ContactResponse `json:"data,omitempty"`
+	Error  string           `json:"error,omitempty"`
+	Err    error            `json:"-"`
+}
+
+type ContactBulkResponse struct {
+	Atomic    bool                `json:"atomic"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []ContactBulkResult `json:"results"`
+}
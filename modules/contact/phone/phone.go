@@ -0,0 +1,42 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+var errNotValid = errors.New("not a valid phone number")
+
+// Normalize parses a phone number written in any common notation and returns
+// it in E.164 form. Numbers without a leading + or international prefix are
+// read as national numbers of region, an ISO 3166-1 alpha-2 code.
+func Normalize(raw string, region string) (string, error) {
+	number, err := phonenumbers.Parse(raw, strings.ToUpper(region))
+	if err != nil {
+		if region == "" && errors.Is(err, phonenumbers.ErrInvalidCountryCode) {
+			return "", errors.New("missing country code, use the +<country code> notation or set a default region")
+		}
+		return "", err
+	}
+
+	if !phonenumbers.IsValidNumber(number) {
+		return "", errNotValid
+	}
+
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
+
+// Digits strips everything but digits, along with the leading zeros of a
+// national trunk prefix, so a partial number such as "0812-34" can be
+// matched against stored E.164 values.
+func Digits(raw string) string {
+	var b strings.Builder
+	for _, r := range raw {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimLeft(b.String(), "0")
+}
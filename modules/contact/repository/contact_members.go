@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/contact/entities"
+	"golang-contact-management-restful-api/modules/contact/phone"
+	savedSearchEntities "golang-contact-management-restful-api/modules/savedsearch/entities"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Members keeps the members of shared saved searches, which grant access
+// to the contacts matching them, in step with the contacts. It runs in the
+// transaction writing a contact, its addresses or its company, so a grant
+// never lags behind the write.
+//
+// Writers lock the saved searches of the contact's owner with KEY SHARE
+// and Replace locks the saved search FOR UPDATE, so a saved search being
+// shared or changed waits for the contact writes in progress, and the other
+// way around.
+type Members struct {
+	trigram *trigramSupport
+}
+
+func NewMembers(similarityThreshold float64) *Members {
+	return &Members{trigram: &trigramSupport{threshold: similarityThreshold}}
+}
+
+// Refresh updates whether the contacts match the shared saved searches of
+// their owners, after they were written in tx. Contacts of organizations
+// cannot be shared and are left alone.
+func (members *Members) Refresh(ctx context.Context, tx *gorm.DB, contactIDs ...int) error {
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	// Locking the contacts orders concurrent refreshes of the same contact,
+	// the later one reading what the earlier one wrote.
+	var contacts []entities.Contact
+	if err := tx.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).Select("id", "username").
+		Where("id IN ? AND organization_id IS NULL", contactIDs).Order("id").Find(&contacts).Error; err != nil {
+		return err
+	}
+
+	byOwner := map[string][]int{}
+	var owners []string
+	for _, contact := range contacts {
+		if _, ok := byOwner[contact.Username]; !ok {
+			owners = append(owners, contact.Username)
+		}
+		byOwner[contact.Username] = append(byOwner[contact.Username], contact.ID)
+	}
+
+	for _, owner := range owners {
+		var ids []int
+		if err := tx.Model(&savedSearchEntities.SavedSearch{}).Clauses(clause.Locking{Strength: "KEY SHARE"}).
+			Where("username = ?", owner).Order("id").Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		// A share created while waiting for the lock is only visible to a
+		// statement started after it.
+		var savedSearches []savedSearchEntities.SavedSearch
+		if err := tx.Where("id IN ?", ids).Where(shared).Order("id").Find(&savedSearches).Error; err != nil {
+			return err
+		}
+
+		for _, savedSearch := range savedSearches {
+			if err := members.update(ctx, tx, savedSearch, byOwner[owner]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Replace recomputes every member of a saved search after it was shared or
+// its query changed in tx. Saved searches no share refers to keep their
+// members as they are, since they grant nothing.
+func (members *Members) Replace(ctx context.Context, tx *gorm.DB, savedSearchID int) error {
+	var savedSearch savedSearchEntities.SavedSearch
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&savedSearch, "id = ?", savedSearchID).Error; err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&savedSearchEntities.SavedSearch{}).Where("id = ?", savedSearchID).Where(shared).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	return members.update(ctx, tx, savedSearch, nil)
+}
+
+// update sets which of contactIDs, or of all the owner's personal contacts
+// when nil, are members of the saved search, recording those that start or
+// stop matching as changed for the grantees. A saved filter that no longer
+// parses matches nothing.
+func (members *Members) update(ctx context.Context, tx *gorm.DB, savedSearch savedSearchEntities.SavedSearch, contactIDs []int) error {
+	query := savedSearch.Query.ContactQuery()
+	if raw := strings.TrimSpace(query.Phone); raw != "" {
+		var region string
+		if err := tx.Raw(`SELECT coalesce(default_region, '') FROM "users" WHERE username = ?`, savedSearch.Username).
+			Scan(&region).Error; err != nil {
+			return err
+		}
+		if normalized, err := phone.Normalize(raw, region); err == nil {
+			query.PhoneE164 = normalized
+		}
+	}
+
+	fuzzy := query.Fuzzy && members.trigram.isAvailable(ctx, tx)
+	if fuzzy {
+		threshold := strconv.FormatFloat(members.trigram.threshold, 'f', -1, 64)
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return err
+		}
+	}
+
+	personal := workspace.NewContext(ctx, workspace.Workspace{})
+	owned, args := workspace.Owned(personal, "contacts", savedSearch.Username)
+	matching := (&contactRepositoryImpl{}).filtered(personal, tx.Session(&gorm.Session{NewDB: true}), savedSearch.Username, query, fuzzy).
+		Where(owned, args...)
+	current := tx.Model(&savedSearchEntities.SavedSearchMember{}).Where("saved_search_id = ?", savedSearch.ID)
+	if contactIDs != nil {
+		matching = matching.Where("contacts.id IN ?", contactIDs)
+		current = current.Where("contact_id IN ?", contactIDs)
+	}
+
+	var ids []int
+	if err := matching.Order("contacts.id").Pluck("contacts.id", &ids).Error; err != nil && !errors.Is(err, domain.ErrInvalidFilter) {
+		return err
+	}
+
+	var existing []int
+	if err := current.Order("contact_id").Pluck("contact_id", &existing).Error; err != nil {
+		return err
+	}
+
+	added := difference(ids, existing)
+	removed := difference(existing, ids)
+
+	if len(removed) > 0 {
+		if err := tx.Where("saved_search_id = ? AND contact_id IN ?", savedSearch.ID, removed).
+			Delete(&savedSearchEntities.SavedSearchMember{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(added) > 0 {
+		rows := make([]savedSearchEntities.SavedSearchMember, len(added))
+		for i, contactID := range added {
+			rows[i] = savedSearchEntities.SavedSearchMember{SavedSearchID: savedSearch.ID, ContactID: contactID}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&rows, 500).Error; err != nil {
+			return err
+		}
+	}
+
+	return changelog.Members(tx, savedSearch.ID, append(added, removed...))
+}
+
+// shared matches the saved searches some share refers to.
+const shared = `EXISTS (SELECT 1 FROM "contact_shares" s WHERE s.saved_search_id = "saved_searches"."id")`
+
+// difference returns the ids of a that are not in b.
+func difference(a []int, b []int) []int {
+	in := make(map[int]bool, len(b))
+	for _, id := range b {
+		in[id] = true
+	}
+
+	var ids []int
+	for _, id := range a {
+		if !in[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
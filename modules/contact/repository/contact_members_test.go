@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDifference(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []int
+		b    []int
+		want []int
+	}{
+		{name: "disjoint", a: []int{1, 2}, b: []int{3}, want: []int{1, 2}},
+		{name: "overlapping", a: []int{1, 2, 3}, b: []int{2}, want: []int{1, 3}},
+		{name: "contained", a: []int{2}, b: []int{1, 2, 3}, want: nil},
+		{name: "empty", a: nil, b: []int{1}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := difference(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("difference(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,21 @@ package repository
 
 import (
 	"context"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
 	"golang-contact-management-restful-api/modules/contact/entities"
 	"golang-contact-management-restful-api/modules/contact/models"
 )
 
 type ContactRepository interface {
 	Save(ctx context.Context, username string, contact entities.Contact) (entities.Contact, error)
-	UpdateByID(ctx context.Context, username string, id int, contact entities.Contact) (entities.Contact, error)
+	UpdateByID(ctx context.Context, username string, id int, version int, contact entities.Contact) (entities.Contact, error)
+	ReplaceByID(ctx context.Context, username string, id int, version int, contact entities.Contact) (entities.Contact, error)
 	FindByID(ctx context.Context, username string, id int) (entities.Contact, error)
-	DeleteByID(ctx context.Context, username string, id int) error
+	DeleteByID(ctx context.Context, username string, id int, version int) error
 	Search(ctx context.Context, username string, query models.ContactSearchQuery) ([]entities.Contact, int, error)
+	CountEach(ctx context.Context, username string, queries []models.ContactSearchQuery) ([]int, error)
+	SearchCursor(ctx context.Context, username string, query models.ContactSearchQuery) ([]entities.Contact, string, bool, error)
+	Stream(ctx context.Context, username string, query models.ContactSearchQuery, fn func(contact entities.Contact) error) error
+	Transaction(ctx context.Context, fn func(repository ContactRepository) error) error
+	FindAddressesByContactIDs(ctx context.Context, contactIDs []int) (map[int][]addressEntities.Address, error)
 }
@@ -3,32 +3,77 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"golang-contact-management-restful-api/internal/database"
+	"golang-contact-management-restful-api/internal/workspace"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
 	"golang-contact-management-restful-api/modules/contact/domain"
 	"golang-contact-management-restful-api/modules/contact/entities"
+	"golang-contact-management-restful-api/modules/contact/filter"
 	"golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/contact/phone"
+	historyEntities "golang-contact-management-restful-api/modules/history/entities"
+	"golang-contact-management-restful-api/modules/history/recorder"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	syncEntities "golang-contact-management-restful-api/modules/sync/entities"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
 )
 
 type contactRepositoryImpl struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	trigram *trigramSupport
+	members *Members
 }
 
-func NewContactRepository(db *gorm.DB) ContactRepository {
-	return &contactRepositoryImpl{DB: db}
+func NewContactRepository(db *gorm.DB, similarityThreshold float64) ContactRepository {
+	trigram := &trigramSupport{threshold: similarityThreshold}
+	return &contactRepositoryImpl{DB: db, trigram: trigram, members: &Members{trigram: trigram}}
 }
 
 func (repository *contactRepositoryImpl) Save(ctx context.Context, username string, contact entities.Contact) (entities.Contact, error) {
+	current := workspace.FromContext(ctx)
+	if !current.CanWrite() {
+		return entities.Contact{}, domain.ErrContactReadOnly
+	}
+
 	contact.Username = username
-	if err := repository.DB.WithContext(ctx).Create(&contact).Error; err != nil {
-		return entities.Contact{}, err
+	contact.CreatedBy = username
+	contact.UpdatedBy = username
+	if !current.IsPersonal() {
+		contact.OrganizationID = &current.OrganizationID
+	}
+
+	if contact.CompanyID != nil {
+		if err := repository.checkCompany(ctx, username, *contact.CompanyID); err != nil {
+			return entities.Contact{}, err
+		}
+	}
+
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&contact).Error; err != nil {
+			return err
+		}
+		if err := changelog.Contact(tx, contact.ID, syncEntities.ActionCreate); err != nil {
+			return err
+		}
+		if err := repository.members.Refresh(ctx, tx, contact.ID); err != nil {
+			return err
+		}
+		return recorder.Record(tx, username, contact.ID, historyEntities.ActionCreate)
+	})
+	if err != nil {
+		return entities.Contact{}, repository.translateError(ctx, err, username, contact.Email, 0)
 	}
 
 	return contact, nil
 }
 
-func (repository *contactRepositoryImpl) UpdateByID(ctx context.Context, username string, id int, contact entities.Contact) (entities.Contact, error) {
+// UpdateByID applies the set fields of contact. A non-zero version makes the
+// update conditional on the contact still being at that version.
+func (repository *contactRepositoryImpl) UpdateByID(ctx context.Context, username string, id int, version int, contact entities.Contact) (entities.Contact, error) {
 	updateMap := map[string]any{}
 
 	if contact.Email != nil {
@@ -39,6 +84,10 @@ func (repository *contactRepositoryImpl) UpdateByID(ctx context.Context, usernam
 		updateMap["phone"] = *contact.Phone
 	}
 
+	if contact.PhoneE164 != nil {
+		updateMap["phone_e164"] = *contact.PhoneE164
+	}
+
 	if contact.LastName != nil {
 		updateMap["last_name"] = *contact.LastName
 	}
@@ -47,33 +96,101 @@ func (repository *contactRepositoryImpl) UpdateByID(ctx context.Context, usernam
 		updateMap["first_name"] = contact.FirstName
 	}
 
+	if contact.JobTitle != nil {
+		updateMap["job_title"] = *contact.JobTitle
+	}
+
+	if contact.Department != nil {
+		updateMap["department"] = *contact.Department
+	}
+
+	if contact.CompanyID != nil && *contact.CompanyID == 0 {
+		updateMap["company_id"] = nil
+	} else if contact.CompanyID != nil {
+		if err := repository.checkCompany(ctx, username, *contact.CompanyID); err != nil {
+			return entities.Contact{}, err
+		}
+		updateMap["company_id"] = *contact.CompanyID
+	}
+
 	if len(updateMap) == 0 {
 		return entities.Contact{}, nil
 	}
 
-	result := repository.DB.WithContext(ctx).Model(&entities.Contact{}).
-		Where("id = ?", id).Where("username = ?", username).
-		Updates(updateMap)
+	return repository.update(ctx, username, id, version, updateMap, contact.Email)
+}
 
-	if result.Error != nil {
-		return entities.Contact{}, result.Error
+// ReplaceByID writes every editable field of contact, clearing those that
+// are nil. The version works as in UpdateByID.
+func (repository *contactRepositoryImpl) ReplaceByID(ctx context.Context, username string, id int, version int, contact entities.Contact) (entities.Contact, error) {
+	if contact.CompanyID != nil {
+		if err := repository.checkCompany(ctx, username, *contact.CompanyID); err != nil {
+			return entities.Contact{}, err
+		}
 	}
 
-	if result.RowsAffected == 0 {
-		return entities.Contact{}, domain.ErrContactNotFound
-	}
+	return repository.update(ctx, username, id, version, map[string]any{
+		"first_name": contact.FirstName,
+		"last_name":  contact.LastName,
+		"email":      contact.Email,
+		"phone":      contact.Phone,
+		"phone_e164": contact.PhoneE164,
+		"company_id": contact.CompanyID,
+		"job_title":  contact.JobTitle,
+		"department": contact.Department,
+	}, contact.Email)
+}
+
+// update applies updateMap to the contact, recording the change in its
+// history, and returns the updated contact.
+func (repository *contactRepositoryImpl) update(ctx context.Context, username string, id int, version int, updateMap map[string]any, email *string) (entities.Contact, error) {
+	updateMap["version"] = gorm.Expr("version + 1")
+	updateMap["updated_by"] = username
+
+	writable, writableArgs := WritableBy(ctx, username)
+	readable, readableArgs := ReadableBy(ctx, username)
 
 	var updated entities.Contact
-	if err := repository.DB.WithContext(ctx).Take(&updated, "id = ? AND username = ?", id, username).Error; err != nil {
-		return entities.Contact{}, err
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := recorder.Baseline(tx, username, id); err != nil {
+			return err
+		}
+
+		result := atVersion(tx.Model(&entities.Contact{}).Where("id = ?", id).Where(writable, writableArgs...), version).
+			Updates(updateMap)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return writeError(ctx, tx, username, id, version)
+		}
+
+		if err := recorder.Record(tx, username, id, historyEntities.ActionUpdate); err != nil {
+			return err
+		}
+
+		if err := changelog.Contact(tx, id, syncEntities.ActionUpdate); err != nil {
+			return err
+		}
+
+		if err := repository.members.Refresh(ctx, tx, id); err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Where(readable, readableArgs...).Take(&updated).Error
+	})
+	if err != nil {
+		return entities.Contact{}, repository.translateError(ctx, err, username, email, id)
 	}
 
 	return updated, nil
 }
 
 func (repository *contactRepositoryImpl) FindByID(ctx context.Context, username string, id int) (entities.Contact, error) {
+	readable, args := ReadableBy(ctx, username)
 	var contact entities.Contact
-	if err := repository.DB.WithContext(ctx).Where("id = ? AND username = ?", id, username).Take(&contact).Error; err != nil {
+	if err := repository.DB.WithContext(ctx).Where("id = ?", id).Where(readable, args...).Take(&contact).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return entities.Contact{}, domain.ErrContactNotFound
 		}
@@ -83,16 +200,35 @@ func (repository *contactRepositoryImpl) FindByID(ctx context.Context, username
 	return contact, nil
 }
 
-func (repository *contactRepositoryImpl) DeleteByID(ctx context.Context, username string, id int) error {
-	result := repository.DB.WithContext(ctx).Where("id = ? AND username = ?", id, username).Delete(&entities.Contact{})
-	if result.Error != nil {
-		return result.Error
-	}
+// DeleteByID deletes the contact together with the relationships pointing
+// to or from it. Its history is kept so the contact can be restored. A
+// non-zero version makes the delete conditional as in UpdateByID.
+func (repository *contactRepositoryImpl) DeleteByID(ctx context.Context, username string, id int, version int) error {
+	writable, args := WritableBy(ctx, username)
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := recorder.Baseline(tx, username, id); err != nil {
+			return err
+		}
 
-	if result.RowsAffected == 0 {
-		return domain.ErrContactNotFound
-	}
-	return nil
+		if err := changelog.Contact(tx, id, syncEntities.ActionDelete); err != nil {
+			return err
+		}
+
+		result := atVersion(tx.Where("id = ?", id).Where(writable, args...), version).Delete(&entities.Contact{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return writeError(ctx, tx, username, id, version)
+		}
+
+		if err := tx.Exec(`DELETE FROM "contact_relationships" WHERE contact_id = ? OR related_contact_id = ?`, id, id).Error; err != nil {
+			return err
+		}
+
+		return recorder.Record(tx, username, id, historyEntities.ActionDelete)
+	})
 }
 
 func (repository *contactRepositoryImpl) Search(ctx context.Context, username string, query models.ContactSearchQuery) ([]entities.Contact, int, error) {
@@ -101,43 +237,347 @@ func (repository *contactRepositoryImpl) Search(ctx context.Context, username st
 		page = 1
 	}
 
-	size := query.Size
-	if size <= 0 {
-		size = 10
+	size := pageSize(query.Size)
+
+	var keys []sortKey
+	if query.Sort != "" {
+		var err error
+		if keys, err = parseSort(query.Sort); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	if size > 100 {
-		size = 100
+	var total int64
+	var contacts []entities.Contact
+	err := repository.searchSession(ctx, query, func(db *gorm.DB, fuzzy bool) error {
+		db = repository.filtered(ctx, db, username, query, fuzzy)
+
+		if err := db.Count(&total).Error; err != nil {
+			return err
+		}
+
+		if tsQuery := buildPrefixTSQuery(query.Query); tsQuery != "" {
+			db = db.Select("contacts.*, ts_rank(contacts.search_vector, to_tsquery('simple', ?)) AS rank, "+
+				"ts_headline('simple', concat_ws(' ', contacts.first_name, contacts.last_name, contacts.email, contacts.phone, contacts_address_text(contacts.id)), "+
+				"to_tsquery('simple', ?), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS snippet", tsQuery, tsQuery)
+			if keys == nil {
+				db = db.Order("rank DESC")
+			}
+		} else if rank, args := similarityRank(query); fuzzy && rank != "" {
+			db = db.Select("contacts.*, "+rank+" AS rank", args...)
+			if keys == nil {
+				db = db.Order("rank DESC")
+			}
+		}
+
+		if keys == nil {
+			db = db.Order("id DESC")
+		} else {
+			db = db.Order(orderClause(keys))
+		}
+
+		return db.Limit(size).Offset((page - 1) * size).Find(&contacts).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contacts, int(total), nil
+
+}
+
+// CountEach counts the contacts matching each of queries, in a single
+// statement with a subquery per query.
+func (repository *contactRepositoryImpl) CountEach(ctx context.Context, username string, queries []models.ContactSearchQuery) ([]int, error) {
+	counts := make([]int, len(queries))
+	if len(queries) == 0 {
+		return counts, nil
+	}
+
+	// The session is fuzzy when any query is; the others ignore it.
+	var session models.ContactSearchQuery
+	for _, query := range queries {
+		session.Fuzzy = session.Fuzzy || query.Fuzzy
+	}
+
+	totals := make([]int64, len(queries))
+	err := repository.searchSession(ctx, session, func(db *gorm.DB, fuzzy bool) error {
+		columns := make([]string, len(queries))
+		subqueries := make([]any, len(queries))
+		for i, query := range queries {
+			subquery := repository.filtered(ctx, db.Session(&gorm.Session{NewDB: true}), username, query, fuzzy && query.Fuzzy).
+				Select("count(*)")
+			if subquery.Error != nil {
+				return subquery.Error
+			}
+			columns[i] = "(?)"
+			subqueries[i] = subquery
+		}
+
+		scan := make([]any, len(totals))
+		for i := range totals {
+			scan[i] = &totals[i]
+		}
+		return db.Raw("SELECT "+strings.Join(columns, ", "), subqueries...).Row().Scan(scan...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, total := range totals {
+		counts[i] = int(total)
+	}
+	return counts, nil
+}
+
+func (repository *contactRepositoryImpl) SearchCursor(ctx context.Context, username string, query models.ContactSearchQuery) ([]entities.Contact, string, bool, error) {
+	size := pageSize(query.Size)
+
+	keys, err := parseSort(query.Sort)
+	if err != nil {
+		return nil, "", false, err
 	}
 
-	db := repository.DB.WithContext(ctx).Model(&entities.Contact{}).Where("username = ?", username)
+	var after []any
+	if query.Cursor != "" {
+		if after, err = decodeCursor(keys, query.Cursor); err != nil {
+			return nil, "", false, err
+		}
+	}
+
+	var contacts []entities.Contact
+	err = repository.searchSession(ctx, query, func(db *gorm.DB, fuzzy bool) error {
+		db = repository.filtered(ctx, db, username, query, fuzzy)
+		if after != nil {
+			condition, args := keysetCondition(keys, after)
+			db = db.Where(condition, args...)
+		}
+
+		return db.Order(orderClause(keys)).Limit(size + 1).Find(&contacts).Error
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	hasMore := len(contacts) > size
+	if !hasMore {
+		return contacts, "", false, nil
+	}
+
+	contacts = contacts[:size]
+	nextCursor, err := encodeCursor(keys, contacts[size-1])
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return contacts, nextCursor, true, nil
+}
+
+func (repository *contactRepositoryImpl) Stream(ctx context.Context, username string, query models.ContactSearchQuery, fn func(contact entities.Contact) error) error {
+	return repository.searchSession(ctx, query, func(db *gorm.DB, fuzzy bool) error {
+		rows, err := repository.filtered(ctx, db, username, query, fuzzy).Order("id ASC").Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var contact entities.Contact
+			if err := db.ScanRows(rows, &contact); err != nil {
+				return err
+			}
+			if err := fn(contact); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+func (repository *contactRepositoryImpl) Transaction(ctx context.Context, fn func(repository ContactRepository) error) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&contactRepositoryImpl{DB: tx, trigram: repository.trigram, members: repository.members})
+	})
+}
+
+func (repository *contactRepositoryImpl) FindAddressesByContactIDs(ctx context.Context, contactIDs []int) (map[int][]addressEntities.Address, error) {
+	addressesByContact := make(map[int][]addressEntities.Address, len(contactIDs))
+	if len(contactIDs) == 0 {
+		return addressesByContact, nil
+	}
+
+	var addresses []addressEntities.Address
+	if err := repository.DB.WithContext(ctx).Where("contact_id IN ?", contactIDs).
+		Order("contact_id ASC, id ASC").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	for _, address := range addresses {
+		addressesByContact[address.ContactID] = append(addressesByContact[address.ContactID], address)
+	}
+
+	return addressesByContact, nil
+}
+
+// atVersion limits a write to rows at version, unless it is zero.
+func atVersion(db *gorm.DB, version int) *gorm.DB {
+	if version == 0 {
+		return db
+	}
+	return db.Where("version = ?", version)
+}
+
+// writeError explains why a write to a contact matched nothing, telling a
+// stale version apart from missing access.
+func writeError(ctx context.Context, tx *gorm.DB, username string, id int, version int) error {
+	if version != 0 {
+		writable, args := WritableBy(ctx, username)
+
+		var count int64
+		if err := tx.Model(&entities.Contact{}).Where("id = ?", id).Where(writable, args...).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrVersionMismatch
+		}
+	}
+
+	return AccessError(ctx, tx, username, id)
+}
+
+// checkCompany makes sure contacts are only linked to companies of the
+// current workspace.
+func (repository *contactRepositoryImpl) checkCompany(ctx context.Context, username string, companyID int) error {
+	owned, args := workspace.Owned(ctx, "companies", username)
+
+	var count int64
+	if err := repository.DB.WithContext(ctx).Table("companies").Where("companies.id = ?", companyID).
+		Where(owned, args...).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return domain.ErrCompanyNotFound
+	}
+	return nil
+}
+
+// translateError turns a violation of the per-user email uniqueness index
+// into a DuplicateEmailError carrying the conflicting contact.
+func (repository *contactRepositoryImpl) translateError(ctx context.Context, err error, username string, email *string, excludeID int) error {
+	if email == nil || !database.IsUniqueViolation(err, "idx_contacts_username_email_key") {
+		return err
+	}
+
+	// The lookup fails inside a transaction aborted by the violation, in
+	// which case the error is reported without the contact id.
+	var conflictingID int
+	_ = repository.DB.WithContext(ctx).Raw(`SELECT c.id FROM "contacts" c JOIN "users" u ON u.username = c.username
+		WHERE c.username = coalesce((SELECT username FROM "contacts" WHERE id = ?), ?)
+		AND c.organization_id IS NULL AND c.id <> ? AND c.email_key = contacts_email_key(?, u.canonical_gmail_emails)
+		LIMIT 1`, excludeID, username, excludeID, *email).Scan(&conflictingID).Error
 
-	if space := strings.TrimSpace(query.Name); space != "" {
+	return &domain.DuplicateEmailError{ContactID: conflictingID}
+}
+
+func (repository *contactRepositoryImpl) filtered(ctx context.Context, db *gorm.DB, username string, query models.ContactSearchQuery, fuzzy bool) *gorm.DB {
+	readable, args := ReadableBy(ctx, username)
+	db = db.Model(&entities.Contact{}).Where(readable, args...)
+
+	if space := strings.TrimSpace(query.Name); space != "" && fuzzy {
+		like := "%" + space + "%"
+		db = db.Where("first_name ILIKE ? OR last_name ILIKE ? OR first_name % ? OR last_name % ?", like, like, space, space)
+	} else if space != "" {
 		like := "%" + space + "%"
 		db = db.Where("first_name ILIKE ? OR last_name ILIKE ?", like, like)
 	}
 
-	if space := strings.TrimSpace(query.Email); space != "" {
+	if space := strings.TrimSpace(query.Email); space != "" && fuzzy {
+		like := "%" + space + "%"
+		db = db.Where("email ILIKE ? OR email % ?", like, space)
+	} else if space != "" {
 		like := "%" + space + "%"
 		db = db.Where("email ILIKE ?", like)
 	}
 
 	if space := strings.TrimSpace(query.Phone); space != "" {
 		like := "%" + space + "%"
-		db = db.Where("phone ILIKE ?", like)
+		if query.PhoneE164 != "" {
+			db = db.Where("phone_e164 = ? OR phone ILIKE ?", query.PhoneE164, like)
+		} else if digits := phone.Digits(space); digits != "" {
+			db = db.Where("phone_e164 LIKE ? OR phone ILIKE ?", "%"+digits+"%", like)
+		} else {
+			db = db.Where("phone ILIKE ?", like)
+		}
 	}
 
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if space := strings.TrimSpace(query.Company); space != "" {
+		db = db.Where(`EXISTS (SELECT 1 FROM "companies" co WHERE co.id = contacts.company_id AND co.name ILIKE ?)`, "%"+space+"%")
 	}
 
-	var contacts []entities.Contact
-	if err := db.Order("id DESC").Limit(size).Offset((page - 1) * size).
-		Find(&contacts).Error; err != nil {
-		return nil, 0, err
+	if query.CompanyID != 0 {
+		db = db.Where("contacts.company_id = ?", query.CompanyID)
 	}
 
-	return contacts, int(total), nil
+	if !query.CreatedSince.IsZero() {
+		db = db.Where("contacts.created_at >= ?", query.CreatedSince)
+	}
+
+	if !query.CreatedBefore.IsZero() {
+		db = db.Where("contacts.created_at < ?", query.CreatedBefore)
+	}
+
+	if !query.UpdatedSince.IsZero() {
+		db = db.Where("contacts.updated_at >= ?", query.UpdatedSince)
+	}
+
+	if !query.UpdatedBefore.IsZero() {
+		db = db.Where("contacts.updated_at < ?", query.UpdatedBefore)
+	}
+
+	if tsQuery := buildPrefixTSQuery(query.Query); tsQuery != "" {
+		db = db.Where("contacts.search_vector @@ to_tsquery('simple', ?)", tsQuery)
+	}
+
+	if space := strings.TrimSpace(query.Filter); space != "" {
+		node, err := filter.Parse(space)
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("%w: %s", domain.ErrInvalidFilter, err.Error()))
+			return db
+		}
+		condition, args := filter.Compile(node)
+		db = db.Where(condition, args...)
+	}
+
+	return db
+}
+
+// searchSession runs fn against the database. Fuzzy searches run inside a
+// transaction that sets the pg_trgm similarity threshold, so the % operator
+// can use the trigram indexes; without the extension they fall back to ILIKE.
+func (repository *contactRepositoryImpl) searchSession(ctx context.Context, query models.ContactSearchQuery, fn func(db *gorm.DB, fuzzy bool) error) error {
+	if !query.Fuzzy || !repository.trigram.isAvailable(ctx, repository.DB) {
+		return fn(repository.DB.WithContext(ctx), false)
+	}
+
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		threshold := strconv.FormatFloat(repository.trigram.threshold, 'f', -1, 64)
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return err
+		}
+		return fn(tx, true)
+	})
+}
+
+func pageSize(size int) int {
+	if size <= 0 {
+		return 10
+	}
+
+	if size > 100 {
+		return 100
+	}
 
+	return size
 }
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/contact/domain"
+
+	"gorm.io/gorm"
+)
+
+// shareGrant matches an accepted share from the contact's owner to the user,
+// of the whole address book, of the contact itself, or of a saved search
+// the contact currently matches. Members updates the saved search members in
+// the transactions writing the contacts, so they are never behind.
+const shareGrant = `EXISTS (SELECT 1 FROM "contact_shares" s WHERE s.grantee_username = ? ` +
+	`AND s.owner_username = contacts.username AND s.status = 'accepted' ` +
+	`AND (s.contact_id IS NULL AND s.saved_search_id IS NULL OR s.contact_id = contacts.id ` +
+	`OR EXISTS (SELECT 1 FROM "saved_search_members" m WHERE m.saved_search_id = s.saved_search_id AND m.contact_id = contacts.id))`
+
+// ReadableBy returns the condition selecting the contacts visible to
+// username in the workspace of ctx. In the personal workspace these are the
+// user's own contacts and those shared with them; in an organization they
+// are the organization's contacts. It expects the contacts table to be named
+// contacts in the query.
+func ReadableBy(ctx context.Context, username string) (string, []any) {
+	owned, args := workspace.Owned(ctx, "contacts", username)
+	if !workspace.FromContext(ctx).IsPersonal() {
+		return "(" + owned + ")", args
+	}
+	return "((" + owned + ") OR contacts.organization_id IS NULL AND " + shareGrant + "))", append(args, username)
+}
+
+// WritableBy is like ReadableBy but only honours read-write grants, and
+// matches nothing for viewers of an organization.
+func WritableBy(ctx context.Context, username string) (string, []any) {
+	current := workspace.FromContext(ctx)
+	if !current.CanWrite() {
+		return "FALSE", nil
+	}
+
+	owned, args := workspace.Owned(ctx, "contacts", username)
+	if !current.IsPersonal() {
+		return "(" + owned + ")", args
+	}
+	return "((" + owned + ") OR contacts.organization_id IS NULL AND " + shareGrant + " AND s.permission = 'write'))", append(args, username)
+}
+
+// AccessError explains why a write to a contact matched nothing: the
+// contact is either only readable by the user or not visible at all.
+func AccessError(ctx context.Context, db *gorm.DB, username string, contactID int) error {
+	condition, args := ReadableBy(ctx, username)
+
+	var count int64
+	if err := db.WithContext(ctx).Table("contacts").Where("contacts.id = ?", contactID).
+		Where(condition, args...).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return domain.ErrContactReadOnly
+	}
+	return domain.ErrContactNotFound
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/contact/models"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// buildPrefixTSQuery turns free text into a to_tsquery expression that
+// requires every word, matching each one as a prefix. Anything that is not a
+// letter or a digit is treated as a separator, so user input can never
+// inject tsquery operators.
+func buildPrefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, strings.ToLower(word)+":*")
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// trigramSupport remembers whether the pg_trgm extension is installed. The
+// check runs once per process, so installing the extension needs a restart.
+type trigramSupport struct {
+	once      sync.Once
+	available bool
+	threshold float64
+}
+
+func (support *trigramSupport) isAvailable(ctx context.Context, db *gorm.DB) bool {
+	support.once.Do(func() {
+		var available bool
+		err := db.WithContext(ctx).
+			Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')").
+			Scan(&available).Error
+		support.available = err == nil && available
+	})
+	return support.available
+}
+
+// similarityRank builds the expression ranking fuzzy matches by the best
+// trigram similarity of the searched name and email.
+func similarityRank(query models.ContactSearchQuery) (string, []any) {
+	var terms []string
+	var args []any
+
+	if name := strings.TrimSpace(query.Name); name != "" {
+		terms = append(terms, "similarity(contacts.first_name, ?)", "similarity(coalesce(contacts.last_name, ''), ?)")
+		args = append(args, name, name)
+	}
+
+	if email := strings.TrimSpace(query.Email); email != "" {
+		terms = append(terms, "similarity(coalesce(contacts.email, ''), ?)")
+		args = append(args, email)
+	}
+
+	if len(terms) == 0 {
+		return "", nil
+	}
+
+	return "GREATEST(" + strings.Join(terms, ", ") + ")", args
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/contact/entities"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortableFields whitelists the fields accepted by the sort parameter.
+// Nullable columns are coalesced so that keyset comparisons never see NULL.
+var sortableFields = map[string]string{
+	"id":         "contacts.id",
+	"first_name": "contacts.first_name",
+	"last_name":  "coalesce(contacts.last_name, '')",
+	"email":      "coalesce(contacts.email, '')",
+	"phone":      "coalesce(contacts.phone, '')",
+	"created_at": "contacts.created_at",
+	"updated_at": "contacts.updated_at",
+}
+
+type sortKey struct {
+	field string
+	expr  string
+	desc  bool
+}
+
+// parseSort parses a comma separated list such as "first_name,-last_name",
+// where a leading "-" sorts descending. The id is always appended as a
+// tie-breaker so the order is total, which keyset pagination relies on.
+func parseSort(value string) ([]sortKey, error) {
+	var keys []sortKey
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key := sortKey{field: part}
+		if strings.HasPrefix(part, "-") {
+			key.field, key.desc = part[1:], true
+		} else if strings.HasPrefix(part, "+") {
+			key.field = part[1:]
+		}
+
+		expr, ok := sortableFields[key.field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q, expected one of %s", domain.ErrInvalidSort, key.field, strings.Join(sortableFieldNames(), ", "))
+		}
+		if seen[key.field] {
+			continue
+		}
+
+		key.expr = expr
+		seen[key.field] = true
+		keys = append(keys, key)
+	}
+
+	if !seen["id"] {
+		keys = append(keys, sortKey{field: "id", expr: sortableFields["id"], desc: true})
+	}
+
+	return keys, nil
+}
+
+func sortableFieldNames() []string {
+	names := make([]string, 0, len(sortableFields))
+	for name := range sortableFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func orderClause(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "ASC"
+		if key.desc {
+			direction = "DESC"
+		}
+		parts[i] = key.expr + " " + direction
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortSignature(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		if key.desc {
+			parts[i] = "-" + key.field
+		} else {
+			parts[i] = key.field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+type contactCursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+func encodeCursor(keys []sortKey, contact entities.Contact) (string, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		values[i] = sortValue(contact, key.field)
+	}
+
+	payload, err := json.Marshal(contactCursor{Sort: sortSignature(keys), Values: values})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func decodeCursor(keys []sortKey, value string) ([]any, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	var cursor contactCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	if cursor.Sort != sortSignature(keys) || len(cursor.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: it was issued for a different sort", domain.ErrInvalidCursor)
+	}
+
+	for i, key := range keys {
+		switch key.field {
+		case "id":
+			if number, ok := cursor.Values[i].(float64); ok {
+				cursor.Values[i] = int(number)
+			}
+		case "created_at", "updated_at":
+			value, _ := cursor.Values[i].(string)
+			parsed, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, domain.ErrInvalidCursor
+			}
+			cursor.Values[i] = parsed
+		}
+	}
+
+	return cursor.Values, nil
+}
+
+// keysetCondition builds the predicate selecting the rows that come after
+// the cursor values in the given order:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(keys []sortKey, values []any) (string, []any) {
+	var clauses []string
+	var args []any
+
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].expr+" = ?")
+			args = append(args, values[j])
+		}
+
+		operator := ">"
+		if key.desc {
+			operator = "<"
+		}
+		parts = append(parts, key.expr+" "+operator+" ?")
+		args = append(args, values[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return strings.Join(clauses, " OR "), args
+}
+
+func sortValue(contact entities.Contact, field string) any {
+	switch field {
+	case "id":
+		return contact.ID
+	case "first_name":
+		return contact.FirstName
+	case "last_name":
+		return http.PointerToString(contact.LastName)
+	case "email":
+		return http.PointerToString(contact.Email)
+	case "phone":
+		return http.PointerToString(contact.Phone)
+	case "created_at":
+		return contact.CreatedAt
+	case "updated_at":
+		return contact.UpdatedAt
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/contact/phone"
+	"strings"
+)
+
+// normalizePhone returns the E.164 form of raw, reading national numbers
+// with the user's default region. It returns nil when raw is empty.
+func (usecase *contactUsecaseImpl) normalizePhone(ctx context.Context, username string, raw string) (*string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	user, err := usecase.userRepository.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := phone.Normalize(raw, user.DefaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %s", domain.ErrInvalidPhone, raw, err.Error())
+	}
+
+	return &normalized, nil
+}
+
+// resolvePhoneQuery normalises the phone search parameter when it is a
+// complete number, so it matches however the stored numbers were written.
+// Partial numbers are left to the repository's digit matching.
+func (usecase *contactUsecaseImpl) resolvePhoneQuery(ctx context.Context, username string, query *models.ContactSearchQuery) error {
+	raw := strings.TrimSpace(query.Phone)
+	if raw == "" {
+		return nil
+	}
+
+	user, err := usecase.userRepository.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if normalized, err := phone.Normalize(raw, user.DefaultRegion); err == nil {
+		query.PhoneE164 = normalized
+	}
+
+	return nil
+}
+
+// resolvePhoneQueries is resolvePhoneQuery for several queries, looking the
+// user up at most once.
+func (usecase *contactUsecaseImpl) resolvePhoneQueries(ctx context.Context, username string, queries []models.ContactSearchQuery) error {
+	var region *string
+	for i := range queries {
+		raw := strings.TrimSpace(queries[i].Phone)
+		if raw == "" {
+			continue
+		}
+
+		if region == nil {
+			user, err := usecase.userRepository.FindByUsername(ctx, username)
+			if err != nil {
+				return err
+			}
+			region = &user.DefaultRegion
+		}
+
+		if normalized, err := phone.Normalize(raw, *region); err == nil {
+			queries[i].PhoneE164 = normalized
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	addressModels "golang-contact-management-restful-api/modules/address/models"
+	addressUsecase "golang-contact-management-restful-api/modules/address/usecase"
+	"golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/contact/models"
+	"sort"
+	"strings"
+)
+
+// contactFields whitelists the attributes accepted by the fields parameter.
+var contactFields = map[string]bool{
+	"id":         true,
+	"first_name": true,
+	"last_name":  true,
+	"email":      true,
+	"phone":      true,
+	"phone_e164": true,
+	"company_id": true,
+	"job_title":  true,
+	"department": true,
+	"shared_by":  true,
+	"rank":       true,
+	"snippet":    true,
+	"created_at": true,
+	"updated_at": true,
+	"created_by": true,
+	"updated_by": true,
+}
+
+var contactIncludes = map[string]bool{
+	models.ContactIncludeAddresses: true,
+}
+
+func validateReadOptions(options models.ContactReadOptions) error {
+	for _, field := range options.Fields {
+		if !contactFields[field] {
+			return fmt.Errorf("%w: unknown field %q, expected one of %s", domain.ErrInvalidFields, field, strings.Join(sortedKeys(contactFields), ", "))
+		}
+	}
+
+	for _, include := range options.Include {
+		if !contactIncludes[include] {
+			return fmt.Errorf("%w: unknown relation %q, expected one of %s", domain.ErrInvalidInclude, include, strings.Join(sortedKeys(contactIncludes), ", "))
+		}
+	}
+
+	return nil
+}
+
+// embed attaches the requested relations to the responses. Each relation is
+// loaded with a single query for the whole page.
+func (usecase *contactUsecaseImpl) embed(ctx context.Context, responses []models.ContactResponse, include []string) error {
+	for _, relation := range include {
+		switch relation {
+		case models.ContactIncludeAddresses:
+			ids := make([]int, len(responses))
+			for i, response := range responses {
+				ids[i] = response.ID
+			}
+
+			addresses, err := usecase.contactRepository.FindAddressesByContactIDs(ctx, ids)
+			if err != nil {
+				return err
+			}
+
+			for i := range responses {
+				embedded := toAddressResponses(addresses[responses[i].ID])
+				responses[i].Addresses = &embedded
+			}
+		}
+	}
+
+	return nil
+}
+
+func toAddressResponses(addresses []addressEntities.Address) []addressModels.AddressResponse {
+	responses := make([]addressModels.AddressResponse, len(addresses))
+	for i, address := range addresses {
+		responses[i] = addressUsecase.ToAddressResponse(address)
+	}
+	return responses
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
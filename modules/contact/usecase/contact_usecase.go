@@ -2,13 +2,19 @@ package usecase
 
 import (
 	"context"
+	"golang-contact-management-restful-api/internal/jsonpatch"
 	"golang-contact-management-restful-api/modules/contact/models"
 )
 
 type ContactUsecase interface {
 	Create(ctx context.Context, username string, request models.ContactCreateRequest) (models.ContactResponse, error)
-	Update(ctx context.Context, username string, id int, request models.ContactUpdateRequest) (models.ContactResponse, error)
-	FindByID(ctx context.Context, username string, id int) (models.ContactResponse, error)
-	DeleteByID(ctx context.Context, username string, id int) error
+	Update(ctx context.Context, username string, id int, version int, request models.ContactUpdateRequest) (models.ContactResponse, error)
+	Patch(ctx context.Context, username string, id int, version int, patch jsonpatch.Patch) (models.ContactResponse, error)
+	FindByID(ctx context.Context, username string, id int, options models.ContactReadOptions) (models.ContactResponse, error)
+	DeleteByID(ctx context.Context, username string, id int, version int) error
 	Search(ctx context.Context, username string, query models.ContactSearchQuery) ([]models.ContactResponse, models.Paging, error)
+	CountEach(ctx context.Context, username string, queries []models.ContactSearchQuery) ([]int, error)
+	SearchCursor(ctx context.Context, username string, query models.ContactSearchQuery) ([]models.ContactResponse, models.CursorPaging, error)
+	Bulk(ctx context.Context, username string, request models.ContactBulkRequest) (models.ContactBulkResponse, error)
+	Export(ctx context.Context, username string, query models.ContactExportQuery, fn func(row models.ContactExportRow) error) error
 }
@@ -2,23 +2,34 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-contact-management-restful-api/internal/jsonpatch"
 	"golang-contact-management-restful-api/internal/transport/http"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	"golang-contact-management-restful-api/modules/contact/domain"
 	"golang-contact-management-restful-api/modules/contact/entities"
 	"golang-contact-management-restful-api/modules/contact/models"
 	"golang-contact-management-restful-api/modules/contact/repository"
+	userRepository "golang-contact-management-restful-api/modules/user/repository"
 	"math"
 
 	"github.com/go-playground/validator/v10"
 )
 
+const exportBatchSize = 500
+
 type contactUsecaseImpl struct {
 	contactRepository repository.ContactRepository
+	userRepository    userRepository.UserRepository
 	validator         *validator.Validate
 }
 
-func NewContactUsecase(contactRepository repository.ContactRepository, validator *validator.Validate) ContactUsecase {
+func NewContactUsecase(contactRepository repository.ContactRepository, userRepository userRepository.UserRepository, validator *validator.Validate) ContactUsecase {
 	return &contactUsecaseImpl{
 		contactRepository: contactRepository,
+		userRepository:    userRepository,
 		validator:         validator,
 	}
 }
@@ -29,72 +40,157 @@ func (usecase *contactUsecaseImpl) Create(ctx context.Context, username string,
 	}
 
 	contact := entities.Contact{
-		FirstName: request.FirstName,
-		LastName:  http.StringToPointerIfNotEmpty(request.LastName),
-		Email:     http.StringToPointerIfNotEmpty(request.Email),
-		Phone:     http.StringToPointerIfNotEmpty(request.Phone),
+		FirstName:  request.FirstName,
+		LastName:   http.StringToPointerIfNotEmpty(request.LastName),
+		Email:      http.StringToPointerIfNotEmpty(request.Email),
+		Phone:      http.StringToPointerIfNotEmpty(request.Phone),
+		JobTitle:   http.StringToPointerIfNotEmpty(request.JobTitle),
+		Department: http.StringToPointerIfNotEmpty(request.Department),
+	}
+	if request.CompanyID != 0 {
+		contact.CompanyID = &request.CompanyID
+	}
+
+	phoneE164, err := usecase.normalizePhone(ctx, username, request.Phone)
+	if err != nil {
+		return models.ContactResponse{}, err
 	}
+	contact.PhoneE164 = phoneE164
 
 	saved, err := usecase.contactRepository.Save(ctx, username, contact)
 	if err != nil {
 		return models.ContactResponse{}, err
 	}
 
-	return models.ContactResponse{
-		ID:        saved.ID,
-		FirstName: saved.FirstName,
-		LastName:  http.PointerToString(saved.LastName),
-		Email:     http.PointerToString(saved.Email),
-		Phone:     http.PointerToString(saved.Phone),
-	}, nil
+	return ToContactResponse(username, saved), nil
 }
 
-func (usecase *contactUsecaseImpl) Update(ctx context.Context, username string, id int, request models.ContactUpdateRequest) (models.ContactResponse, error) {
+func (usecase *contactUsecaseImpl) Update(ctx context.Context, username string, id int, version int, request models.ContactUpdateRequest) (models.ContactResponse, error) {
 	if err := usecase.validator.Struct(request); err != nil {
 		return models.ContactResponse{}, err
 	}
 
 	contact := entities.Contact{
-		FirstName: request.FirstName,
-		LastName:  http.StringToPointerIfNotEmpty(request.LastName),
-		Email:     http.StringToPointerIfNotEmpty(request.Email),
-		Phone:     http.StringToPointerIfNotEmpty(request.Phone),
+		FirstName:  request.FirstName,
+		LastName:   http.StringToPointerIfNotEmpty(request.LastName),
+		Email:      http.StringToPointerIfNotEmpty(request.Email),
+		Phone:      http.StringToPointerIfNotEmpty(request.Phone),
+		CompanyID:  request.CompanyID,
+		JobTitle:   http.StringToPointerIfNotEmpty(request.JobTitle),
+		Department: http.StringToPointerIfNotEmpty(request.Department),
 	}
 
-	updatedContact, err := usecase.contactRepository.UpdateByID(ctx, username, id, contact)
+	phoneE164, err := usecase.normalizePhone(ctx, username, request.Phone)
 	if err != nil {
 		return models.ContactResponse{}, err
 	}
+	contact.PhoneE164 = phoneE164
 
-	return models.ContactResponse{
-		ID:        updatedContact.ID,
-		FirstName: updatedContact.FirstName,
-		LastName:  http.PointerToString(updatedContact.LastName),
-		Email:     http.PointerToString(updatedContact.Email),
-		Phone:     http.PointerToString(updatedContact.Phone),
-	}, nil
+	updatedContact, err := usecase.contactRepository.UpdateByID(ctx, username, id, version, contact)
+	if err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	return ToContactResponse(username, updatedContact), nil
 }
 
-func (usecase *contactUsecaseImpl) FindByID(ctx context.Context, username string, id int) (models.ContactResponse, error) {
+// Patch applies a merge patch or JSON patch to the stored contact. The patch
+// is computed against the version that was read, so a concurrent change
+// fails it with ErrVersionMismatch rather than being overwritten.
+func (usecase *contactUsecaseImpl) Patch(ctx context.Context, username string, id int, version int, patch jsonpatch.Patch) (models.ContactResponse, error) {
+	current, err := usecase.contactRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	if version == 0 {
+		version = current.Version
+	} else if version != current.Version {
+		return models.ContactResponse{}, domain.ErrVersionMismatch
+	}
+
+	doc, err := json.Marshal(models.ContactPatchDocument{
+		FirstName:  current.FirstName,
+		LastName:   current.LastName,
+		Email:      current.Email,
+		Phone:      current.Phone,
+		CompanyID:  current.CompanyID,
+		JobTitle:   current.JobTitle,
+		Department: current.Department,
+	})
+	if err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	patched, err := patch.Apply(doc)
+	if err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	var document models.ContactPatchDocument
+	if err := jsonpatch.Unmarshal(patched, &document); err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	if err := usecase.validator.Struct(document); err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	contact := entities.Contact{
+		FirstName:  document.FirstName,
+		LastName:   http.StringToPointerIfNotEmpty(http.PointerToString(document.LastName)),
+		Email:      http.StringToPointerIfNotEmpty(http.PointerToString(document.Email)),
+		Phone:      http.StringToPointerIfNotEmpty(http.PointerToString(document.Phone)),
+		CompanyID:  document.CompanyID,
+		JobTitle:   http.StringToPointerIfNotEmpty(http.PointerToString(document.JobTitle)),
+		Department: http.StringToPointerIfNotEmpty(http.PointerToString(document.Department)),
+	}
+
+	phoneE164, err := usecase.normalizePhone(ctx, username, http.PointerToString(contact.Phone))
+	if err != nil {
+		return models.ContactResponse{}, err
+	}
+	contact.PhoneE164 = phoneE164
+
+	updatedContact, err := usecase.contactRepository.ReplaceByID(ctx, username, id, version, contact)
+	if err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	return ToContactResponse(username, updatedContact), nil
+}
+
+func (usecase *contactUsecaseImpl) FindByID(ctx context.Context, username string, id int, options models.ContactReadOptions) (models.ContactResponse, error) {
+	if err := validateReadOptions(options); err != nil {
+		return models.ContactResponse{}, err
+	}
+
 	retrievedContact, err := usecase.contactRepository.FindByID(ctx, username, id)
 	if err != nil {
 		return models.ContactResponse{}, err
 	}
-	return models.ContactResponse{
-		ID:        retrievedContact.ID,
-		FirstName: retrievedContact.FirstName,
-		LastName:  http.PointerToString(retrievedContact.LastName),
-		Email:     http.PointerToString(retrievedContact.Email),
-		Phone:     http.PointerToString(retrievedContact.Phone),
-	}, nil
 
+	responses := []models.ContactResponse{ToContactResponse(username, retrievedContact)}
+	if err := usecase.embed(ctx, responses, options.Include); err != nil {
+		return models.ContactResponse{}, err
+	}
+
+	return responses[0], nil
 }
 
-func (usecase *contactUsecaseImpl) DeleteByID(ctx context.Context, username string, id int) error {
-	return usecase.contactRepository.DeleteByID(ctx, username, id)
+func (usecase *contactUsecaseImpl) DeleteByID(ctx context.Context, username string, id int, version int) error {
+	return usecase.contactRepository.DeleteByID(ctx, username, id, version)
 }
 
 func (usecase *contactUsecaseImpl) Search(ctx context.Context, username string, query models.ContactSearchQuery) ([]models.ContactResponse, models.Paging, error) {
+	if err := validateReadOptions(query.ContactReadOptions); err != nil {
+		return nil, models.Paging{}, err
+	}
+
+	if err := usecase.resolvePhoneQuery(ctx, username, &query); err != nil {
+		return nil, models.Paging{}, err
+	}
+
 	results, total, err := usecase.contactRepository.Search(ctx, username, query)
 	if err != nil {
 		return nil, models.Paging{}, err
@@ -102,13 +198,13 @@ func (usecase *contactUsecaseImpl) Search(ctx context.Context, username string,
 
 	responses := make([]models.ContactResponse, len(results))
 	for i, result := range results {
-		responses[i] = models.ContactResponse{
-			ID:        result.ID,
-			FirstName: result.FirstName,
-			LastName:  http.PointerToString(result.LastName),
-			Email:     http.PointerToString(result.Email),
-			Phone:     http.PointerToString(result.Phone),
-		}
+		responses[i] = ToContactResponse(username, result)
+		responses[i].Rank = result.Rank
+		responses[i].Snippet = result.Snippet
+	}
+
+	if err := usecase.embed(ctx, responses, query.Include); err != nil {
+		return nil, models.Paging{}, err
 	}
 
 	totalPage := int(math.Ceil(float64(total) / float64(query.Size)))
@@ -125,3 +221,247 @@ func (usecase *contactUsecaseImpl) Search(ctx context.Context, username string,
 	return responses, paging, nil
 
 }
+
+func (usecase *contactUsecaseImpl) Export(ctx context.Context, username string, query models.ContactExportQuery, fn func(row models.ContactExportRow) error) error {
+	if query.Format != models.ExportFormatCSV && query.Format != models.ExportFormatJSONL {
+		return domain.ErrUnsupportedExportFormat
+	}
+
+	batch := make([]entities.Contact, 0, exportBatchSize)
+	flush := func() error {
+		addresses := map[int][]addressEntities.Address{}
+		if query.IncludeAddresses {
+			ids := make([]int, len(batch))
+			for i, contact := range batch {
+				ids[i] = contact.ID
+			}
+
+			var err error
+			if addresses, err = usecase.contactRepository.FindAddressesByContactIDs(ctx, ids); err != nil {
+				return err
+			}
+		}
+
+		for _, contact := range batch {
+			row := models.ContactExportRow{
+				ContactResponse: ToContactResponse(username, contact),
+			}
+			if len(addresses[contact.ID]) > 0 {
+				row.Addresses = toAddressResponses(addresses[contact.ID])
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	if err := usecase.resolvePhoneQuery(ctx, username, &query.ContactSearchQuery); err != nil {
+		return err
+	}
+
+	err := usecase.contactRepository.Stream(ctx, username, query.ContactSearchQuery, func(contact entities.Contact) error {
+		batch = append(batch, contact)
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// CountEach counts the contacts matching each of queries.
+func (usecase *contactUsecaseImpl) CountEach(ctx context.Context, username string, queries []models.ContactSearchQuery) ([]int, error) {
+	queries = append([]models.ContactSearchQuery(nil), queries...)
+	if err := usecase.resolvePhoneQueries(ctx, username, queries); err != nil {
+		return nil, err
+	}
+
+	return usecase.contactRepository.CountEach(ctx, username, queries)
+}
+
+func (usecase *contactUsecaseImpl) SearchCursor(ctx context.Context, username string, query models.ContactSearchQuery) ([]models.ContactResponse, models.CursorPaging, error) {
+	if err := validateReadOptions(query.ContactReadOptions); err != nil {
+		return nil, models.CursorPaging{}, err
+	}
+
+	if err := usecase.resolvePhoneQuery(ctx, username, &query); err != nil {
+		return nil, models.CursorPaging{}, err
+	}
+
+	results, nextCursor, hasMore, err := usecase.contactRepository.SearchCursor(ctx, username, query)
+	if err != nil {
+		return nil, models.CursorPaging{}, err
+	}
+
+	responses := make([]models.ContactResponse, len(results))
+	for i, result := range results {
+		responses[i] = ToContactResponse(username, result)
+	}
+
+	if err := usecase.embed(ctx, responses, query.Include); err != nil {
+		return nil, models.CursorPaging{}, err
+	}
+
+	return responses, models.CursorPaging{
+		Size:       len(responses),
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}, nil
+}
+
+func (usecase *contactUsecaseImpl) Bulk(ctx context.Context, username string, request models.ContactBulkRequest) (models.ContactBulkResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.ContactBulkResponse{}, err
+	}
+
+	var results []models.ContactBulkResult
+	if !request.Atomic {
+		results = usecase.runBulk(ctx, username, request.Operations, false)
+	} else {
+		errBulkAborted := errors.New("bulk aborted")
+		err := usecase.contactRepository.Transaction(ctx, func(repository repository.ContactRepository) error {
+			transactional := &contactUsecaseImpl{contactRepository: repository, userRepository: usecase.userRepository, validator: usecase.validator}
+			results = transactional.runBulk(ctx, username, request.Operations, true)
+			for _, result := range results {
+				if result.Err != nil {
+					return errBulkAborted
+				}
+			}
+			return nil
+		})
+
+		if err != nil && !errors.Is(err, errBulkAborted) {
+			return models.ContactBulkResponse{}, err
+		}
+
+		if err != nil {
+			for i := range results {
+				if results[i].Err == nil || errors.Is(results[i].Err, domain.ErrBulkRolledBack) {
+					results[i].Data = nil
+					results[i].Err = domain.ErrBulkRolledBack
+				}
+			}
+		}
+	}
+
+	response := models.ContactBulkResponse{Atomic: request.Atomic, Results: results}
+	for _, result := range results {
+		if result.Err == nil {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
+// runBulk executes the operations one by one through the single-item
+// methods, so validation and persistence match the regular endpoints. When
+// stopOnError is set, the operations after the first failure are skipped.
+func (usecase *contactUsecaseImpl) runBulk(ctx context.Context, username string, operations []models.ContactBulkOperation, stopOnError bool) []models.ContactBulkResult {
+	results := make([]models.ContactBulkResult, len(operations))
+	failed := false
+
+	for i, operation := range operations {
+		results[i] = models.ContactBulkResult{Index: i, Op: operation.Op, ID: operation.ID}
+		if failed && stopOnError {
+			results[i].Err = domain.ErrBulkRolledBack
+			continue
+		}
+
+		response, err := usecase.runBulkOperation(ctx, username, operation)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+
+		if response != nil {
+			results[i].ID = response.ID
+			results[i].Data = response
+		}
+	}
+
+	return results
+}
+
+func (usecase *contactUsecaseImpl) runBulkOperation(ctx context.Context, username string, operation models.ContactBulkOperation) (*models.ContactResponse, error) {
+	if operation.Op != models.BulkOperationCreate && operation.ID == 0 {
+		return nil, fmt.Errorf("%w: id is required for %s", domain.ErrBulkInvalidOperation, operation.Op)
+	}
+
+	switch operation.Op {
+	case models.BulkOperationCreate:
+		var request models.ContactCreateRequest
+		if err := unmarshalBulkData(operation.Data, &request); err != nil {
+			return nil, err
+		}
+		response, err := usecase.Create(ctx, username, request)
+		if err != nil {
+			return nil, err
+		}
+		return &response, nil
+	case models.BulkOperationUpdate:
+		var request models.ContactUpdateRequest
+		if err := unmarshalBulkData(operation.Data, &request); err != nil {
+			return nil, err
+		}
+		response, err := usecase.Update(ctx, username, operation.ID, 0, request)
+		if err != nil {
+			return nil, err
+		}
+		return &response, nil
+	case models.BulkOperationDelete:
+		return nil, usecase.DeleteByID(ctx, username, operation.ID, 0)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", domain.ErrBulkInvalidOperation, operation.Op)
+	}
+}
+
+// ToContactResponse returns contact as seen by username, who sees who
+// shared it with them.
+func ToContactResponse(username string, contact entities.Contact) models.ContactResponse {
+	return models.ContactResponse{
+		ID:         contact.ID,
+		FirstName:  contact.FirstName,
+		LastName:   http.PointerToString(contact.LastName),
+		Email:      http.PointerToString(contact.Email),
+		Phone:      http.PointerToString(contact.Phone),
+		PhoneE164:  http.PointerToString(contact.PhoneE164),
+		CompanyID:  http.PointerToInt(contact.CompanyID),
+		JobTitle:   http.PointerToString(contact.JobTitle),
+		Department: http.PointerToString(contact.Department),
+		SharedBy:   sharedBy(username, contact),
+		CreatedAt:  contact.CreatedAt,
+		UpdatedAt:  contact.UpdatedAt,
+		CreatedBy:  contact.CreatedBy,
+		UpdatedBy:  contact.UpdatedBy,
+		Version:    contact.Version,
+	}
+}
+
+// sharedBy names the owner of contacts that were shared with the user.
+func sharedBy(username string, contact entities.Contact) string {
+	if contact.Username == username || contact.OrganizationID != nil {
+		return ""
+	}
+	return contact.Username
+}
+
+func unmarshalBulkData(data json.RawMessage, dst any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: data is required", domain.ErrBulkInvalidOperation)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("%w: invalid data", domain.ErrBulkInvalidOperation)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrImportNotFound         = errors.New("import not found")
+	ErrImportEmptyFile        = errors.New("import file is empty")
+	ErrImportNoHeader         = errors.New("import file has no header row")
+	ErrImportInvalidMapping   = errors.New("import mapping is invalid")
+	ErrImportAlreadyCommitted = errors.New("import has already been committed")
+	ErrImportInterrupted      = errors.New("import was interrupted")
+)
@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+)
+
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ContactImport struct {
+	ID            string            `json:"id" gorm:"column:id;primaryKey;size:36"`
+	Username      string            `json:"username" gorm:"column:username;size:255;not null;index"`
+	FileName      string            `json:"file_name" gorm:"column:file_name;size:255"`
+	Delimiter     string            `json:"delimiter" gorm:"column:delimiter;size:1;not null"`
+	Encoding      string            `json:"encoding" gorm:"column:encoding;size:20;not null"`
+	Headers       []string          `json:"headers" gorm:"column:headers;type:jsonb;serializer:json"`
+	Mapping       map[string]string `json:"mapping" gorm:"column:mapping;type:jsonb;serializer:json"`
+	Content       string            `json:"-" gorm:"column:content;type:text;not null"`
+	Status        string            `json:"status" gorm:"column:status;size:20;not null"`
+	TotalRows     int               `json:"total_rows" gorm:"column:total_rows;not null;default:0"`
+	ProcessedRows int               `json:"processed_rows" gorm:"column:processed_rows;not null;default:0"`
+	ImportedRows  int               `json:"imported_rows" gorm:"column:imported_rows;not null;default:0"`
+	FailedRows    int               `json:"failed_rows" gorm:"column:failed_rows;not null;default:0"`
+	Errors        []RowError        `json:"errors" gorm:"column:errors;type:jsonb;serializer:json"`
+	FailureReason *string           `json:"failure_reason,omitempty" gorm:"column:failure_reason;size:1000"`
+	CreatedAt     time.Time         `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time         `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ContactImport) TableName() string {
+	return "contact_imports"
+}
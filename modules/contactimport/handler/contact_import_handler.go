@@ -0,0 +1,11 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type ContactImportHandler interface {
+	Upload(ctx *fiber.Ctx) error
+	FindByID(ctx *fiber.Ctx) error
+	UpdateMapping(ctx *fiber.Ctx) error
+	Preview(ctx *fiber.Ctx) error
+	Commit(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/contactimport/domain"
+	"golang-contact-management-restful-api/modules/contactimport/models"
+	"golang-contact-management-restful-api/modules/contactimport/usecase"
+	"io"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type contactImportHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.ContactImportUsecase
+	validate *validator.Validate
+}
+
+func NewContactImportHttpHandler(app *fiber.App, usecase usecase.ContactImportUsecase) ContactImportHandler {
+	return &contactImportHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *contactImportHandlerHttp) Upload(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	request := models.ImportUploadRequest{}
+	if fileHeader, err := ctx.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "invalid upload"})
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "invalid upload"})
+		}
+		request.FileName = fileHeader.Filename
+		request.Content = content
+	} else {
+		request.Content = append([]byte(nil), ctx.Body()...)
+	}
+
+	response, err := handler.usecase.Upload(ctx.Context(), username, request)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.ImportResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactImportHandlerHttp) FindByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.FindByID(ctx.Context(), username, ctx.Params("importId"))
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ImportResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactImportHandlerHttp) UpdateMapping(ctx *fiber.Ctx) error {
+	var request models.ImportMappingRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.UpdateMapping(ctx.Context(), username, ctx.Params("importId"), request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ImportResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactImportHandlerHttp) Preview(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Preview(ctx.Context(), username, ctx.Params("importId"))
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ImportPreviewResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactImportHandlerHttp) Commit(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Commit(ctx.Context(), username, ctx.Params("importId"))
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(http.DataEnvelope[models.ImportResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactImportHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrImportNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrImportAlreadyCommitted):
+		return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "golang-contact-management-restful-api/modules/contactimport/entities"
+
+type ImportUploadRequest struct {
+	FileName string
+	Content  []byte
+}
+
+type ImportMappingRequest struct {
+	Mapping map[string]string `json:"mapping" validate:"required,min=1"`
+}
+
+type ImportResponse struct {
+	ID            string              `json:"id"`
+	FileName      string              `json:"file_name"`
+	Delimiter     string              `json:"delimiter"`
+	Encoding      string              `json:"encoding"`
+	Headers       []string            `json:"headers"`
+	Mapping       map[string]string   `json:"mapping"`
+	Status        string              `json:"status"`
+	TotalRows     int                 `json:"total_rows"`
+	ProcessedRows int                 `json:"processed_rows"`
+	ImportedRows  int                 `json:"imported_rows"`
+	FailedRows    int                 `json:"failed_rows"`
+	Progress      float64             `json:"progress"`
+	Errors        []entities.RowError `json:"errors"`
+	FailureReason string              `json:"failure_reason,omitempty"`
+}
+
+type ImportPreviewRow struct {
+	Row     int               `json:"row"`
+	Values  map[string]string `json:"values"`
+	Valid   bool              `json:"valid"`
+	Address bool              `json:"address"`
+}
+
+type ImportPreviewResponse struct {
+	TotalRows   int                 `json:"total_rows"`
+	ValidRows   int                 `json:"valid_rows"`
+	InvalidRows int                 `json:"invalid_rows"`
+	Errors      []entities.RowError `json:"errors"`
+	Sample      []ImportPreviewRow  `json:"sample"`
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/contactimport/entities"
+	"time"
+)
+
+type ContactImportRepository interface {
+	Save(ctx context.Context, contactImport entities.ContactImport) (entities.ContactImport, error)
+	FindByID(ctx context.Context, username string, id string) (entities.ContactImport, error)
+	UpdateMapping(ctx context.Context, username string, id string, mapping map[string]string) (entities.ContactImport, error)
+	MarkProcessing(ctx context.Context, username string, id string) error
+	UpdateProgress(ctx context.Context, id string, progress entities.ContactImport) error
+	FailStalled(ctx context.Context, before time.Time, reason string) error
+}
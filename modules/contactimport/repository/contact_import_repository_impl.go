@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/modules/contactimport/domain"
+	"golang-contact-management-restful-api/modules/contactimport/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type contactImportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewContactImportRepository(db *gorm.DB) ContactImportRepository {
+	return &contactImportRepositoryImpl{DB: db}
+}
+
+func (repository *contactImportRepositoryImpl) Save(ctx context.Context, contactImport entities.ContactImport) (entities.ContactImport, error) {
+	if err := repository.DB.WithContext(ctx).Create(&contactImport).Error; err != nil {
+		return entities.ContactImport{}, err
+	}
+	return contactImport, nil
+}
+
+func (repository *contactImportRepositoryImpl) FindByID(ctx context.Context, username string, id string) (entities.ContactImport, error) {
+	var contactImport entities.ContactImport
+	if err := repository.DB.WithContext(ctx).Take(&contactImport, "id = ? AND username = ?", id, username).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ContactImport{}, domain.ErrImportNotFound
+		}
+		return entities.ContactImport{}, err
+	}
+	return contactImport, nil
+}
+
+func (repository *contactImportRepositoryImpl) UpdateMapping(ctx context.Context, username string, id string, mapping map[string]string) (entities.ContactImport, error) {
+	result := repository.DB.WithContext(ctx).Model(&entities.ContactImport{}).
+		Where("id = ? AND username = ? AND status = ?", id, username, entities.StatusPending).
+		Select("mapping").
+		Updates(&entities.ContactImport{Mapping: mapping})
+
+	if result.Error != nil {
+		return entities.ContactImport{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		existing, err := repository.FindByID(ctx, username, id)
+		if err != nil {
+			return entities.ContactImport{}, err
+		}
+		if existing.Status != entities.StatusPending {
+			return entities.ContactImport{}, domain.ErrImportAlreadyCommitted
+		}
+	}
+
+	return repository.FindByID(ctx, username, id)
+}
+
+func (repository *contactImportRepositoryImpl) MarkProcessing(ctx context.Context, username string, id string) error {
+	result := repository.DB.WithContext(ctx).Model(&entities.ContactImport{}).
+		Where("id = ? AND username = ? AND status = ?", id, username, entities.StatusPending).
+		Update("status", entities.StatusProcessing)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := repository.FindByID(ctx, username, id); err != nil {
+			return err
+		}
+		return domain.ErrImportAlreadyCommitted
+	}
+
+	return nil
+}
+
+// UpdateProgress stores the counters, errors and status of an import. Its
+// updated_at tells running imports apart from stalled ones.
+func (repository *contactImportRepositoryImpl) UpdateProgress(ctx context.Context, id string, progress entities.ContactImport) error {
+	return repository.DB.WithContext(ctx).Model(&entities.ContactImport{ID: id}).
+		Select("status", "processed_rows", "imported_rows", "failed_rows", "errors", "failure_reason", "updated_at").
+		Updates(&progress).Error
+}
+
+// FailStalled fails the processing imports without progress since before.
+func (repository *contactImportRepositoryImpl) FailStalled(ctx context.Context, before time.Time, reason string) error {
+	return repository.DB.WithContext(ctx).Model(&entities.ContactImport{}).
+		Where("status = ? AND updated_at < ?", entities.StatusProcessing, before).
+		Updates(map[string]any{
+			"status":         entities.StatusFailed,
+			"failure_reason": reason,
+		}).Error
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/contactimport/models"
+)
+
+type ContactImportUsecase interface {
+	Upload(ctx context.Context, username string, request models.ImportUploadRequest) (models.ImportResponse, error)
+	FindByID(ctx context.Context, username string, id string) (models.ImportResponse, error)
+	UpdateMapping(ctx context.Context, username string, id string, request models.ImportMappingRequest) (models.ImportResponse, error)
+	Preview(ctx context.Context, username string, id string) (models.ImportPreviewResponse, error)
+	Commit(ctx context.Context, username string, id string) (models.ImportResponse, error)
+	FailStalled(ctx context.Context) error
+}
@@ -0,0 +1,516 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang-contact-management-restful-api/internal/requestinfo"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/internal/workspace"
+	addressDomain "golang-contact-management-restful-api/modules/address/domain"
+	addressModels "golang-contact-management-restful-api/modules/address/models"
+	addressUsecase "golang-contact-management-restful-api/modules/address/usecase"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	"golang-contact-management-restful-api/modules/contactimport/domain"
+	"golang-contact-management-restful-api/modules/contactimport/entities"
+	"golang-contact-management-restful-api/modules/contactimport/models"
+	"golang-contact-management-restful-api/modules/contactimport/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+const (
+	previewSampleSize = 20
+	progressInterval  = 25
+	maxStoredErrors   = 1000
+
+	// progressAttempts is how often the final progress of an import is
+	// written before giving up.
+	progressAttempts = 3
+	// StallTimeout is how long a processing import may go without progress
+	// before it is considered interrupted, for example by a restart.
+	StallTimeout = 15 * time.Minute
+)
+
+// rowErrorCauses are the errors caused by the data of a row. Any other error
+// failing a row, such as a database outage, would fail every row, so it
+// fails the import instead.
+var rowErrorCauses = []error{
+	contactDomain.ErrContactNotFound,
+	contactDomain.ErrContactReadOnly,
+	contactDomain.ErrInvalidPhone,
+	contactDomain.ErrDuplicateEmail,
+	contactDomain.ErrCompanyNotFound,
+	addressDomain.ErrAddressNotFound,
+}
+
+type contactImportUsecaseImpl struct {
+	contactImportRepository repository.ContactImportRepository
+	contactUsecase          contactUsecase.ContactUsecase
+	addressUsecase          addressUsecase.AddressUsecase
+	validator               *validator.Validate
+	onError                 func(err error)
+}
+
+// NewContactImportUsecase returns the import usecase. Errors of imports
+// running in the background that cannot be stored with the import are
+// passed to onError.
+func NewContactImportUsecase(contactImportRepository repository.ContactImportRepository, contactUsecase contactUsecase.ContactUsecase, addressUsecase addressUsecase.AddressUsecase, validator *validator.Validate, onError func(err error)) ContactImportUsecase {
+	return &contactImportUsecaseImpl{
+		contactImportRepository: contactImportRepository,
+		contactUsecase:          contactUsecase,
+		addressUsecase:          addressUsecase,
+		validator:               validator,
+		onError:                 onError,
+	}
+}
+
+type importRow struct {
+	number  int
+	values  map[string]string
+	contact contactModels.ContactCreateRequest
+	address *addressModels.AddressCreateRequest
+	errors  []entities.RowError
+}
+
+func (usecase *contactImportUsecaseImpl) Upload(ctx context.Context, username string, request models.ImportUploadRequest) (models.ImportResponse, error) {
+	if len(strings.TrimSpace(string(request.Content))) == 0 {
+		return models.ImportResponse{}, domain.ErrImportEmptyFile
+	}
+
+	content, encoding, err := decodeContent(request.Content)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	delimiter := detectDelimiter(content)
+	headers, rows, err := readRecords(content, delimiter)
+	if err != nil || len(headers) == 0 {
+		return models.ImportResponse{}, domain.ErrImportNoHeader
+	}
+
+	contactImport := entities.ContactImport{
+		ID:        uuid.NewString(),
+		Username:  username,
+		FileName:  request.FileName,
+		Delimiter: string(delimiter),
+		Encoding:  encoding,
+		Headers:   headers,
+		Mapping:   proposeMapping(headers),
+		Content:   content,
+		Status:    entities.StatusPending,
+		TotalRows: len(rows),
+		Errors:    []entities.RowError{},
+	}
+
+	saved, err := usecase.contactImportRepository.Save(ctx, contactImport)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	return toImportResponse(saved), nil
+}
+
+func (usecase *contactImportUsecaseImpl) FindByID(ctx context.Context, username string, id string) (models.ImportResponse, error) {
+	contactImport, err := usecase.contactImportRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	return toImportResponse(contactImport), nil
+}
+
+func (usecase *contactImportUsecaseImpl) UpdateMapping(ctx context.Context, username string, id string, request models.ImportMappingRequest) (models.ImportResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	contactImport, err := usecase.contactImportRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	if err := validateMapping(contactImport.Headers, request.Mapping); err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	mapping := map[string]string{}
+	for header, field := range request.Mapping {
+		if field != "" {
+			mapping[header] = field
+		}
+	}
+
+	updated, err := usecase.contactImportRepository.UpdateMapping(ctx, username, id, mapping)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	return toImportResponse(updated), nil
+}
+
+func (usecase *contactImportUsecaseImpl) Preview(ctx context.Context, username string, id string) (models.ImportPreviewResponse, error) {
+	contactImport, err := usecase.contactImportRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ImportPreviewResponse{}, err
+	}
+
+	rows, err := usecase.prepareRows(contactImport)
+	if err != nil {
+		return models.ImportPreviewResponse{}, err
+	}
+
+	response := models.ImportPreviewResponse{
+		TotalRows: len(rows),
+		Errors:    []entities.RowError{},
+		Sample:    []models.ImportPreviewRow{},
+	}
+
+	for _, row := range rows {
+		if len(row.errors) == 0 {
+			response.ValidRows++
+		} else {
+			response.InvalidRows++
+			response.Errors = append(response.Errors, row.errors...)
+		}
+
+		if len(response.Sample) < previewSampleSize {
+			response.Sample = append(response.Sample, models.ImportPreviewRow{
+				Row:     row.number,
+				Values:  row.values,
+				Valid:   len(row.errors) == 0,
+				Address: row.address != nil,
+			})
+		}
+	}
+
+	return response, nil
+}
+
+func (usecase *contactImportUsecaseImpl) Commit(ctx context.Context, username string, id string) (models.ImportResponse, error) {
+	contactImport, err := usecase.contactImportRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	rows, err := usecase.prepareRows(contactImport)
+	if err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	if err := usecase.contactImportRepository.MarkProcessing(ctx, username, id); err != nil {
+		return models.ImportResponse{}, err
+	}
+
+	contactImport.Status = entities.StatusProcessing
+	go usecase.process(workspace.FromContext(ctx), requestinfo.FromContext(ctx), contactImport, rows)
+
+	return toImportResponse(contactImport), nil
+}
+
+// process imports the prepared rows in the background through the contact
+// and address usecases, so imported data follows the same rules as the API.
+// Contacts are created in the workspace the import was committed in, and
+// audited as part of the request that committed it. The import fails, with
+// the rows imported so far, on the first error that is not about a row, or
+// when processing panics.
+func (usecase *contactImportUsecaseImpl) process(current workspace.Workspace, info requestinfo.Info, contactImport entities.ContactImport, rows []importRow) {
+	ctx := requestinfo.NewContext(workspace.NewContext(context.Background(), current), info)
+
+	progress := entities.ContactImport{
+		Status: entities.StatusProcessing,
+		Errors: []entities.RowError{},
+	}
+
+	fail := func(err error) {
+		usecase.onError(fmt.Errorf("import %s failed: %w", contactImport.ID, err))
+
+		reason := err.Error()
+		if len(reason) > 1000 {
+			reason = reason[:1000]
+		}
+		progress.Status = entities.StatusFailed
+		progress.FailureReason = &reason
+		usecase.finish(ctx, contactImport.ID, progress)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fail(fmt.Errorf("%w: %v", domain.ErrImportInterrupted, recovered))
+		}
+	}()
+
+	for i, row := range rows {
+		rowErrors := row.errors
+		if len(rowErrors) == 0 {
+			var err error
+			if rowErrors, err = usecase.importRow(ctx, contactImport.Username, row); err != nil {
+				fail(err)
+				return
+			}
+		}
+
+		progress.ProcessedRows++
+		if len(rowErrors) == 0 {
+			progress.ImportedRows++
+		} else {
+			progress.FailedRows++
+			for _, rowError := range rowErrors {
+				if len(progress.Errors) < maxStoredErrors {
+					progress.Errors = append(progress.Errors, rowError)
+				}
+			}
+		}
+
+		// A missed update only delays the progress shown, so it is not
+		// retried.
+		if (i+1)%progressInterval == 0 {
+			if err := usecase.contactImportRepository.UpdateProgress(ctx, contactImport.ID, progress); err != nil {
+				usecase.onError(fmt.Errorf("failed to store the progress of import %s: %w", contactImport.ID, err))
+			}
+		}
+	}
+
+	progress.Status = entities.StatusCompleted
+	usecase.finish(ctx, contactImport.ID, progress)
+}
+
+// finish stores the final progress of an import, retrying a few times as
+// the import would otherwise look stuck until it is failed as stalled.
+func (usecase *contactImportUsecaseImpl) finish(ctx context.Context, id string, progress entities.ContactImport) {
+	var err error
+	for attempt := 1; attempt <= progressAttempts; attempt++ {
+		if err = usecase.contactImportRepository.UpdateProgress(ctx, id, progress); err == nil {
+			return
+		}
+		if attempt < progressAttempts {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+	usecase.onError(fmt.Errorf("failed to store the result of import %s: %w", id, err))
+}
+
+// importRow creates the contact of a row and its address. Errors caused by
+// the row are returned as row errors, any other error as err.
+func (usecase *contactImportUsecaseImpl) importRow(ctx context.Context, username string, row importRow) ([]entities.RowError, error) {
+	saved, err := usecase.contactUsecase.Create(ctx, username, row.contact)
+	if err != nil {
+		if !isRowError(err) {
+			return nil, err
+		}
+		return []entities.RowError{{Row: row.number, Message: err.Error()}}, nil
+	}
+
+	if row.address == nil {
+		return nil, nil
+	}
+
+	if _, err := usecase.addressUsecase.Create(ctx, username, saved.ID, *row.address); err != nil {
+		if !isRowError(err) {
+			return nil, fmt.Errorf("contact %d was imported without its address: %w", saved.ID, err)
+		}
+		return []entities.RowError{{
+			Row:     row.number,
+			Field:   "address",
+			Message: fmt.Sprintf("contact %d was imported without its address: %s", saved.ID, err.Error()),
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+func isRowError(err error) bool {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return true
+	}
+	for _, cause := range rowErrorCauses {
+		if errors.Is(err, cause) {
+			return true
+		}
+	}
+	return false
+}
+
+// FailStalled fails the imports that made no progress for StallTimeout,
+// which were interrupted by a crash or restart.
+func (usecase *contactImportUsecaseImpl) FailStalled(ctx context.Context) error {
+	return usecase.contactImportRepository.FailStalled(ctx, time.Now().Add(-StallTimeout), domain.ErrImportInterrupted.Error())
+}
+
+// prepareRows maps every data row onto contact and address requests and runs
+// them through the same validation rules as the single-item endpoints.
+func (usecase *contactImportUsecaseImpl) prepareRows(contactImport entities.ContactImport) ([]importRow, error) {
+	if !hasField(contactImport.Mapping, FieldFirstName) {
+		return nil, fmt.Errorf("%w: column for %s is not mapped", domain.ErrImportInvalidMapping, FieldFirstName)
+	}
+
+	headers, records, err := readRecords(contactImport.Content, []rune(contactImport.Delimiter)[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, len(records))
+	for i, record := range records {
+		row := importRow{number: i + 2, values: map[string]string{}}
+		for column, header := range headers {
+			field, ok := contactImport.Mapping[header]
+			if !ok || column >= len(record) {
+				continue
+			}
+			if value := strings.TrimSpace(record[column]); value != "" {
+				row.values[field] = value
+			}
+		}
+
+		row.contact = contactModels.ContactCreateRequest{
+			FirstName: row.values[FieldFirstName],
+			LastName:  row.values[FieldLastName],
+			Email:     row.values[FieldEmail],
+			Phone:     row.values[FieldPhone],
+		}
+		row.errors = append(row.errors, usecase.validationErrors(row.number, "", row.contact)...)
+
+		if hasAddressValues(row.values) {
+			row.address = &addressModels.AddressCreateRequest{
+				Street:     row.values[FieldAddressStreet],
+				City:       row.values[FieldAddressCity],
+				Province:   row.values[FieldAddressProvince],
+				Country:    row.values[FieldAddressCountry],
+				PostalCode: row.values[FieldAddressPostalCode],
+			}
+			row.errors = append(row.errors, usecase.validationErrors(row.number, "address.", *row.address)...)
+		}
+
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+func (usecase *contactImportUsecaseImpl) validationErrors(number int, prefix string, request any) []entities.RowError {
+	err := usecase.validator.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []entities.RowError{{Row: number, Message: err.Error()}}
+	}
+
+	requestType := reflect.TypeOf(request)
+	rowErrors := make([]entities.RowError, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		field := fieldError.StructField()
+		if structField, ok := requestType.FieldByName(field); ok {
+			field = strings.Split(structField.Tag.Get("json"), ",")[0]
+		}
+		rowErrors[i] = entities.RowError{
+			Row:     number,
+			Field:   prefix + field,
+			Message: validationMessage(fieldError),
+		}
+	}
+
+	return rowErrors
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fieldError.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fieldError.Param())
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", fieldError.Tag())
+	}
+}
+
+func validateMapping(headers []string, mapping map[string]string) error {
+	known := map[string]bool{}
+	for _, header := range headers {
+		known[header] = true
+	}
+
+	used := map[string]string{}
+	for header, field := range mapping {
+		if !known[header] {
+			return fmt.Errorf("%w: unknown column %q", domain.ErrImportInvalidMapping, header)
+		}
+		if field == "" {
+			continue
+		}
+		if !isImportField(field) {
+			return fmt.Errorf("%w: unknown field %q, expected one of %s", domain.ErrImportInvalidMapping, field, strings.Join(importFields, ", "))
+		}
+		if other, ok := used[field]; ok {
+			return fmt.Errorf("%w: columns %q and %q both map to %s", domain.ErrImportInvalidMapping, other, header, field)
+		}
+		used[field] = header
+	}
+
+	if _, ok := used[FieldFirstName]; !ok {
+		return fmt.Errorf("%w: column for %s is not mapped", domain.ErrImportInvalidMapping, FieldFirstName)
+	}
+
+	return nil
+}
+
+func hasField(mapping map[string]string, field string) bool {
+	for _, mapped := range mapping {
+		if mapped == field {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAddressValues(values map[string]string) bool {
+	for field := range values {
+		if strings.HasPrefix(field, "address.") {
+			return true
+		}
+	}
+	return false
+}
+
+func toImportResponse(contactImport entities.ContactImport) models.ImportResponse {
+	progress := 0.0
+	if contactImport.TotalRows > 0 {
+		progress = float64(contactImport.ProcessedRows) / float64(contactImport.TotalRows)
+	}
+
+	rowErrors := contactImport.Errors
+	if rowErrors == nil {
+		rowErrors = []entities.RowError{}
+	}
+
+	return models.ImportResponse{
+		ID:            contactImport.ID,
+		FileName:      contactImport.FileName,
+		Delimiter:     contactImport.Delimiter,
+		Encoding:      contactImport.Encoding,
+		Headers:       contactImport.Headers,
+		Mapping:       contactImport.Mapping,
+		Status:        contactImport.Status,
+		TotalRows:     contactImport.TotalRows,
+		ProcessedRows: contactImport.ProcessedRows,
+		ImportedRows:  contactImport.ImportedRows,
+		FailedRows:    contactImport.FailedRows,
+		Progress:      progress,
+		Errors:        rowErrors,
+		FailureReason: http.PointerToString(contactImport.FailureReason),
+	}
+}
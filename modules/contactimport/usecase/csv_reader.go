@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	textunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const (
+	FieldFirstName         = "first_name"
+	FieldLastName          = "last_name"
+	FieldEmail             = "email"
+	FieldPhone             = "phone"
+	FieldAddressStreet     = "address.street"
+	FieldAddressCity       = "address.city"
+	FieldAddressProvince   = "address.province"
+	FieldAddressCountry    = "address.country"
+	FieldAddressPostalCode = "address.postal_code"
+)
+
+var importFields = []string{
+	FieldFirstName,
+	FieldLastName,
+	FieldEmail,
+	FieldPhone,
+	FieldAddressStreet,
+	FieldAddressCity,
+	FieldAddressProvince,
+	FieldAddressCountry,
+	FieldAddressPostalCode,
+}
+
+// fieldAliases lists normalised header names, as exported by Google Contacts,
+// Outlook and hand-made spreadsheets, that propose a mapping onto each field.
+var fieldAliases = map[string][]string{
+	FieldFirstName: {"firstname", "givenname", "first", "forename"},
+	FieldLastName:  {"lastname", "familyname", "surname", "last"},
+	FieldEmail: {"email", "emailaddress", "email1value", "email1address", "mail",
+		"primaryemail", "email2address", "email2value"},
+	FieldPhone: {"phone", "phonenumber", "phone1value", "mobile", "mobilephone", "mobilenumber",
+		"primaryphone", "homephone", "businessphone", "telephone", "tel"},
+	FieldAddressStreet: {"street", "streetaddress", "address", "address1street", "homestreet",
+		"businessstreet", "otherstreet"},
+	FieldAddressCity: {"city", "town", "address1city", "homecity", "businesscity", "othercity"},
+	FieldAddressProvince: {"province", "state", "region", "address1region", "homestate",
+		"businessstate", "otherstate"},
+	FieldAddressCountry: {"country", "countryregion", "address1country", "homecountryregion",
+		"businesscountryregion", "othercountryregion"},
+	FieldAddressPostalCode: {"postalcode", "postcode", "zip", "zipcode", "address1postalcode",
+		"homepostalcode", "businesspostalcode", "otherpostalcode"},
+}
+
+var delimiterCandidates = []rune{',', ';', '\t', '|'}
+
+// decodeContent converts the uploaded bytes to UTF-8, honouring UTF-8 and
+// UTF-16 byte order marks and falling back to Windows-1252 (the default of
+// Outlook exports) when the content is not valid UTF-8.
+func decodeContent(raw []byte) (string, string, error) {
+	switch {
+	case bytes.HasPrefix(raw, []byte{0xEF, 0xBB, 0xBF}):
+		return string(raw[3:]), "utf-8", nil
+	case bytes.HasPrefix(raw, []byte{0xFF, 0xFE}):
+		decoded, err := decodeWith(raw, textunicode.UTF16(textunicode.LittleEndian, textunicode.ExpectBOM).NewDecoder())
+		return decoded, "utf-16le", err
+	case bytes.HasPrefix(raw, []byte{0xFE, 0xFF}):
+		decoded, err := decodeWith(raw, textunicode.UTF16(textunicode.BigEndian, textunicode.ExpectBOM).NewDecoder())
+		return decoded, "utf-16be", err
+	case utf8.Valid(raw):
+		return string(raw), "utf-8", nil
+	default:
+		decoded, err := decodeWith(raw, charmap.Windows1252.NewDecoder())
+		return decoded, "windows-1252", err
+	}
+}
+
+func decodeWith(raw []byte, decoder transform.Transformer) (string, error) {
+	decoded, _, err := transform.Bytes(decoder, raw)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// detectDelimiter picks the candidate that splits the first lines of the
+// file into the same, largest number of columns.
+func detectDelimiter(content string) rune {
+	lines := strings.SplitN(content, "\n", 21)
+	if len(lines) > 20 {
+		lines = lines[:20]
+	}
+	sample := strings.Join(lines, "\n")
+
+	best, bestScore := ',', 0
+	for _, candidate := range delimiterCandidates {
+		reader := newCSVReader(sample, candidate)
+		header, err := reader.Read()
+		if err != nil || len(header) < 2 {
+			continue
+		}
+
+		score := len(header)
+		for {
+			record, err := reader.Read()
+			if err != nil {
+				break
+			}
+			if len(record) == len(header) {
+				score += len(header)
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	return best
+}
+
+func newCSVReader(content string, delimiter rune) *csv.Reader {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	return reader
+}
+
+// readRecords returns the header row and the data rows of the file, skipping
+// blank lines.
+func readRecords(content string, delimiter rune) ([]string, [][]string, error) {
+	reader := newCSVReader(content, delimiter)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		rows = append(rows, record)
+	}
+
+	return header, rows, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeHeader(header string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// proposeMapping maps every recognised header onto an import field. Each
+// field is claimed by the first matching column only.
+func proposeMapping(headers []string) map[string]string {
+	mapping := map[string]string{}
+	claimed := map[string]bool{}
+
+	for _, field := range importFields {
+		for _, alias := range fieldAliases[field] {
+			for _, header := range headers {
+				if _, taken := mapping[header]; taken || claimed[field] {
+					continue
+				}
+				if normalizeHeader(header) == alias {
+					mapping[header] = field
+					claimed[field] = true
+				}
+			}
+		}
+	}
+
+	return mapping
+}
+
+func isImportField(field string) bool {
+	for _, candidate := range importFields {
+		if candidate == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrVersionNotFound = errors.New("version not found")
+	ErrInvalidVersion  = errors.New("invalid version")
+	ErrDeletedVersion  = errors.New("cannot revert to a version where the contact was deleted")
+)
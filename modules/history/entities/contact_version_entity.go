@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+const (
+	ActionBaseline      = "baseline"
+	ActionCreate        = "create"
+	ActionUpdate        = "update"
+	ActionDelete        = "delete"
+	ActionAddressCreate = "address_create"
+	ActionAddressUpdate = "address_update"
+	ActionAddressDelete = "address_delete"
+	ActionRevert        = "revert"
+)
+
+// ContactSnapshot is the state of a contact and its addresses after a
+// change. Deleted contacts have no snapshot.
+type ContactSnapshot struct {
+	FirstName  string            `json:"first_name"`
+	LastName   *string           `json:"last_name,omitempty"`
+	Email      *string           `json:"email,omitempty"`
+	Phone      *string           `json:"phone,omitempty"`
+	PhoneE164  *string           `json:"phone_e164,omitempty"`
+	CompanyID  *int              `json:"company_id,omitempty"`
+	JobTitle   *string           `json:"job_title,omitempty"`
+	Department *string           `json:"department,omitempty"`
+	Addresses  []AddressSnapshot `json:"addresses"`
+}
+
+type AddressSnapshot struct {
+	ID         int     `json:"id"`
+	Street     *string `json:"street,omitempty"`
+	City       *string `json:"city,omitempty"`
+	Province   *string `json:"province,omitempty"`
+	Country    string  `json:"country"`
+	PostalCode string  `json:"postal_code"`
+}
+
+// FieldChange records one field before and after a change. Address fields
+// are named addresses.<id>.<field>, and whole addresses addresses.<id>.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// ContactVersion is one entry in a contact's history. Username and
+// OrganizationID copy the contact's owner so the history stays scoped after
+// the contact is deleted.
+type ContactVersion struct {
+	ID             int              `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContactID      int              `json:"contact_id" gorm:"column:contact_id;not null;uniqueIndex:idx_contact_versions_contact_version"`
+	Version        int              `json:"version" gorm:"column:version;not null;uniqueIndex:idx_contact_versions_contact_version"`
+	Action         string           `json:"action" gorm:"column:action;size:20;not null"`
+	Snapshot       *ContactSnapshot `json:"snapshot" gorm:"column:snapshot;type:jsonb;serializer:json"`
+	Changes        []FieldChange    `json:"changes" gorm:"column:changes;type:jsonb;serializer:json;not null"`
+	ChangedBy      string           `json:"changed_by" gorm:"column:changed_by;size:255;not null"`
+	Username       string           `json:"username" gorm:"column:username;size:255;not null"`
+	OrganizationID *int             `json:"organization_id,omitempty" gorm:"column:organization_id"`
+	CreatedAt      time.Time        `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (ContactVersion) TableName() string {
+	return "contact_versions"
+}
@@ -0,0 +1,8 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type ContactVersionHandler interface {
+	FindAll(ctx *fiber.Ctx) error
+	Revert(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/history/domain"
+	"golang-contact-management-restful-api/modules/history/models"
+	"golang-contact-management-restful-api/modules/history/usecase"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type contactVersionHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.ContactVersionUsecase
+	validate *validator.Validate
+}
+
+func NewContactVersionHttpHandler(app *fiber.App, usecase usecase.ContactVersionUsecase) ContactVersionHandler {
+	return &contactVersionHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *contactVersionHandlerHttp) FindAll(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	size, _ := strconv.Atoi(ctx.Query("size", "10"))
+
+	results, paging, err := handler.usecase.FindAll(ctx.Context(), username, contactID, page, size)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(struct {
+		Data   []models.VersionResponse `json:"data"`
+		Paging contactModels.Paging     `json:"paging"`
+	}{
+		Data:   results,
+		Paging: paging,
+	})
+}
+
+func (handler *contactVersionHandlerHttp) Revert(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	version, err := strconv.Atoi(ctx.Query("version"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: domain.ErrInvalidVersion.Error()})
+	}
+
+	response, err := handler.usecase.Revert(ctx.Context(), username, contactID, version)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[contactModels.ContactResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactVersionHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrVersionNotFound), errors.Is(err, contactDomain.ErrContactNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, contactDomain.ErrContactReadOnly):
+		return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrDeletedVersion), errors.Is(err, contactDomain.ErrDuplicateEmail):
+		return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
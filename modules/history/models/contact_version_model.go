@@ -0,0 +1,14 @@
+package models
+
+import (
+	"golang-contact-management-restful-api/modules/history/entities"
+	"time"
+)
+
+type VersionResponse struct {
+	Version   int                    `json:"version"`
+	Action    string                 `json:"action"`
+	ChangedBy string                 `json:"changed_by"`
+	CreatedAt time.Time              `json:"created_at"`
+	Changes   []entities.FieldChange `json:"changes"`
+}
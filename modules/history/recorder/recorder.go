@@ -0,0 +1,221 @@
+// Package recorder writes contact history. Repositories call it inside the
+// transaction that changes a contact or its addresses, after checking
+// access, so versions are never recorded for changes that roll back.
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	"golang-contact-management-restful-api/modules/history/entities"
+
+	"gorm.io/gorm"
+)
+
+// Baseline records the current state of a contact that has no history yet,
+// so the first change made after history was introduced can be reverted.
+// It must be called before the change.
+func Baseline(tx *gorm.DB, changedBy string, contactID int) error {
+	var count int64
+	if err := tx.Model(&entities.ContactVersion{}).Where("contact_id = ?", contactID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	contact, snapshot, err := Snapshot(tx, contactID)
+	if err != nil || snapshot == nil {
+		return err
+	}
+
+	return tx.Create(&entities.ContactVersion{
+		ContactID:      contactID,
+		Version:        1,
+		Action:         entities.ActionBaseline,
+		Snapshot:       snapshot,
+		Changes:        []entities.FieldChange{},
+		ChangedBy:      changedBy,
+		Username:       contact.Username,
+		OrganizationID: contact.OrganizationID,
+	}).Error
+}
+
+// Record stores the contact's current state as a new version with the
+// changes since the previous one. Updates that changed nothing are skipped.
+func Record(tx *gorm.DB, changedBy string, contactID int, action string) error {
+	contact, snapshot, err := Snapshot(tx, contactID)
+	if err != nil {
+		return err
+	}
+
+	var previous entities.ContactVersion
+	err = tx.Where("contact_id = ?", contactID).Order("version DESC").Take(&previous).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	changes := Diff(previous.Snapshot, snapshot)
+	if len(changes) == 0 && action != entities.ActionCreate && action != entities.ActionRevert {
+		return nil
+	}
+
+	version := entities.ContactVersion{
+		ContactID:      contactID,
+		Version:        previous.Version + 1,
+		Action:         action,
+		Snapshot:       snapshot,
+		Changes:        changes,
+		ChangedBy:      changedBy,
+		Username:       contact.Username,
+		OrganizationID: contact.OrganizationID,
+	}
+	if snapshot == nil {
+		version.Username = previous.Username
+		version.OrganizationID = previous.OrganizationID
+	}
+
+	return tx.Create(&version).Error
+}
+
+// Snapshot loads the contact and its addresses. The snapshot is nil when
+// the contact does not exist.
+func Snapshot(tx *gorm.DB, contactID int) (contactEntities.Contact, *entities.ContactSnapshot, error) {
+	var contact contactEntities.Contact
+	if err := tx.Where("id = ?", contactID).Take(&contact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return contactEntities.Contact{}, nil, nil
+		}
+		return contactEntities.Contact{}, nil, err
+	}
+
+	var addresses []addressEntities.Address
+	if err := tx.Where("contact_id = ?", contactID).Order("id ASC").Find(&addresses).Error; err != nil {
+		return contactEntities.Contact{}, nil, err
+	}
+
+	snapshot := &entities.ContactSnapshot{
+		FirstName:  contact.FirstName,
+		LastName:   contact.LastName,
+		Email:      contact.Email,
+		Phone:      contact.Phone,
+		PhoneE164:  contact.PhoneE164,
+		CompanyID:  contact.CompanyID,
+		JobTitle:   contact.JobTitle,
+		Department: contact.Department,
+		Addresses:  make([]entities.AddressSnapshot, len(addresses)),
+	}
+	for i, address := range addresses {
+		snapshot.Addresses[i] = entities.AddressSnapshot{
+			ID:         address.ID,
+			Street:     address.Street,
+			City:       address.City,
+			Province:   address.Province,
+			Country:    address.Country,
+			PostalCode: address.PostalCode,
+		}
+	}
+
+	return contact, snapshot, nil
+}
+
+// Diff lists the fields that differ between two snapshots; a nil snapshot
+// stands for a contact that does not exist.
+func Diff(before *entities.ContactSnapshot, after *entities.ContactSnapshot) []entities.FieldChange {
+	changes := []entities.FieldChange{}
+
+	beforeFields, afterFields := contactFields(before), contactFields(after)
+	for _, field := range []string{"first_name", "last_name", "email", "phone", "phone_e164", "company_id", "job_title", "department"} {
+		if beforeFields[field] != afterFields[field] {
+			changes = append(changes, entities.FieldChange{Field: field, Before: beforeFields[field], After: afterFields[field]})
+		}
+	}
+
+	beforeAddresses, afterAddresses := addressesByID(before), addressesByID(after)
+	for _, id := range addressIDs(before, after) {
+		previous, hadBefore := beforeAddresses[id]
+		current, hasAfter := afterAddresses[id]
+		name := fmt.Sprintf("addresses.%d", id)
+
+		switch {
+		case !hadBefore:
+			changes = append(changes, entities.FieldChange{Field: name, Before: nil, After: current})
+		case !hasAfter:
+			changes = append(changes, entities.FieldChange{Field: name, Before: previous, After: nil})
+		default:
+			previousFields, currentFields := addressFields(previous), addressFields(current)
+			for _, field := range []string{"street", "city", "province", "country", "postal_code"} {
+				if previousFields[field] != currentFields[field] {
+					changes = append(changes, entities.FieldChange{Field: name + "." + field, Before: previousFields[field], After: currentFields[field]})
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+func contactFields(snapshot *entities.ContactSnapshot) map[string]any {
+	if snapshot == nil {
+		return map[string]any{}
+	}
+	return map[string]any{
+		"first_name": snapshot.FirstName,
+		"last_name":  value(snapshot.LastName),
+		"email":      value(snapshot.Email),
+		"phone":      value(snapshot.Phone),
+		"phone_e164": value(snapshot.PhoneE164),
+		"company_id": value(snapshot.CompanyID),
+		"job_title":  value(snapshot.JobTitle),
+		"department": value(snapshot.Department),
+	}
+}
+
+func addressFields(address entities.AddressSnapshot) map[string]any {
+	return map[string]any{
+		"street":      value(address.Street),
+		"city":        value(address.City),
+		"province":    value(address.Province),
+		"country":     address.Country,
+		"postal_code": address.PostalCode,
+	}
+}
+
+func addressesByID(snapshot *entities.ContactSnapshot) map[int]entities.AddressSnapshot {
+	addresses := map[int]entities.AddressSnapshot{}
+	if snapshot != nil {
+		for _, address := range snapshot.Addresses {
+			addresses[address.ID] = address
+		}
+	}
+	return addresses
+}
+
+// addressIDs returns the ids of both snapshots' addresses in order, without
+// duplicates.
+func addressIDs(before *entities.ContactSnapshot, after *entities.ContactSnapshot) []int {
+	var ids []int
+	seen := map[int]bool{}
+	for _, snapshot := range []*entities.ContactSnapshot{before, after} {
+		if snapshot == nil {
+			continue
+		}
+		for _, address := range snapshot.Addresses {
+			if !seen[address.ID] {
+				seen[address.ID] = true
+				ids = append(ids, address.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// value dereferences optional fields so they compare by value and encode
+// as null when unset.
+func value[T comparable](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
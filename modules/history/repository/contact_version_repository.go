@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/history/entities"
+)
+
+type ContactVersionRepository interface {
+	FindAll(ctx context.Context, username string, contactID int, page int, size int) ([]entities.ContactVersion, int, error)
+	FindByVersion(ctx context.Context, username string, contactID int, version int) (entities.ContactVersion, error)
+	Revert(ctx context.Context, username string, contactID int, version int) error
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/internal/database"
+	"golang-contact-management-restful-api/internal/workspace"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	"golang-contact-management-restful-api/modules/history/domain"
+	"golang-contact-management-restful-api/modules/history/entities"
+	"golang-contact-management-restful-api/modules/history/recorder"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	syncEntities "golang-contact-management-restful-api/modules/sync/entities"
+
+	"gorm.io/gorm"
+)
+
+type contactVersionRepositoryImpl struct {
+	DB      *gorm.DB
+	members *contactRepository.Members
+}
+
+func NewContactVersionRepository(db *gorm.DB, members *contactRepository.Members) ContactVersionRepository {
+	return &contactVersionRepositoryImpl{DB: db, members: members}
+}
+
+func (repository *contactVersionRepositoryImpl) FindAll(ctx context.Context, username string, contactID int, page int, size int) ([]entities.ContactVersion, int, error) {
+	db := repository.visible(ctx, username).Where("contact_versions.contact_id = ?", contactID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, contactDomain.ErrContactNotFound
+	}
+
+	var versions []entities.ContactVersion
+	if err := db.Order("contact_versions.version DESC").
+		Offset((page - 1) * size).Limit(size).
+		Find(&versions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return versions, int(total), nil
+}
+
+func (repository *contactVersionRepositoryImpl) FindByVersion(ctx context.Context, username string, contactID int, version int) (entities.ContactVersion, error) {
+	var contactVersion entities.ContactVersion
+	if err := repository.visible(ctx, username).
+		Where("contact_versions.contact_id = ? AND contact_versions.version = ?", contactID, version).
+		Take(&contactVersion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ContactVersion{}, domain.ErrVersionNotFound
+		}
+		return entities.ContactVersion{}, err
+	}
+	return contactVersion, nil
+}
+
+// Revert restores the contact and its addresses to the state recorded in
+// version, and records the result as a new version. A deleted contact is
+// recreated with its original id.
+func (repository *contactVersionRepositoryImpl) Revert(ctx context.Context, username string, contactID int, version int) error {
+	target, err := repository.FindByVersion(ctx, username, contactID, version)
+	if err != nil {
+		return err
+	}
+	if target.Snapshot == nil {
+		return domain.ErrDeletedVersion
+	}
+	snapshot := target.Snapshot
+
+	err = repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := recorder.Baseline(tx, username, contactID); err != nil {
+			return err
+		}
+
+		companyID, err := existingCompany(tx, snapshot.CompanyID)
+		if err != nil {
+			return err
+		}
+
+		writable, args := contactRepository.WritableBy(ctx, username)
+		result := tx.Model(&contactEntities.Contact{}).Where("id = ?", contactID).Where(writable, args...).
+			Updates(map[string]any{
+				"first_name": snapshot.FirstName,
+				"last_name":  snapshot.LastName,
+				"email":      snapshot.Email,
+				"phone":      snapshot.Phone,
+				"phone_e164": snapshot.PhoneE164,
+				"company_id": companyID,
+				"job_title":  snapshot.JobTitle,
+				"department": snapshot.Department,
+				"version":    gorm.Expr("version + 1"),
+				"updated_by": username,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		action := syncEntities.ActionUpdate
+		if result.RowsAffected == 0 {
+			if err := repository.restore(ctx, tx, username, target, companyID); err != nil {
+				return err
+			}
+			action = syncEntities.ActionCreate
+		}
+
+		if err := changelog.Contact(tx, contactID, action); err != nil {
+			return err
+		}
+
+		if err := changelog.AddressesDeleted(tx, contactID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("contact_id = ?", contactID).Delete(&addressEntities.Address{}).Error; err != nil {
+			return err
+		}
+
+		for _, address := range snapshot.Addresses {
+			if err := tx.Create(&addressEntities.Address{
+				ID:         address.ID,
+				Street:     address.Street,
+				City:       address.City,
+				Province:   address.Province,
+				Country:    address.Country,
+				PostalCode: address.PostalCode,
+				ContactID:  contactID,
+				CreatedBy:  username,
+				UpdatedBy:  username,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := changelog.Address(tx, contactID, address.ID, syncEntities.ActionCreate); err != nil {
+				return err
+			}
+		}
+
+		if err := repository.members.Refresh(ctx, tx, contactID); err != nil {
+			return err
+		}
+
+		return recorder.Record(tx, username, contactID, entities.ActionRevert)
+	})
+	if database.IsUniqueViolation(err, "idx_contacts_username_email_key") {
+		return contactDomain.ErrDuplicateEmail
+	}
+	return err
+}
+
+// restore recreates a deleted contact. Contacts that still exist but are not
+// writable by the user are reported as such instead.
+func (repository *contactVersionRepositoryImpl) restore(ctx context.Context, tx *gorm.DB, username string, target entities.ContactVersion, companyID *int) error {
+	var count int64
+	if err := tx.Model(&contactEntities.Contact{}).Where("id = ?", target.ContactID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return contactRepository.AccessError(ctx, tx, username, target.ContactID)
+	}
+
+	if !workspace.FromContext(ctx).CanWrite() {
+		return contactDomain.ErrContactReadOnly
+	}
+
+	// Only the owner sees the history of a deleted contact, see visible.
+	snapshot := target.Snapshot
+	return tx.Create(&contactEntities.Contact{
+		ID:             target.ContactID,
+		FirstName:      snapshot.FirstName,
+		LastName:       snapshot.LastName,
+		Email:          snapshot.Email,
+		Phone:          snapshot.Phone,
+		PhoneE164:      snapshot.PhoneE164,
+		CompanyID:      companyID,
+		JobTitle:       snapshot.JobTitle,
+		Department:     snapshot.Department,
+		Username:       target.Username,
+		OrganizationID: target.OrganizationID,
+		CreatedBy:      username,
+		UpdatedBy:      username,
+	}).Error
+}
+
+// visible selects the versions of contacts the user can read, and of
+// deleted contacts that were owned in the current workspace.
+func (repository *contactVersionRepositoryImpl) visible(ctx context.Context, username string) *gorm.DB {
+	owned, ownedArgs := workspace.Owned(ctx, "contact_versions", username)
+	readable, readableArgs := contactRepository.ReadableBy(ctx, username)
+
+	return repository.DB.WithContext(ctx).Model(&entities.ContactVersion{}).Where(
+		"(("+owned+") OR contact_versions.contact_id IN (?))",
+		append(ownedArgs, repository.DB.Table("contacts").Select("contacts.id").Where(readable, readableArgs...))...,
+	)
+}
+
+// existingCompany drops the link to a company deleted since the version was
+// recorded.
+func existingCompany(tx *gorm.DB, companyID *int) (*int, error) {
+	if companyID == nil {
+		return nil, nil
+	}
+
+	var count int64
+	if err := tx.Table("companies").Where("id = ?", *companyID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return companyID, nil
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/history/models"
+)
+
+type ContactVersionUsecase interface {
+	FindAll(ctx context.Context, username string, contactID int, page int, size int) ([]models.VersionResponse, contactModels.Paging, error)
+	Revert(ctx context.Context, username string, contactID int, version int) (contactModels.ContactResponse, error)
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	"golang-contact-management-restful-api/modules/history/domain"
+	"golang-contact-management-restful-api/modules/history/models"
+	"golang-contact-management-restful-api/modules/history/repository"
+	"math"
+)
+
+type contactVersionUsecaseImpl struct {
+	contactVersionRepository repository.ContactVersionRepository
+	contactUsecase           contactUsecase.ContactUsecase
+}
+
+func NewContactVersionUsecase(contactVersionRepository repository.ContactVersionRepository, contactUsecase contactUsecase.ContactUsecase) ContactVersionUsecase {
+	return &contactVersionUsecaseImpl{
+		contactVersionRepository: contactVersionRepository,
+		contactUsecase:           contactUsecase,
+	}
+}
+
+func (usecase *contactVersionUsecaseImpl) FindAll(ctx context.Context, username string, contactID int, page int, size int) ([]models.VersionResponse, contactModels.Paging, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	} else if size > 100 {
+		size = 100
+	}
+
+	versions, total, err := usecase.contactVersionRepository.FindAll(ctx, username, contactID, page, size)
+	if err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	responses := make([]models.VersionResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = models.VersionResponse{
+			Version:   version.Version,
+			Action:    version.Action,
+			ChangedBy: version.ChangedBy,
+			CreatedAt: version.CreatedAt,
+			Changes:   version.Changes,
+		}
+	}
+
+	totalPage := int(math.Ceil(float64(total) / float64(size)))
+	if totalPage == 0 {
+		totalPage = 1
+	}
+
+	return responses, contactModels.Paging{
+		Page:      page,
+		TotalPage: totalPage,
+		TotalItem: total,
+	}, nil
+}
+
+// Revert restores the version and returns the contact as it is now, with its
+// addresses.
+func (usecase *contactVersionUsecaseImpl) Revert(ctx context.Context, username string, contactID int, version int) (contactModels.ContactResponse, error) {
+	if version < 1 {
+		return contactModels.ContactResponse{}, domain.ErrInvalidVersion
+	}
+
+	if err := usecase.contactVersionRepository.Revert(ctx, username, contactID, version); err != nil {
+		return contactModels.ContactResponse{}, err
+	}
+
+	return usecase.contactUsecase.FindByID(ctx, username, contactID, contactModels.ContactReadOptions{
+		Include: []string{contactModels.ContactIncludeAddresses},
+	})
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "errors"
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrSlugTaken            = errors.New("an organization with this slug already exists")
+	ErrInvalidSlug          = errors.New("slug may only contain lowercase letters, digits and single hyphens")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrMemberExists         = errors.New("user is already a member of this organization")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrForbidden            = errors.New("your role in this organization does not allow this")
+	ErrLastOwner            = errors.New("an organization needs at least one owner")
+)
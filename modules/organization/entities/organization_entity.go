@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+type Organization struct {
+	ID        int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"column:name;size:100;not null"`
+	Slug      string    `json:"slug" gorm:"column:slug;size:50;not null;uniqueIndex"`
+	CreatedBy string    `json:"created_by" gorm:"column:created_by;size:255;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	Role      string    `json:"-" gorm:"column:role;->;-:migration"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+type OrganizationMember struct {
+	OrganizationID int       `json:"organization_id" gorm:"column:organization_id;primaryKey"`
+	Username       string    `json:"username" gorm:"column:username;primaryKey;size:255"`
+	Role           string    `json:"role" gorm:"column:role;size:10;not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
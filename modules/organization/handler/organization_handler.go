@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type OrganizationHandler interface {
+	Create(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	FindByID(ctx *fiber.Ctx) error
+	UpdateByID(ctx *fiber.Ctx) error
+	DeleteByID(ctx *fiber.Ctx) error
+	FindMembers(ctx *fiber.Ctx) error
+	AddMember(ctx *fiber.Ctx) error
+	UpdateMember(ctx *fiber.Ctx) error
+	RemoveMember(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/modules/organization/domain"
+	"golang-contact-management-restful-api/modules/organization/models"
+	"golang-contact-management-restful-api/modules/organization/usecase"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type organizationHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.OrganizationUsecase
+	validate *validator.Validate
+}
+
+func NewOrganizationHttpHandler(app *fiber.App, usecase usecase.OrganizationUsecase) OrganizationHandler {
+	return &organizationHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *organizationHandlerHttp) Create(ctx *fiber.Ctx) error {
+	var request models.OrganizationCreateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Create(ctx.Context(), username, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.OrganizationResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) FindAll(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.FindAll(ctx.Context(), username)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[[]models.OrganizationResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) FindByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.FindByID(ctx.Context(), username, id)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.OrganizationResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) UpdateByID(ctx *fiber.Ctx) error {
+	var request models.OrganizationUpdateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.Update(ctx.Context(), username, id, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.OrganizationResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	if err := handler.usecase.DeleteByID(ctx.Context(), username, id); err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
+		Data: "OK",
+	})
+}
+
+func (handler *organizationHandlerHttp) FindMembers(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.FindMembers(ctx.Context(), username, id)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[[]models.MemberResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) AddMember(ctx *fiber.Ctx) error {
+	var request models.MemberCreateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.AddMember(ctx.Context(), username, id, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.MemberResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) UpdateMember(ctx *fiber.Ctx) error {
+	var request models.MemberUpdateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.UpdateMember(ctx.Context(), username, id, ctx.Params("username"), request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.MemberResponse]{
+		Data: response,
+	})
+}
+
+func (handler *organizationHandlerHttp) RemoveMember(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("orgId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	if err := handler.usecase.RemoveMember(ctx.Context(), username, id, ctx.Params("username")); err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
+		Data: "OK",
+	})
+}
+
+func (handler *organizationHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOrganizationNotFound), errors.Is(err, domain.ErrMemberNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrForbidden):
+		return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrSlugTaken), errors.Is(err, domain.ErrMemberExists), errors.Is(err, domain.ErrLastOwner):
+		return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
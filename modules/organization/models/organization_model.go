@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type OrganizationCreateRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	Slug string `json:"slug" validate:"required,min=3,max=50"`
+}
+
+type OrganizationUpdateRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+type OrganizationResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemberCreateRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=255"`
+	Role     string `json:"role" validate:"required,oneof=owner admin member viewer"`
+}
+
+type MemberUpdateRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member viewer"`
+}
+
+type MemberResponse struct {
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
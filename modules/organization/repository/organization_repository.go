@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/organization/entities"
+)
+
+type OrganizationRepository interface {
+	Save(ctx context.Context, organization entities.Organization) (entities.Organization, error)
+	UpdateByID(ctx context.Context, id int, organization entities.Organization) error
+	DeleteByID(ctx context.Context, id int) error
+	FindByID(ctx context.Context, username string, id int) (entities.Organization, error)
+	FindAll(ctx context.Context, username string) ([]entities.Organization, error)
+	ExistsBySlug(ctx context.Context, slug string) (bool, error)
+	FindMembership(ctx context.Context, username string, selector string) (entities.OrganizationMember, error)
+	FindMembers(ctx context.Context, organizationID int) ([]entities.OrganizationMember, error)
+	FindMember(ctx context.Context, organizationID int, username string) (entities.OrganizationMember, error)
+	SaveMember(ctx context.Context, member entities.OrganizationMember) (entities.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, organizationID int, username string, role string) error
+	DeleteMember(ctx context.Context, organizationID int, username string) error
+	CountOwners(ctx context.Context, organizationID int) (int, error)
+}
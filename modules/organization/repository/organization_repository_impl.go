@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/organization/domain"
+	"golang-contact-management-restful-api/modules/organization/entities"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+type organizationRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepositoryImpl{DB: db}
+}
+
+// Save creates the organization and makes its creator the first owner.
+func (repository *organizationRepositoryImpl) Save(ctx context.Context, organization entities.Organization) (entities.Organization, error) {
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&organization).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entities.OrganizationMember{
+			OrganizationID: organization.ID,
+			Username:       organization.CreatedBy,
+			Role:           workspace.RoleOwner,
+		}).Error
+	})
+	if err != nil {
+		return entities.Organization{}, err
+	}
+
+	organization.Role = workspace.RoleOwner
+	return organization, nil
+}
+
+func (repository *organizationRepositoryImpl) UpdateByID(ctx context.Context, id int, organization entities.Organization) error {
+	result := repository.DB.WithContext(ctx).Model(&entities.Organization{}).Where("id = ?", id).
+		Updates(map[string]any{"name": organization.Name})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+func (repository *organizationRepositoryImpl) DeleteByID(ctx context.Context, id int) error {
+	result := repository.DB.WithContext(ctx).Delete(&entities.Organization{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+// FindByID returns the organization along with the user's role in it. Users
+// who are not members get ErrOrganizationNotFound.
+func (repository *organizationRepositoryImpl) FindByID(ctx context.Context, username string, id int) (entities.Organization, error) {
+	var organization entities.Organization
+	if err := repository.members(ctx, username).Where("organizations.id = ?", id).Take(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Organization{}, domain.ErrOrganizationNotFound
+		}
+		return entities.Organization{}, err
+	}
+	return organization, nil
+}
+
+func (repository *organizationRepositoryImpl) FindAll(ctx context.Context, username string) ([]entities.Organization, error) {
+	var organizations []entities.Organization
+	if err := repository.members(ctx, username).Order("organizations.name ASC").Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (repository *organizationRepositoryImpl) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	if err := repository.DB.WithContext(ctx).Model(&entities.Organization{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindMembership resolves a workspace selector, either an organization id
+// or slug, to the user's membership in it.
+func (repository *organizationRepositoryImpl) FindMembership(ctx context.Context, username string, selector string) (entities.OrganizationMember, error) {
+	db := repository.DB.WithContext(ctx).Model(&entities.OrganizationMember{}).
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id").
+		Where("organization_members.username = ?", username)
+
+	if id, err := strconv.Atoi(selector); err == nil {
+		db = db.Where("organizations.id = ?", id)
+	} else {
+		db = db.Where("organizations.slug = ?", selector)
+	}
+
+	var member entities.OrganizationMember
+	if err := db.Take(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.OrganizationMember{}, domain.ErrOrganizationNotFound
+		}
+		return entities.OrganizationMember{}, err
+	}
+	return member, nil
+}
+
+func (repository *organizationRepositoryImpl) FindMembers(ctx context.Context, organizationID int) ([]entities.OrganizationMember, error) {
+	var members []entities.OrganizationMember
+	if err := repository.DB.WithContext(ctx).Where("organization_id = ?", organizationID).
+		Order("username ASC").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (repository *organizationRepositoryImpl) FindMember(ctx context.Context, organizationID int, username string) (entities.OrganizationMember, error) {
+	var member entities.OrganizationMember
+	if err := repository.DB.WithContext(ctx).Take(&member, "organization_id = ? AND username = ?", organizationID, username).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.OrganizationMember{}, domain.ErrMemberNotFound
+		}
+		return entities.OrganizationMember{}, err
+	}
+	return member, nil
+}
+
+func (repository *organizationRepositoryImpl) SaveMember(ctx context.Context, member entities.OrganizationMember) (entities.OrganizationMember, error) {
+	if err := repository.DB.WithContext(ctx).Create(&member).Error; err != nil {
+		return entities.OrganizationMember{}, err
+	}
+	return member, nil
+}
+
+func (repository *organizationRepositoryImpl) UpdateMemberRole(ctx context.Context, organizationID int, username string, role string) error {
+	result := repository.DB.WithContext(ctx).Model(&entities.OrganizationMember{}).
+		Where("organization_id = ? AND username = ?", organizationID, username).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (repository *organizationRepositoryImpl) DeleteMember(ctx context.Context, organizationID int, username string) error {
+	result := repository.DB.WithContext(ctx).
+		Where("organization_id = ? AND username = ?", organizationID, username).
+		Delete(&entities.OrganizationMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (repository *organizationRepositoryImpl) CountOwners(ctx context.Context, organizationID int) (int, error) {
+	var count int64
+	if err := repository.DB.WithContext(ctx).Model(&entities.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationID, workspace.RoleOwner).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (repository *organizationRepositoryImpl) members(ctx context.Context, username string) *gorm.DB {
+	return repository.DB.WithContext(ctx).Model(&entities.Organization{}).
+		Select("organizations.*, organization_members.role AS role").
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.username = ?", username)
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/organization/models"
+)
+
+type OrganizationUsecase interface {
+	Create(ctx context.Context, username string, request models.OrganizationCreateRequest) (models.OrganizationResponse, error)
+	Update(ctx context.Context, username string, id int, request models.OrganizationUpdateRequest) (models.OrganizationResponse, error)
+	FindByID(ctx context.Context, username string, id int) (models.OrganizationResponse, error)
+	FindAll(ctx context.Context, username string) ([]models.OrganizationResponse, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+	FindMembers(ctx context.Context, username string, id int) ([]models.MemberResponse, error)
+	AddMember(ctx context.Context, username string, id int, request models.MemberCreateRequest) (models.MemberResponse, error)
+	UpdateMember(ctx context.Context, username string, id int, member string, request models.MemberUpdateRequest) (models.MemberResponse, error)
+	RemoveMember(ctx context.Context, username string, id int, member string) error
+}
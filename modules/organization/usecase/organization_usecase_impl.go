@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/organization/domain"
+	"golang-contact-management-restful-api/modules/organization/entities"
+	"golang-contact-management-restful-api/modules/organization/models"
+	"golang-contact-management-restful-api/modules/organization/repository"
+	userRepository "golang-contact-management-restful-api/modules/user/repository"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type organizationUsecaseImpl struct {
+	organizationRepository repository.OrganizationRepository
+	userRepository         userRepository.UserRepository
+	validator              *validator.Validate
+}
+
+func NewOrganizationUsecase(organizationRepository repository.OrganizationRepository, userRepository userRepository.UserRepository, validator *validator.Validate) OrganizationUsecase {
+	return &organizationUsecaseImpl{
+		organizationRepository: organizationRepository,
+		userRepository:         userRepository,
+		validator:              validator,
+	}
+}
+
+func (usecase *organizationUsecaseImpl) Create(ctx context.Context, username string, request models.OrganizationCreateRequest) (models.OrganizationResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.OrganizationResponse{}, err
+	}
+
+	if !slugPattern.MatchString(request.Slug) {
+		return models.OrganizationResponse{}, domain.ErrInvalidSlug
+	}
+
+	exists, err := usecase.organizationRepository.ExistsBySlug(ctx, request.Slug)
+	if err != nil {
+		return models.OrganizationResponse{}, err
+	}
+	if exists {
+		return models.OrganizationResponse{}, domain.ErrSlugTaken
+	}
+
+	saved, err := usecase.organizationRepository.Save(ctx, entities.Organization{
+		Name:      request.Name,
+		Slug:      request.Slug,
+		CreatedBy: username,
+	})
+	if err != nil {
+		return models.OrganizationResponse{}, err
+	}
+
+	return toOrganizationResponse(saved), nil
+}
+
+func (usecase *organizationUsecaseImpl) Update(ctx context.Context, username string, id int, request models.OrganizationUpdateRequest) (models.OrganizationResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.OrganizationResponse{}, err
+	}
+
+	organization, err := usecase.organizationRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.OrganizationResponse{}, err
+	}
+	if !canManage(organization.Role) {
+		return models.OrganizationResponse{}, domain.ErrForbidden
+	}
+
+	if err := usecase.organizationRepository.UpdateByID(ctx, id, entities.Organization{Name: request.Name}); err != nil {
+		return models.OrganizationResponse{}, err
+	}
+
+	organization.Name = request.Name
+	return toOrganizationResponse(organization), nil
+}
+
+func (usecase *organizationUsecaseImpl) FindByID(ctx context.Context, username string, id int) (models.OrganizationResponse, error) {
+	organization, err := usecase.organizationRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.OrganizationResponse{}, err
+	}
+	return toOrganizationResponse(organization), nil
+}
+
+func (usecase *organizationUsecaseImpl) FindAll(ctx context.Context, username string) ([]models.OrganizationResponse, error) {
+	organizations, err := usecase.organizationRepository.FindAll(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OrganizationResponse, len(organizations))
+	for i, organization := range organizations {
+		responses[i] = toOrganizationResponse(organization)
+	}
+	return responses, nil
+}
+
+// DeleteByID deletes the organization together with the contacts it owns,
+// so only owners may do it.
+func (usecase *organizationUsecaseImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	organization, err := usecase.organizationRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return err
+	}
+	if organization.Role != workspace.RoleOwner {
+		return domain.ErrForbidden
+	}
+
+	return usecase.organizationRepository.DeleteByID(ctx, id)
+}
+
+func (usecase *organizationUsecaseImpl) FindMembers(ctx context.Context, username string, id int) ([]models.MemberResponse, error) {
+	if _, err := usecase.organizationRepository.FindByID(ctx, username, id); err != nil {
+		return nil, err
+	}
+
+	members, err := usecase.organizationRepository.FindMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.MemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = toMemberResponse(member)
+	}
+	return responses, nil
+}
+
+func (usecase *organizationUsecaseImpl) AddMember(ctx context.Context, username string, id int, request models.MemberCreateRequest) (models.MemberResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.MemberResponse{}, err
+	}
+
+	organization, err := usecase.organizationRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.MemberResponse{}, err
+	}
+	if !canAssign(organization.Role, request.Role) {
+		return models.MemberResponse{}, domain.ErrForbidden
+	}
+
+	exists, err := usecase.userRepository.ExistsByUsername(ctx, request.Username)
+	if err != nil {
+		return models.MemberResponse{}, err
+	}
+	if !exists {
+		return models.MemberResponse{}, domain.ErrUserNotFound
+	}
+
+	if _, err := usecase.organizationRepository.FindMember(ctx, id, request.Username); err == nil {
+		return models.MemberResponse{}, domain.ErrMemberExists
+	}
+
+	saved, err := usecase.organizationRepository.SaveMember(ctx, entities.OrganizationMember{
+		OrganizationID: id,
+		Username:       request.Username,
+		Role:           request.Role,
+	})
+	if err != nil {
+		return models.MemberResponse{}, err
+	}
+
+	return toMemberResponse(saved), nil
+}
+
+func (usecase *organizationUsecaseImpl) UpdateMember(ctx context.Context, username string, id int, memberUsername string, request models.MemberUpdateRequest) (models.MemberResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.MemberResponse{}, err
+	}
+
+	organization, err := usecase.organizationRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.MemberResponse{}, err
+	}
+
+	member, err := usecase.organizationRepository.FindMember(ctx, id, memberUsername)
+	if err != nil {
+		return models.MemberResponse{}, err
+	}
+
+	if !canAssign(organization.Role, member.Role) || !canAssign(organization.Role, request.Role) {
+		return models.MemberResponse{}, domain.ErrForbidden
+	}
+
+	if member.Role == workspace.RoleOwner && request.Role != workspace.RoleOwner {
+		if err := usecase.ensureAnotherOwner(ctx, id); err != nil {
+			return models.MemberResponse{}, err
+		}
+	}
+
+	if err := usecase.organizationRepository.UpdateMemberRole(ctx, id, memberUsername, request.Role); err != nil {
+		return models.MemberResponse{}, err
+	}
+
+	member.Role = request.Role
+	return toMemberResponse(member), nil
+}
+
+// RemoveMember removes a member. Admins may remove others, and every member
+// may remove themselves to leave the organization.
+func (usecase *organizationUsecaseImpl) RemoveMember(ctx context.Context, username string, id int, memberUsername string) error {
+	organization, err := usecase.organizationRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return err
+	}
+
+	member, err := usecase.organizationRepository.FindMember(ctx, id, memberUsername)
+	if err != nil {
+		return err
+	}
+
+	if memberUsername != username && !canAssign(organization.Role, member.Role) {
+		return domain.ErrForbidden
+	}
+
+	if member.Role == workspace.RoleOwner {
+		if err := usecase.ensureAnotherOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return usecase.organizationRepository.DeleteMember(ctx, id, memberUsername)
+}
+
+func (usecase *organizationUsecaseImpl) ensureAnotherOwner(ctx context.Context, id int) error {
+	owners, err := usecase.organizationRepository.CountOwners(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
+
+func canManage(role string) bool {
+	return role == workspace.RoleOwner || role == workspace.RoleAdmin
+}
+
+// canAssign reports whether a member with the given role may grant or take
+// away target. Only owners manage other owners.
+func canAssign(role string, target string) bool {
+	if target == workspace.RoleOwner {
+		return role == workspace.RoleOwner
+	}
+	return canManage(role)
+}
+
+func toOrganizationResponse(organization entities.Organization) models.OrganizationResponse {
+	return models.OrganizationResponse{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		Role:      organization.Role,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+func toMemberResponse(member entities.OrganizationMember) models.MemberResponse {
+	return models.MemberResponse{
+		Username:  member.Username,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrRelationshipNotFound = errors.New("relationship not found")
+	ErrRelationshipExists   = errors.New("relationship already exists")
+	ErrInvalidType          = errors.New("invalid relationship type, expected lowercase letters and underscores")
+	ErrSelfRelationship     = errors.New("a contact cannot be related to itself")
+	ErrDifferentOwners      = errors.New("related contacts must have the same owner")
+	ErrInvalidDepth         = errors.New("invalid depth")
+)
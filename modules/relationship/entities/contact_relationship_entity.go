@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ContactRelationship is a typed, directed edge between two contacts of the
+// same owner: ContactID is Type of RelatedContactID. When the type has a
+// reciprocal, the reverse edge is stored as well and both carry
+// ReciprocalType so they can be removed together.
+type ContactRelationship struct {
+	ID               int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ContactID        int       `json:"contact_id" gorm:"column:contact_id;not null;uniqueIndex:idx_contact_relationships_edge"`
+	RelatedContactID int       `json:"related_contact_id" gorm:"column:related_contact_id;not null;index;uniqueIndex:idx_contact_relationships_edge"`
+	Type             string    `json:"type" gorm:"column:type;size:50;not null;uniqueIndex:idx_contact_relationships_edge"`
+	ReciprocalType   *string   `json:"reciprocal_type,omitempty" gorm:"column:reciprocal_type;size:50"`
+	CreatedAt        time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (ContactRelationship) TableName() string {
+	return "contact_relationships"
+}
@@ -0,0 +1,10 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type ContactRelationshipHandler interface {
+	Create(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	DeleteByID(ctx *fiber.Ctx) error
+	Graph(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/relationship/domain"
+	"golang-contact-management-restful-api/modules/relationship/models"
+	"golang-contact-management-restful-api/modules/relationship/usecase"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type contactRelationshipHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.ContactRelationshipUsecase
+	validate *validator.Validate
+}
+
+func NewContactRelationshipHttpHandler(app *fiber.App, usecase usecase.ContactRelationshipUsecase) ContactRelationshipHandler {
+	return &contactRelationshipHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *contactRelationshipHandlerHttp) Create(ctx *fiber.Ctx) error {
+	var request models.RelationshipCreateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	response, err := handler.usecase.Create(ctx.Context(), username, contactID, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.RelationshipResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactRelationshipHandlerHttp) FindAll(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	response, err := handler.usecase.FindAll(ctx.Context(), username, contactID)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[[]models.RelationshipResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactRelationshipHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("relationshipId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Relationship ID"})
+	}
+
+	if err := handler.usecase.DeleteByID(ctx.Context(), username, contactID, id); err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
+		Data: "OK",
+	})
+}
+
+func (handler *contactRelationshipHandlerHttp) Graph(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	contactID, err := strconv.Atoi(ctx.Params("contactId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid Contact ID"})
+	}
+
+	depth, err := strconv.Atoi(ctx.Query("depth", strconv.Itoa(models.DefaultGraphDepth)))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: domain.ErrInvalidDepth.Error()})
+	}
+
+	response, err := handler.usecase.Graph(ctx.Context(), username, contactID, depth)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.GraphResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactRelationshipHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrRelationshipNotFound), errors.Is(err, contactDomain.ErrContactNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, contactDomain.ErrContactReadOnly):
+		return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrRelationshipExists):
+		return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
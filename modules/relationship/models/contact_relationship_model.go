@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+const (
+	DefaultGraphDepth = 1
+	MaxGraphDepth     = 5
+)
+
+// Reciprocals are applied when a relationship is created without an explicit
+// reciprocal type.
+var Reciprocals = map[string]string{
+	"spouse_of":     "spouse_of",
+	"partner_of":    "partner_of",
+	"sibling_of":    "sibling_of",
+	"friend_of":     "friend_of",
+	"colleague_of":  "colleague_of",
+	"parent_of":     "child_of",
+	"child_of":      "parent_of",
+	"reports_to":    "manages",
+	"manages":       "reports_to",
+	"assistant_of":  "has_assistant",
+	"has_assistant": "assistant_of",
+}
+
+type RelationshipCreateRequest struct {
+	RelatedContactID int    `json:"related_contact_id" validate:"required,min=1"`
+	Type             string `json:"type" validate:"required,max=50"`
+	ReciprocalType   string `json:"reciprocal_type" validate:"omitempty,max=50"`
+}
+
+type RelationshipResponse struct {
+	ID               int       `json:"id"`
+	ContactID        int       `json:"contact_id"`
+	RelatedContactID int       `json:"related_contact_id"`
+	Type             string    `json:"type"`
+	ReciprocalType   string    `json:"reciprocal_type,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type GraphNode struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Depth     int    `json:"depth"`
+}
+
+type GraphEdge struct {
+	ID   int    `json:"id"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Type string `json:"type"`
+}
+
+type GraphResponse struct {
+	Root  int         `json:"root"`
+	Depth int         `json:"depth"`
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	"golang-contact-management-restful-api/modules/relationship/entities"
+)
+
+type ContactRelationshipRepository interface {
+	Save(ctx context.Context, username string, relationship entities.ContactRelationship) (entities.ContactRelationship, error)
+	FindAll(ctx context.Context, username string, contactID int) ([]entities.ContactRelationship, error)
+	DeleteByID(ctx context.Context, username string, contactID int, id int) error
+	FindByContactIDs(ctx context.Context, username string, contactIDs []int) ([]entities.ContactRelationship, error)
+	FindContacts(ctx context.Context, username string, contactIDs []int) ([]contactEntities.Contact, error)
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/internal/database"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	"golang-contact-management-restful-api/modules/relationship/domain"
+	"golang-contact-management-restful-api/modules/relationship/entities"
+
+	"gorm.io/gorm"
+)
+
+type contactRelationshipRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewContactRelationshipRepository(db *gorm.DB) ContactRelationshipRepository {
+	return &contactRelationshipRepositoryImpl{DB: db}
+}
+
+// Save stores the relationship and, when it has a reciprocal type, the
+// reverse edge. Both contacts must be writable by the user and belong to the
+// same owner.
+func (repository *contactRelationshipRepositoryImpl) Save(ctx context.Context, username string, relationship entities.ContactRelationship) (entities.ContactRelationship, error) {
+	contact, err := repository.takeWritableContact(ctx, username, relationship.ContactID)
+	if err != nil {
+		return entities.ContactRelationship{}, err
+	}
+
+	related, err := repository.takeWritableContact(ctx, username, relationship.RelatedContactID)
+	if err != nil {
+		return entities.ContactRelationship{}, err
+	}
+
+	if !sameOwner(contact, related) {
+		return entities.ContactRelationship{}, domain.ErrDifferentOwners
+	}
+
+	err = repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&relationship).Error; err != nil {
+			return err
+		}
+
+		if relationship.ReciprocalType == nil {
+			return nil
+		}
+
+		return tx.Create(&entities.ContactRelationship{
+			ContactID:        relationship.RelatedContactID,
+			RelatedContactID: relationship.ContactID,
+			Type:             *relationship.ReciprocalType,
+			ReciprocalType:   &relationship.Type,
+		}).Error
+	})
+	if database.IsUniqueViolation(err, "idx_contact_relationships_edge") {
+		return entities.ContactRelationship{}, domain.ErrRelationshipExists
+	}
+	if err != nil {
+		return entities.ContactRelationship{}, err
+	}
+
+	return relationship, nil
+}
+
+// FindAll lists the relationships of the contact: its outgoing edges and
+// the incoming ones that have no reverse edge.
+func (repository *contactRelationshipRepositoryImpl) FindAll(ctx context.Context, username string, contactID int) ([]entities.ContactRelationship, error) {
+	if _, err := repository.takeReadableContact(ctx, username, contactID); err != nil {
+		return nil, err
+	}
+
+	var relationships []entities.ContactRelationship
+	if err := repository.DB.WithContext(ctx).
+		Where("contact_id = ? OR (related_contact_id = ? AND reciprocal_type IS NULL)", contactID, contactID).
+		Order("id ASC").Find(&relationships).Error; err != nil {
+		return nil, err
+	}
+	return relationships, nil
+}
+
+// DeleteByID removes the relationship together with its reverse edge.
+func (repository *contactRelationshipRepositoryImpl) DeleteByID(ctx context.Context, username string, contactID int, id int) error {
+	if _, err := repository.takeWritableContact(ctx, username, contactID); err != nil {
+		return err
+	}
+
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var relationship entities.ContactRelationship
+		if err := tx.Where("id = ? AND (contact_id = ? OR related_contact_id = ?)", id, contactID, contactID).
+			Take(&relationship).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrRelationshipNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&relationship).Error; err != nil {
+			return err
+		}
+
+		if relationship.ReciprocalType == nil {
+			return nil
+		}
+
+		return tx.Where("contact_id = ? AND related_contact_id = ? AND type = ?",
+			relationship.RelatedContactID, relationship.ContactID, *relationship.ReciprocalType).
+			Delete(&entities.ContactRelationship{}).Error
+	})
+}
+
+// FindByContactIDs returns every edge touching the contacts whose both ends
+// are readable by the user.
+func (repository *contactRelationshipRepositoryImpl) FindByContactIDs(ctx context.Context, username string, contactIDs []int) ([]entities.ContactRelationship, error) {
+	readable, args := contactRepository.ReadableBy(ctx, username)
+	visible := repository.DB.Table("contacts").Select("contacts.id").Where(readable, args...)
+
+	var relationships []entities.ContactRelationship
+	if err := repository.DB.WithContext(ctx).
+		Where("contact_id IN ? OR related_contact_id IN ?", contactIDs, contactIDs).
+		Where("contact_id IN (?) AND related_contact_id IN (?)", visible, visible).
+		Order("id ASC").Find(&relationships).Error; err != nil {
+		return nil, err
+	}
+	return relationships, nil
+}
+
+func (repository *contactRelationshipRepositoryImpl) FindContacts(ctx context.Context, username string, contactIDs []int) ([]contactEntities.Contact, error) {
+	readable, args := contactRepository.ReadableBy(ctx, username)
+
+	var contacts []contactEntities.Contact
+	if err := repository.DB.WithContext(ctx).Where("contacts.id IN ?", contactIDs).
+		Where(readable, args...).Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+func (repository *contactRelationshipRepositoryImpl) takeReadableContact(ctx context.Context, username string, contactID int) (contactEntities.Contact, error) {
+	contacts, err := repository.FindContacts(ctx, username, []int{contactID})
+	if err != nil {
+		return contactEntities.Contact{}, err
+	}
+	if len(contacts) == 0 {
+		return contactEntities.Contact{}, contactDomain.ErrContactNotFound
+	}
+	return contacts[0], nil
+}
+
+func (repository *contactRelationshipRepositoryImpl) takeWritableContact(ctx context.Context, username string, contactID int) (contactEntities.Contact, error) {
+	writable, args := contactRepository.WritableBy(ctx, username)
+
+	var contact contactEntities.Contact
+	err := repository.DB.WithContext(ctx).Where("id = ?", contactID).Where(writable, args...).Take(&contact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return contactEntities.Contact{}, contactRepository.AccessError(ctx, repository.DB, username, contactID)
+	}
+	return contact, err
+}
+
+// sameOwner compares the organizations owning the contacts, or their users
+// for personal contacts.
+func sameOwner(a contactEntities.Contact, b contactEntities.Contact) bool {
+	if a.OrganizationID != nil || b.OrganizationID != nil {
+		return a.OrganizationID != nil && b.OrganizationID != nil && *a.OrganizationID == *b.OrganizationID
+	}
+	return a.Username == b.Username
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/relationship/models"
+)
+
+type ContactRelationshipUsecase interface {
+	Create(ctx context.Context, username string, contactID int, request models.RelationshipCreateRequest) (models.RelationshipResponse, error)
+	FindAll(ctx context.Context, username string, contactID int) ([]models.RelationshipResponse, error)
+	DeleteByID(ctx context.Context, username string, contactID int, id int) error
+	Graph(ctx context.Context, username string, contactID int, depth int) (models.GraphResponse, error)
+}
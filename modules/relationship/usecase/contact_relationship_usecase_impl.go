@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/transport/http"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/relationship/domain"
+	"golang-contact-management-restful-api/modules/relationship/entities"
+	"golang-contact-management-restful-api/modules/relationship/models"
+	"golang-contact-management-restful-api/modules/relationship/repository"
+	"regexp"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var typePattern = regexp.MustCompile(`^[a-z]+(_[a-z]+)*$`)
+
+type contactRelationshipUsecaseImpl struct {
+	contactRelationshipRepository repository.ContactRelationshipRepository
+	validator                     *validator.Validate
+}
+
+func NewContactRelationshipUsecase(contactRelationshipRepository repository.ContactRelationshipRepository, validator *validator.Validate) ContactRelationshipUsecase {
+	return &contactRelationshipUsecaseImpl{
+		contactRelationshipRepository: contactRelationshipRepository,
+		validator:                     validator,
+	}
+}
+
+func (usecase *contactRelationshipUsecaseImpl) Create(ctx context.Context, username string, contactID int, request models.RelationshipCreateRequest) (models.RelationshipResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.RelationshipResponse{}, err
+	}
+
+	if !typePattern.MatchString(request.Type) {
+		return models.RelationshipResponse{}, domain.ErrInvalidType
+	}
+	if request.ReciprocalType != "" && !typePattern.MatchString(request.ReciprocalType) {
+		return models.RelationshipResponse{}, domain.ErrInvalidType
+	}
+	if request.RelatedContactID == contactID {
+		return models.RelationshipResponse{}, domain.ErrSelfRelationship
+	}
+
+	reciprocal := request.ReciprocalType
+	if reciprocal == "" {
+		reciprocal = models.Reciprocals[request.Type]
+	}
+
+	saved, err := usecase.contactRelationshipRepository.Save(ctx, username, entities.ContactRelationship{
+		ContactID:        contactID,
+		RelatedContactID: request.RelatedContactID,
+		Type:             request.Type,
+		ReciprocalType:   http.StringToPointerIfNotEmpty(reciprocal),
+	})
+	if err != nil {
+		return models.RelationshipResponse{}, err
+	}
+
+	return toRelationshipResponse(saved), nil
+}
+
+func (usecase *contactRelationshipUsecaseImpl) FindAll(ctx context.Context, username string, contactID int) ([]models.RelationshipResponse, error) {
+	relationships, err := usecase.contactRelationshipRepository.FindAll(ctx, username, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.RelationshipResponse, len(relationships))
+	for i, relationship := range relationships {
+		responses[i] = toRelationshipResponse(relationship)
+	}
+	return responses, nil
+}
+
+func (usecase *contactRelationshipUsecaseImpl) DeleteByID(ctx context.Context, username string, contactID int, id int) error {
+	return usecase.contactRelationshipRepository.DeleteByID(ctx, username, contactID, id)
+}
+
+// Graph walks the relationships breadth first from the contact up to depth
+// hops. Every contact is visited once, so cycles such as mutual reports or
+// reciprocal edges do not loop, and each level costs a single query.
+func (usecase *contactRelationshipUsecaseImpl) Graph(ctx context.Context, username string, contactID int, depth int) (models.GraphResponse, error) {
+	if depth < 1 || depth > models.MaxGraphDepth {
+		return models.GraphResponse{}, domain.ErrInvalidDepth
+	}
+
+	depths := map[int]int{contactID: 0}
+	seenEdges := map[int]bool{}
+	edges := []models.GraphEdge{}
+
+	frontier := []int{contactID}
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		relationships, err := usecase.contactRelationshipRepository.FindByContactIDs(ctx, username, frontier)
+		if err != nil {
+			return models.GraphResponse{}, err
+		}
+
+		var next []int
+		for _, relationship := range relationships {
+			if seenEdges[relationship.ID] {
+				continue
+			}
+			seenEdges[relationship.ID] = true
+			edges = append(edges, models.GraphEdge{
+				ID:   relationship.ID,
+				From: relationship.ContactID,
+				To:   relationship.RelatedContactID,
+				Type: relationship.Type,
+			})
+
+			for _, id := range []int{relationship.ContactID, relationship.RelatedContactID} {
+				if _, ok := depths[id]; !ok {
+					depths[id] = level
+					next = append(next, id)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	ids := make([]int, 0, len(depths))
+	for id := range depths {
+		ids = append(ids, id)
+	}
+
+	contacts, err := usecase.contactRelationshipRepository.FindContacts(ctx, username, ids)
+	if err != nil {
+		return models.GraphResponse{}, err
+	}
+
+	found := false
+	nodes := make([]models.GraphNode, 0, len(contacts))
+	for _, contact := range contacts {
+		found = found || contact.ID == contactID
+		nodes = append(nodes, models.GraphNode{
+			ID:        contact.ID,
+			FirstName: contact.FirstName,
+			LastName:  http.PointerToString(contact.LastName),
+			Depth:     depths[contact.ID],
+		})
+	}
+	if !found {
+		return models.GraphResponse{}, contactDomain.ErrContactNotFound
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	return models.GraphResponse{
+		Root:  contactID,
+		Depth: depth,
+		Nodes: nodes,
+		Edges: edges,
+	}, nil
+}
+
+func toRelationshipResponse(relationship entities.ContactRelationship) models.RelationshipResponse {
+	return models.RelationshipResponse{
+		ID:               relationship.ID,
+		ContactID:        relationship.ContactID,
+		RelatedContactID: relationship.RelatedContactID,
+		Type:             relationship.Type,
+		ReciprocalType:   http.PointerToString(relationship.ReciprocalType),
+		CreatedAt:        relationship.CreatedAt,
+	}
+}
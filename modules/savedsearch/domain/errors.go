@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrSavedSearchNotFound  = errors.New("saved search not found")
+	ErrSavedSearchNameTaken = errors.New("a saved search with this name already exists")
+)
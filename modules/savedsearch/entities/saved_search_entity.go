@@ -0,0 +1,56 @@
+package entities
+
+import (
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"time"
+)
+
+type SavedSearchQuery struct {
+	Query   string `json:"q,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Company string `json:"company,omitempty"`
+	Fuzzy   bool   `json:"fuzzy,omitempty"`
+	Filter  string `json:"filter,omitempty"`
+	Sort    string `json:"sort,omitempty"`
+}
+
+// ContactQuery returns the contact search the saved query stands for.
+func (query SavedSearchQuery) ContactQuery() contactModels.ContactSearchQuery {
+	return contactModels.ContactSearchQuery{
+		Query:   query.Query,
+		Name:    query.Name,
+		Email:   query.Email,
+		Phone:   query.Phone,
+		Company: query.Company,
+		Fuzzy:   query.Fuzzy,
+		Filter:  query.Filter,
+		Sort:    query.Sort,
+	}
+}
+
+type SavedSearch struct {
+	ID        int              `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string           `json:"name" gorm:"column:name;size:100;not null"`
+	Query     SavedSearchQuery `json:"query" gorm:"column:query;type:jsonb;serializer:json;not null"`
+	Username  string           `json:"username" gorm:"column:username;size:255;not null;index"`
+	CreatedAt time.Time        `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time        `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// SavedSearchMember is a contact currently matching a saved search. It is
+// only kept for saved searches that are shared, so that their grants can be
+// checked in SQL.
+type SavedSearchMember struct {
+	SavedSearchID int `gorm:"column:saved_search_id;primaryKey"`
+	ContactID     int `gorm:"column:contact_id;primaryKey;index"`
+}
+
+func (SavedSearchMember) TableName() string {
+	return "saved_search_members"
+}
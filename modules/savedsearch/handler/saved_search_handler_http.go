@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/savedsearch/domain"
+	"golang-contact-management-restful-api/modules/savedsearch/models"
+	"golang-contact-management-restful-api/modules/savedsearch/usecase"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type savedSearchHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.SavedSearchUsecase
+	validate *validator.Validate
+}
+
+func NewSavedSearchHttpHandler(app *fiber.App, usecase usecase.SavedSearchUsecase) SavedSearchHandler {
+	return &savedSearchHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *savedSearchHandlerHttp) Create(ctx *fiber.Ctx) error {
+	var request models.SavedSearchCreateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Create(ctx.Context(), username, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.SavedSearchResponse]{
+		Data: response,
+	})
+}
+
+func (handler *savedSearchHandlerHttp) FindAll(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.FindAll(ctx.Context(), username)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[[]models.SavedSearchResponse]{
+		Data: response,
+	})
+}
+
+func (handler *savedSearchHandlerHttp) FindByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.FindByID(ctx.Context(), username, id)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.SavedSearchResponse]{
+		Data: response,
+	})
+}
+
+func (handler *savedSearchHandlerHttp) UpdateByID(ctx *fiber.Ctx) error {
+	var request models.SavedSearchUpdateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.Update(ctx.Context(), username, id, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.SavedSearchResponse]{
+		Data: response,
+	})
+}
+
+func (handler *savedSearchHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	if err := handler.usecase.DeleteByID(ctx.Context(), username, id); err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
+		Data: "OK",
+	})
+}
+
+func (handler *savedSearchHandlerHttp) Contacts(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	size, _ := strconv.Atoi(ctx.Query("size", "10"))
+
+	if ctx.Context().QueryArgs().Has("cursor") {
+		results, paging, err := handler.usecase.SearchContactsCursor(ctx.Context(), username, id, ctx.Query("cursor"), size)
+		if err != nil {
+			return handler.errorResponse(ctx, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(struct {
+			Data   []contactModels.ContactResponse `json:"data"`
+			Paging contactModels.CursorPaging      `json:"paging"`
+		}{
+			Data:   results,
+			Paging: paging,
+		})
+	}
+
+	results, paging, err := handler.usecase.SearchContacts(ctx.Context(), username, id, page, size)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(struct {
+		Data   []contactModels.ContactResponse `json:"data"`
+		Paging contactModels.Paging            `json:"paging"`
+	}{
+		Data:   results,
+		Paging: paging,
+	})
+}
+
+func (handler *savedSearchHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSavedSearchNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrSavedSearchNameTaken):
+		return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+type SavedSearchQuery struct {
+	Query   string `json:"q" validate:"omitempty,max=255"`
+	Name    string `json:"name" validate:"omitempty,max=100"`
+	Email   string `json:"email" validate:"omitempty,max=100"`
+	Phone   string `json:"phone" validate:"omitempty,max=32"`
+	Company string `json:"company" validate:"omitempty,max=100"`
+	Fuzzy   bool   `json:"fuzzy"`
+	Filter  string `json:"filter" validate:"omitempty,max=2000"`
+	Sort    string `json:"sort" validate:"omitempty,max=255"`
+}
+
+type SavedSearchCreateRequest struct {
+	Name  string           `json:"name" validate:"required,min=1,max=100"`
+	Query SavedSearchQuery `json:"query"`
+}
+
+type SavedSearchUpdateRequest struct {
+	Name  string            `json:"name" validate:"omitempty,min=1,max=100"`
+	Query *SavedSearchQuery `json:"query"`
+}
+
+type SavedSearchResponse struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Query       SavedSearchQuery `json:"query"`
+	MemberCount int              `json:"member_count"`
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/savedsearch/entities"
+)
+
+type SavedSearchRepository interface {
+	Save(ctx context.Context, username string, savedSearch entities.SavedSearch) (entities.SavedSearch, error)
+	UpdateByID(ctx context.Context, username string, id int, savedSearch entities.SavedSearch) (entities.SavedSearch, error)
+	FindByID(ctx context.Context, username string, id int) (entities.SavedSearch, error)
+	FindAll(ctx context.Context, username string) ([]entities.SavedSearch, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+	ExistsByName(ctx context.Context, username string, name string, excludeID int) (bool, error)
+}
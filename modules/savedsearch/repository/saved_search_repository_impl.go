@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	"golang-contact-management-restful-api/modules/savedsearch/domain"
+	"golang-contact-management-restful-api/modules/savedsearch/entities"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+
+	"gorm.io/gorm"
+)
+
+type savedSearchRepositoryImpl struct {
+	DB      *gorm.DB
+	members *contactRepository.Members
+}
+
+func NewSavedSearchRepository(db *gorm.DB, members *contactRepository.Members) SavedSearchRepository {
+	return &savedSearchRepositoryImpl{DB: db, members: members}
+}
+
+func (repository *savedSearchRepositoryImpl) Save(ctx context.Context, username string, savedSearch entities.SavedSearch) (entities.SavedSearch, error) {
+	savedSearch.Username = username
+	if err := repository.DB.WithContext(ctx).Create(&savedSearch).Error; err != nil {
+		return entities.SavedSearch{}, err
+	}
+	return savedSearch, nil
+}
+
+// UpdateByID updates a saved search, recomputing its members in the same
+// transaction when it is shared.
+func (repository *savedSearchRepositoryImpl) UpdateByID(ctx context.Context, username string, id int, savedSearch entities.SavedSearch) (entities.SavedSearch, error) {
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.SavedSearch{}).
+			Where("id = ? AND username = ?", id, username).
+			Select("name", "query").
+			Updates(&savedSearch)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrSavedSearchNotFound
+		}
+
+		return repository.members.Replace(ctx, tx, id)
+	})
+	if err != nil {
+		return entities.SavedSearch{}, err
+	}
+
+	return repository.FindByID(ctx, username, id)
+}
+
+func (repository *savedSearchRepositoryImpl) FindByID(ctx context.Context, username string, id int) (entities.SavedSearch, error) {
+	var savedSearch entities.SavedSearch
+	if err := repository.DB.WithContext(ctx).Take(&savedSearch, "id = ? AND username = ?", id, username).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.SavedSearch{}, domain.ErrSavedSearchNotFound
+		}
+		return entities.SavedSearch{}, err
+	}
+	return savedSearch, nil
+}
+
+func (repository *savedSearchRepositoryImpl) FindAll(ctx context.Context, username string) ([]entities.SavedSearch, error) {
+	var savedSearches []entities.SavedSearch
+	if err := repository.DB.WithContext(ctx).Where("username = ?", username).
+		Order("name ASC").Find(&savedSearches).Error; err != nil {
+		return nil, err
+	}
+	return savedSearches, nil
+}
+
+// DeleteByID deletes a saved search and the shares of it. The contacts
+// they covered are recorded as changed so that the grantees' sync drops
+// them.
+func (repository *savedSearchRepositoryImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var shareIDs []int
+		if err := tx.Table("contact_shares").Joins(`JOIN "saved_searches" ss ON ss.id = contact_shares.saved_search_id`).
+			Where("ss.id = ? AND ss.username = ?", id, username).Pluck("contact_shares.id", &shareIDs).Error; err != nil {
+			return err
+		}
+		for _, shareID := range shareIDs {
+			if err := changelog.Share(tx, shareID); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Where("id = ? AND username = ?", id, username).Delete(&entities.SavedSearch{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrSavedSearchNotFound
+		}
+		return nil
+	})
+}
+
+func (repository *savedSearchRepositoryImpl) ExistsByName(ctx context.Context, username string, name string, excludeID int) (bool, error) {
+	var count int64
+	if err := repository.DB.WithContext(ctx).Model(&entities.SavedSearch{}).
+		Where("username = ? AND lower(name) = lower(?) AND id <> ?", username, name, excludeID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
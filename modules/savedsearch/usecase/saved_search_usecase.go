@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"context"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/savedsearch/models"
+)
+
+type SavedSearchUsecase interface {
+	Create(ctx context.Context, username string, request models.SavedSearchCreateRequest) (models.SavedSearchResponse, error)
+	Update(ctx context.Context, username string, id int, request models.SavedSearchUpdateRequest) (models.SavedSearchResponse, error)
+	FindByID(ctx context.Context, username string, id int) (models.SavedSearchResponse, error)
+	FindAll(ctx context.Context, username string) ([]models.SavedSearchResponse, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+	SearchContacts(ctx context.Context, username string, id int, page int, size int) ([]contactModels.ContactResponse, contactModels.Paging, error)
+	SearchContactsCursor(ctx context.Context, username string, id int, cursor string, size int) ([]contactModels.ContactResponse, contactModels.CursorPaging, error)
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	"golang-contact-management-restful-api/modules/savedsearch/domain"
+	"golang-contact-management-restful-api/modules/savedsearch/entities"
+	"golang-contact-management-restful-api/modules/savedsearch/models"
+	"golang-contact-management-restful-api/modules/savedsearch/repository"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type savedSearchUsecaseImpl struct {
+	savedSearchRepository repository.SavedSearchRepository
+	contactUsecase        contactUsecase.ContactUsecase
+	validator             *validator.Validate
+}
+
+func NewSavedSearchUsecase(savedSearchRepository repository.SavedSearchRepository, contactUsecase contactUsecase.ContactUsecase, validator *validator.Validate) SavedSearchUsecase {
+	return &savedSearchUsecaseImpl{
+		savedSearchRepository: savedSearchRepository,
+		contactUsecase:        contactUsecase,
+		validator:             validator,
+	}
+}
+
+func (usecase *savedSearchUsecaseImpl) Create(ctx context.Context, username string, request models.SavedSearchCreateRequest) (models.SavedSearchResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	if err := usecase.ensureNameAvailable(ctx, username, request.Name, 0); err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	savedSearch := entities.SavedSearch{
+		Name:  request.Name,
+		Query: toEntityQuery(request.Query),
+	}
+
+	memberCount, err := usecase.memberCount(ctx, username, savedSearch.Query)
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	saved, err := usecase.savedSearchRepository.Save(ctx, username, savedSearch)
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	return toSavedSearchResponse(saved, memberCount), nil
+}
+
+func (usecase *savedSearchUsecaseImpl) Update(ctx context.Context, username string, id int, request models.SavedSearchUpdateRequest) (models.SavedSearchResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	savedSearch, err := usecase.savedSearchRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	if request.Name != "" {
+		if err := usecase.ensureNameAvailable(ctx, username, request.Name, id); err != nil {
+			return models.SavedSearchResponse{}, err
+		}
+		savedSearch.Name = request.Name
+	}
+
+	if request.Query != nil {
+		if err := usecase.validator.Struct(request.Query); err != nil {
+			return models.SavedSearchResponse{}, err
+		}
+		savedSearch.Query = toEntityQuery(*request.Query)
+	}
+
+	memberCount, err := usecase.memberCount(ctx, username, savedSearch.Query)
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	updated, err := usecase.savedSearchRepository.UpdateByID(ctx, username, id, savedSearch)
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	return toSavedSearchResponse(updated, memberCount), nil
+}
+
+func (usecase *savedSearchUsecaseImpl) FindByID(ctx context.Context, username string, id int) (models.SavedSearchResponse, error) {
+	savedSearch, err := usecase.savedSearchRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	memberCounts, err := usecase.memberCounts(ctx, username, []entities.SavedSearch{savedSearch})
+	if err != nil {
+		return models.SavedSearchResponse{}, err
+	}
+
+	return toSavedSearchResponse(savedSearch, memberCounts[0]), nil
+}
+
+func (usecase *savedSearchUsecaseImpl) FindAll(ctx context.Context, username string) ([]models.SavedSearchResponse, error) {
+	savedSearches, err := usecase.savedSearchRepository.FindAll(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	memberCounts, err := usecase.memberCounts(ctx, username, savedSearches)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.SavedSearchResponse, len(savedSearches))
+	for i, savedSearch := range savedSearches {
+		responses[i] = toSavedSearchResponse(savedSearch, memberCounts[i])
+	}
+
+	return responses, nil
+}
+
+func (usecase *savedSearchUsecaseImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	return usecase.savedSearchRepository.DeleteByID(ctx, username, id)
+}
+
+func (usecase *savedSearchUsecaseImpl) SearchContacts(ctx context.Context, username string, id int, page int, size int) ([]contactModels.ContactResponse, contactModels.Paging, error) {
+	savedSearch, err := usecase.savedSearchRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	query := savedSearch.Query.ContactQuery()
+	query.Page = page
+	query.Size = size
+
+	return usecase.contactUsecase.Search(ctx, username, query)
+}
+
+func (usecase *savedSearchUsecaseImpl) SearchContactsCursor(ctx context.Context, username string, id int, cursor string, size int) ([]contactModels.ContactResponse, contactModels.CursorPaging, error) {
+	savedSearch, err := usecase.savedSearchRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return nil, contactModels.CursorPaging{}, err
+	}
+
+	query := savedSearch.Query.ContactQuery()
+	query.Cursor = cursor
+	query.Size = size
+
+	return usecase.contactUsecase.SearchCursor(ctx, username, query)
+}
+
+func (usecase *savedSearchUsecaseImpl) ensureNameAvailable(ctx context.Context, username string, name string, excludeID int) error {
+	exists, err := usecase.savedSearchRepository.ExistsByName(ctx, username, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrSavedSearchNameTaken
+	}
+	return nil
+}
+
+// memberCount counts the contacts currently matching the saved query. The
+// query runs through a regular search so that an invalid filter or sort is
+// rejected before it is stored.
+func (usecase *savedSearchUsecaseImpl) memberCount(ctx context.Context, username string, query entities.SavedSearchQuery) (int, error) {
+	contactQuery := query.ContactQuery()
+	contactQuery.Page = 1
+	contactQuery.Size = 1
+	_, paging, err := usecase.contactUsecase.Search(ctx, username, contactQuery)
+	if err != nil {
+		return 0, err
+	}
+	return paging.TotalItem, nil
+}
+
+// memberCounts counts the contacts currently matching each of the stored
+// saved searches, in a single query.
+func (usecase *savedSearchUsecaseImpl) memberCounts(ctx context.Context, username string, savedSearches []entities.SavedSearch) ([]int, error) {
+	queries := make([]contactModels.ContactSearchQuery, len(savedSearches))
+	for i, savedSearch := range savedSearches {
+		queries[i] = savedSearch.Query.ContactQuery()
+	}
+	return usecase.contactUsecase.CountEach(ctx, username, queries)
+}
+
+func toEntityQuery(query models.SavedSearchQuery) entities.SavedSearchQuery {
+	return entities.SavedSearchQuery{
+		Query:   query.Query,
+		Name:    query.Name,
+		Email:   query.Email,
+		Phone:   query.Phone,
+		Company: query.Company,
+		Fuzzy:   query.Fuzzy,
+		Filter:  query.Filter,
+		Sort:    query.Sort,
+	}
+}
+
+func toSavedSearchResponse(savedSearch entities.SavedSearch, memberCount int) models.SavedSearchResponse {
+	return models.SavedSearchResponse{
+		ID:   savedSearch.ID,
+		Name: savedSearch.Name,
+		Query: models.SavedSearchQuery{
+			Query:   savedSearch.Query.Query,
+			Name:    savedSearch.Query.Name,
+			Email:   savedSearch.Query.Email,
+			Phone:   savedSearch.Query.Phone,
+			Company: savedSearch.Query.Company,
+			Fuzzy:   savedSearch.Query.Fuzzy,
+			Filter:  savedSearch.Query.Filter,
+			Sort:    savedSearch.Query.Sort,
+		},
+		MemberCount: memberCount,
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrShareNotFound        = errors.New("share not found")
+	ErrShareWithSelf        = errors.New("cannot share with yourself")
+	ErrGranteeNotFound      = errors.New("the user to share with does not exist")
+	ErrShareAlreadyExists   = errors.New("this contact, saved search or address book is already shared with the user")
+	ErrShareAlreadyAccepted = errors.New("share has already been accepted")
+	ErrShareNotOwner        = errors.New("only the owner can change a share")
+)
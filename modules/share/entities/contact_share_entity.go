@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+)
+
+// ContactShare grants GranteeUsername access to one of OwnerUsername's
+// contacts, to those matching one of their saved searches, or to the whole
+// address book when ContactID and SavedSearchID are nil. It only takes
+// effect once the grantee has accepted it.
+type ContactShare struct {
+	ID              int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	OwnerUsername   string     `json:"owner_username" gorm:"column:owner_username;size:255;not null;index"`
+	GranteeUsername string     `json:"grantee_username" gorm:"column:grantee_username;size:255;not null;index"`
+	ContactID       *int       `json:"contact_id" gorm:"column:contact_id"`
+	SavedSearchID   *int       `json:"saved_search_id" gorm:"column:saved_search_id;index"`
+	Permission      string     `json:"permission" gorm:"column:permission;size:10;not null"`
+	Status          string     `json:"status" gorm:"column:status;size:10;not null"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	AcceptedAt      *time.Time `json:"accepted_at" gorm:"column:accepted_at"`
+}
+
+func (ContactShare) TableName() string {
+	return "contact_shares"
+}
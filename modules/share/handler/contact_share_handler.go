@@ -0,0 +1,11 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type ContactShareHandler interface {
+	Create(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	UpdateByID(ctx *fiber.Ctx) error
+	Accept(ctx *fiber.Ctx) error
+	DeleteByID(ctx *fiber.Ctx) error
+}
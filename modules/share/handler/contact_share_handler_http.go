@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	"golang-contact-management-restful-api/modules/share/domain"
+	"golang-contact-management-restful-api/modules/share/models"
+	"golang-contact-management-restful-api/modules/share/usecase"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type contactShareHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.ContactShareUsecase
+	validate *validator.Validate
+}
+
+func NewContactShareHttpHandler(app *fiber.App, usecase usecase.ContactShareUsecase) ContactShareHandler {
+	return &contactShareHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *contactShareHandlerHttp) Create(ctx *fiber.Ctx) error {
+	var request models.ShareCreateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.Create(ctx.Context(), username, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(http.DataEnvelope[models.ShareResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactShareHandlerHttp) FindAll(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	response, err := handler.usecase.FindAll(ctx.Context(), username, ctx.Query("direction"))
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[[]models.ShareResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactShareHandlerHttp) UpdateByID(ctx *fiber.Ctx) error {
+	var request models.ShareUpdateRequest
+	if !http.ParseBody(ctx, &request) {
+		return nil
+	}
+
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.Update(ctx.Context(), username, id, request)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ShareResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactShareHandlerHttp) Accept(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	response, err := handler.usecase.Accept(ctx.Context(), username, id)
+	if err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.ShareResponse]{
+		Data: response,
+	})
+}
+
+func (handler *contactShareHandlerHttp) DeleteByID(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: "Invalid ID"})
+	}
+
+	if err := handler.usecase.DeleteByID(ctx.Context(), username, id); err != nil {
+		return handler.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[string]{
+		Data: "OK",
+	})
+}
+
+func (handler *contactShareHandlerHttp) errorResponse(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrShareNotFound), errors.Is(err, domain.ErrGranteeNotFound), errors.Is(err, contactDomain.ErrContactNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrShareNotOwner):
+		return ctx.Status(fiber.StatusForbidden).JSON(http.ErrorResponse{Errors: err.Error()})
+	case errors.Is(err, domain.ErrShareAlreadyExists), errors.Is(err, domain.ErrShareAlreadyAccepted):
+		return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+	default:
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+}
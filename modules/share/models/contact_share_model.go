@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+
+	ScopeContact     = "contact"
+	ScopeGroup       = "group"
+	ScopeAddressBook = "address_book"
+)
+
+type ShareCreateRequest struct {
+	Grantee   string `json:"grantee" validate:"required,min=3,max=255"`
+	ContactID *int   `json:"contact_id" validate:"omitempty,min=1"`
+	// SavedSearchID shares the contacts matching a saved search, as they
+	// change, instead of a single contact.
+	SavedSearchID *int   `json:"saved_search_id" validate:"omitempty,min=1,excluded_with=ContactID"`
+	Permission    string `json:"permission" validate:"required,oneof=read write"`
+}
+
+type ShareUpdateRequest struct {
+	Permission string `json:"permission" validate:"required,oneof=read write"`
+}
+
+type ShareResponse struct {
+	ID            int        `json:"id"`
+	Owner         string     `json:"owner"`
+	Grantee       string     `json:"grantee"`
+	ContactID     *int       `json:"contact_id,omitempty"`
+	SavedSearchID *int       `json:"saved_search_id,omitempty"`
+	Scope         string     `json:"scope"`
+	Permission    string     `json:"permission"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	AcceptedAt    *time.Time `json:"accepted_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/share/entities"
+)
+
+type ContactShareRepository interface {
+	Save(ctx context.Context, share entities.ContactShare) (entities.ContactShare, error)
+	FindByID(ctx context.Context, username string, id int) (entities.ContactShare, error)
+	FindAll(ctx context.Context, username string, direction string) ([]entities.ContactShare, error)
+	Exists(ctx context.Context, owner string, grantee string, contactID *int, savedSearchID *int) (bool, error)
+	UpdatePermission(ctx context.Context, id int, permission string) (entities.ContactShare, error)
+	Accept(ctx context.Context, id int) (entities.ContactShare, error)
+	DeleteByID(ctx context.Context, id int) error
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	"golang-contact-management-restful-api/modules/share/domain"
+	"golang-contact-management-restful-api/modules/share/entities"
+	"golang-contact-management-restful-api/modules/share/models"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type contactShareRepositoryImpl struct {
+	DB      *gorm.DB
+	members *contactRepository.Members
+}
+
+func NewContactShareRepository(db *gorm.DB, members *contactRepository.Members) ContactShareRepository {
+	return &contactShareRepositoryImpl{DB: db, members: members}
+}
+
+// Save creates the share. The members of a shared saved search are computed
+// in the same transaction, and kept up to date by the contact writes from
+// then on.
+func (repository *contactShareRepositoryImpl) Save(ctx context.Context, share entities.ContactShare) (entities.ContactShare, error) {
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&share).Error; err != nil {
+			return err
+		}
+		if share.SavedSearchID == nil {
+			return nil
+		}
+		return repository.members.Replace(ctx, tx, *share.SavedSearchID)
+	})
+	if err != nil {
+		return entities.ContactShare{}, err
+	}
+	return share, nil
+}
+
+// FindByID returns a share the user either granted or received.
+func (repository *contactShareRepositoryImpl) FindByID(ctx context.Context, username string, id int) (entities.ContactShare, error) {
+	var share entities.ContactShare
+	if err := repository.DB.WithContext(ctx).
+		Where("id = ? AND (owner_username = ? OR grantee_username = ?)", id, username, username).
+		Take(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ContactShare{}, domain.ErrShareNotFound
+		}
+		return entities.ContactShare{}, err
+	}
+	return share, nil
+}
+
+func (repository *contactShareRepositoryImpl) FindAll(ctx context.Context, username string, direction string) ([]entities.ContactShare, error) {
+	db := repository.DB.WithContext(ctx)
+	switch direction {
+	case models.DirectionIncoming:
+		db = db.Where("grantee_username = ?", username)
+	case models.DirectionOutgoing:
+		db = db.Where("owner_username = ?", username)
+	default:
+		db = db.Where("owner_username = ? OR grantee_username = ?", username, username)
+	}
+
+	var shares []entities.ContactShare
+	if err := db.Order("id DESC").Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (repository *contactShareRepositoryImpl) Exists(ctx context.Context, owner string, grantee string, contactID *int, savedSearchID *int) (bool, error) {
+	db := repository.DB.WithContext(ctx).Model(&entities.ContactShare{}).
+		Where("owner_username = ? AND grantee_username = ?", owner, grantee)
+	if contactID == nil {
+		db = db.Where("contact_id IS NULL")
+	} else {
+		db = db.Where("contact_id = ?", *contactID)
+	}
+	if savedSearchID == nil {
+		db = db.Where("saved_search_id IS NULL")
+	} else {
+		db = db.Where("saved_search_id = ?", *savedSearchID)
+	}
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repository *contactShareRepositoryImpl) UpdatePermission(ctx context.Context, id int, permission string) (entities.ContactShare, error) {
+	return repository.update(ctx, id, map[string]any{"permission": permission})
+}
+
+// Accept makes the share take effect, recording the shared contacts as
+// changed for the grantee's sync.
+func (repository *contactShareRepositoryImpl) Accept(ctx context.Context, id int) (entities.ContactShare, error) {
+	var accepted entities.ContactShare
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		accepted, err = (&contactShareRepositoryImpl{DB: tx, members: repository.members}).update(ctx, id, map[string]any{
+			"status":      entities.StatusAccepted,
+			"accepted_at": time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		return changelog.Share(tx, id)
+	})
+	return accepted, err
+}
+
+// DeleteByID revokes the share. The contacts it covered are recorded as
+// changed so that the grantee's sync drops them.
+func (repository *contactShareRepositoryImpl) DeleteByID(ctx context.Context, id int) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := changelog.Share(tx, id); err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entities.ContactShare{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrShareNotFound
+		}
+		return nil
+	})
+}
+
+func (repository *contactShareRepositoryImpl) update(ctx context.Context, id int, updateMap map[string]any) (entities.ContactShare, error) {
+	result := repository.DB.WithContext(ctx).Model(&entities.ContactShare{}).Where("id = ?", id).Updates(updateMap)
+	if result.Error != nil {
+		return entities.ContactShare{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.ContactShare{}, domain.ErrShareNotFound
+	}
+
+	var updated entities.ContactShare
+	if err := repository.DB.WithContext(ctx).Take(&updated, "id = ?", id).Error; err != nil {
+		return entities.ContactShare{}, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/share/models"
+)
+
+type ContactShareUsecase interface {
+	Create(ctx context.Context, username string, request models.ShareCreateRequest) (models.ShareResponse, error)
+	FindAll(ctx context.Context, username string, direction string) ([]models.ShareResponse, error)
+	Update(ctx context.Context, username string, id int, request models.ShareUpdateRequest) (models.ShareResponse, error)
+	Accept(ctx context.Context, username string, id int) (models.ShareResponse, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+}
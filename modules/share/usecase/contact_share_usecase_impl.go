@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	contactDomain "golang-contact-management-restful-api/modules/contact/domain"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	savedSearchRepository "golang-contact-management-restful-api/modules/savedsearch/repository"
+	"golang-contact-management-restful-api/modules/share/domain"
+	"golang-contact-management-restful-api/modules/share/entities"
+	"golang-contact-management-restful-api/modules/share/models"
+	"golang-contact-management-restful-api/modules/share/repository"
+	userRepository "golang-contact-management-restful-api/modules/user/repository"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type contactShareUsecaseImpl struct {
+	contactShareRepository repository.ContactShareRepository
+	contactRepository      contactRepository.ContactRepository
+	savedSearchRepository  savedSearchRepository.SavedSearchRepository
+	userRepository         userRepository.UserRepository
+	validator              *validator.Validate
+}
+
+func NewContactShareUsecase(contactShareRepository repository.ContactShareRepository, contactRepository contactRepository.ContactRepository, savedSearchRepository savedSearchRepository.SavedSearchRepository, userRepository userRepository.UserRepository, validator *validator.Validate) ContactShareUsecase {
+	return &contactShareUsecaseImpl{
+		contactShareRepository: contactShareRepository,
+		contactRepository:      contactRepository,
+		savedSearchRepository:  savedSearchRepository,
+		userRepository:         userRepository,
+		validator:              validator,
+	}
+}
+
+func (usecase *contactShareUsecaseImpl) Create(ctx context.Context, username string, request models.ShareCreateRequest) (models.ShareResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.ShareResponse{}, err
+	}
+
+	if request.Grantee == username {
+		return models.ShareResponse{}, domain.ErrShareWithSelf
+	}
+
+	exists, err := usecase.userRepository.ExistsByUsername(ctx, request.Grantee)
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+	if !exists {
+		return models.ShareResponse{}, domain.ErrGranteeNotFound
+	}
+
+	// Only contacts the user owns can be shared on; contacts shared with
+	// them are not passed along.
+	if request.ContactID != nil {
+		contact, err := usecase.contactRepository.FindByID(ctx, username, *request.ContactID)
+		if err != nil {
+			return models.ShareResponse{}, err
+		}
+		if contact.Username != username || contact.OrganizationID != nil {
+			return models.ShareResponse{}, contactDomain.ErrContactNotFound
+		}
+	}
+	if request.SavedSearchID != nil {
+		if _, err := usecase.savedSearchRepository.FindByID(ctx, username, *request.SavedSearchID); err != nil {
+			return models.ShareResponse{}, err
+		}
+	}
+
+	exists, err = usecase.contactShareRepository.Exists(ctx, username, request.Grantee, request.ContactID, request.SavedSearchID)
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+	if exists {
+		return models.ShareResponse{}, domain.ErrShareAlreadyExists
+	}
+
+	saved, err := usecase.contactShareRepository.Save(ctx, entities.ContactShare{
+		OwnerUsername:   username,
+		GranteeUsername: request.Grantee,
+		ContactID:       request.ContactID,
+		SavedSearchID:   request.SavedSearchID,
+		Permission:      request.Permission,
+		Status:          entities.StatusPending,
+	})
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+
+	return toShareResponse(saved), nil
+}
+
+func (usecase *contactShareUsecaseImpl) FindAll(ctx context.Context, username string, direction string) ([]models.ShareResponse, error) {
+	shares, err := usecase.contactShareRepository.FindAll(ctx, username, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ShareResponse, len(shares))
+	for i, share := range shares {
+		responses[i] = toShareResponse(share)
+	}
+	return responses, nil
+}
+
+func (usecase *contactShareUsecaseImpl) Update(ctx context.Context, username string, id int, request models.ShareUpdateRequest) (models.ShareResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.ShareResponse{}, err
+	}
+
+	share, err := usecase.contactShareRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+	if share.OwnerUsername != username {
+		return models.ShareResponse{}, domain.ErrShareNotOwner
+	}
+
+	updated, err := usecase.contactShareRepository.UpdatePermission(ctx, id, request.Permission)
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+
+	return toShareResponse(updated), nil
+}
+
+// Accept is called by the grantee to accept a pending invitation.
+func (usecase *contactShareUsecaseImpl) Accept(ctx context.Context, username string, id int) (models.ShareResponse, error) {
+	share, err := usecase.contactShareRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+	if share.GranteeUsername != username {
+		return models.ShareResponse{}, domain.ErrShareNotFound
+	}
+	if share.Status == entities.StatusAccepted {
+		return models.ShareResponse{}, domain.ErrShareAlreadyAccepted
+	}
+
+	accepted, err := usecase.contactShareRepository.Accept(ctx, id)
+	if err != nil {
+		return models.ShareResponse{}, err
+	}
+
+	return toShareResponse(accepted), nil
+}
+
+// DeleteByID revokes a share when called by the owner, and declines or
+// leaves it when called by the grantee.
+func (usecase *contactShareUsecaseImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	if _, err := usecase.contactShareRepository.FindByID(ctx, username, id); err != nil {
+		return err
+	}
+
+	return usecase.contactShareRepository.DeleteByID(ctx, id)
+}
+
+func toShareResponse(share entities.ContactShare) models.ShareResponse {
+	scope := models.ScopeAddressBook
+	if share.ContactID != nil {
+		scope = models.ScopeContact
+	} else if share.SavedSearchID != nil {
+		scope = models.ScopeGroup
+	}
+
+	return models.ShareResponse{
+		ID:            share.ID,
+		Owner:         share.OwnerUsername,
+		Grantee:       share.GranteeUsername,
+		ContactID:     share.ContactID,
+		SavedSearchID: share.SavedSearchID,
+		Scope:         scope,
+		Permission:    share.Permission,
+		Status:        share.Status,
+		CreatedAt:     share.CreatedAt,
+		AcceptedAt:    share.AcceptedAt,
+	}
+}
@@ -0,0 +1,225 @@
+// Package changelog appends to the change log read by the sync API, and
+// publishes the matching domain events to the outbox. Repositories call it
+// inside the transaction that writes a contact or an address, so entries
+// commit or roll back together with the change.
+package changelog
+
+import (
+	"golang-contact-management-restful-api/internal/outbox"
+	"golang-contact-management-restful-api/modules/sync/entities"
+
+	"gorm.io/gorm"
+)
+
+// Channel is the notification channel told about new entries. Writers
+// notify it through Postgres, which delivers on commit to listeners such as
+// the Postgres pub/sub broker.
+const Channel = "change_log"
+
+// Contact records a write to a contact. Deletions must be recorded before
+// the contact is deleted, and record the deletion of its addresses too.
+func Contact(tx *gorm.DB, contactID int, action string) error {
+	if err := begin(tx); err != nil {
+		return err
+	}
+
+	if action == entities.ActionDelete {
+		if err := AddressesDeleted(tx, contactID); err != nil {
+			return err
+		}
+
+		// Grantees only see the owner's entries while they can read the
+		// contact, which ends with the deletion.
+		if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+			SELECT DISTINCT ?, c.id, c.id, ?, s.grantee_username, NULL, now()
+			FROM "contact_shares" s JOIN "contacts" c ON c.username = s.owner_username AND c.organization_id IS NULL
+			WHERE c.id = ? AND s.status = 'accepted' AND `+covers+`
+			ORDER BY s.grantee_username`,
+			entities.EntityContact, entities.ActionDelete, contactID).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, c.id, c.id, ?, c.username, c.organization_id, now() FROM "contacts" c WHERE c.id = ?`,
+		entities.EntityContact, action, contactID).Error; err != nil {
+		return err
+	}
+
+	return publish(tx, `SELECT ?, c.id::text, c.username, c.organization_id, `+contactPayload+`, now() FROM "contacts" c WHERE c.id = ?`,
+		eventType(entities.EntityContact, action), contactID)
+}
+
+// CompanyContacts records a write to every contact of a company. It must
+// be called before they are unlinked from it.
+func CompanyContacts(tx *gorm.DB, companyID int) error {
+	if err := begin(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, c.id, c.id, ?, c.username, c.organization_id, now() FROM "contacts" c WHERE c.company_id = ?`,
+		entities.EntityContact, entities.ActionUpdate, companyID).Error; err != nil {
+		return err
+	}
+
+	return publish(tx, `SELECT ?, c.id::text, c.username, c.organization_id, `+contactPayload+`, now()
+		FROM "contacts" c WHERE c.company_id = ? ORDER BY c.id`,
+		outbox.ContactUpdated, companyID)
+}
+
+// Address records a write to an address of a contact.
+func Address(tx *gorm.DB, contactID int, addressID int, action string) error {
+	if err := begin(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, ?, c.id, ?, c.username, c.organization_id, now() FROM "contacts" c WHERE c.id = ?`,
+		entities.EntityAddress, addressID, action, contactID).Error; err != nil {
+		return err
+	}
+
+	// A deleted address is already gone, so its event only carries ids.
+	if action == entities.ActionDelete {
+		return publish(tx, `SELECT ?, ?::text, c.username, c.organization_id, jsonb_build_object('id', ?::int, 'contact_id', c.id), now()
+			FROM "contacts" c WHERE c.id = ?`,
+			outbox.AddressDeleted, addressID, addressID, contactID)
+	}
+
+	return publish(tx, `SELECT ?, a.id::text, c.username, c.organization_id, to_jsonb(a), now()
+		FROM "addresses" a JOIN "contacts" c ON c.id = a.contact_id WHERE a.id = ? AND a.contact_id = ?`,
+		eventType(entities.EntityAddress, action), addressID, contactID)
+}
+
+// AddressesDeleted records the deletion of every address of a contact,
+// for its owner and the users it is shared with. It must be called before
+// they are deleted.
+func AddressesDeleted(tx *gorm.DB, contactID int) error {
+	if err := begin(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, a.id, c.id, ?, c.username, c.organization_id, now()
+		FROM "addresses" a JOIN "contacts" c ON c.id = a.contact_id WHERE a.contact_id = ?`,
+		entities.EntityAddress, entities.ActionDelete, contactID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT DISTINCT ?, a.id, c.id, ?, s.grantee_username, NULL, now()
+		FROM "contact_shares" s JOIN "contacts" c ON c.username = s.owner_username AND c.organization_id IS NULL
+		JOIN "addresses" a ON a.contact_id = c.id
+		WHERE c.id = ? AND s.status = 'accepted' AND `+covers+`
+		ORDER BY s.grantee_username, a.id`,
+		entities.EntityAddress, entities.ActionDelete, contactID).Error; err != nil {
+		return err
+	}
+
+	return publish(tx, `SELECT ?, a.id::text, c.username, c.organization_id, to_jsonb(a), now()
+		FROM "addresses" a JOIN "contacts" c ON c.id = a.contact_id WHERE a.contact_id = ? ORDER BY a.id`,
+		outbox.AddressDeleted, contactID)
+}
+
+// Share records a write, as seen by the grantee, to every contact covered
+// by a share and to their addresses, since accepting or revoking it changes
+// what the grantee can read. A revoked share must be recorded before it is
+// deleted.
+func Share(tx *gorm.DB, shareID int) error {
+	if err := begin(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, c.id, c.id, ?, s.grantee_username, NULL, now()
+		FROM "contact_shares" s JOIN "contacts" c ON c.username = s.owner_username AND c.organization_id IS NULL
+		WHERE s.id = ? AND s.status = 'accepted' AND `+covers+`
+		ORDER BY c.id`,
+		entities.EntityContact, entities.ActionUpdate, shareID).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, a.id, c.id, ?, s.grantee_username, NULL, now()
+		FROM "contact_shares" s JOIN "contacts" c ON c.username = s.owner_username AND c.organization_id IS NULL
+		JOIN "addresses" a ON a.contact_id = c.id
+		WHERE s.id = ? AND s.status = 'accepted' AND `+covers+`
+		ORDER BY a.id`,
+		entities.EntityAddress, entities.ActionUpdate, shareID).Error
+}
+
+// Members records a write, as seen by the grantees of a saved search, to
+// contacts that started or stopped matching it, and to their addresses, as
+// that changes what the grantees can read.
+func Members(tx *gorm.DB, savedSearchID int, contactIDs []int) error {
+	if len(contactIDs) == 0 {
+		return nil
+	}
+
+	if err := begin(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, c.id, c.id, ?, s.grantee_username, NULL, now()
+		FROM "contact_shares" s JOIN "contacts" c ON c.username = s.owner_username AND c.organization_id IS NULL
+		WHERE s.saved_search_id = ? AND s.status = 'accepted' AND c.id IN ?
+		ORDER BY c.id`,
+		entities.EntityContact, entities.ActionUpdate, savedSearchID, contactIDs).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`INSERT INTO "change_log" ("entity", "entity_id", "contact_id", "action", "username", "organization_id", "created_at")
+		SELECT ?, a.id, c.id, ?, s.grantee_username, NULL, now()
+		FROM "contact_shares" s JOIN "contacts" c ON c.username = s.owner_username AND c.organization_id IS NULL
+		JOIN "addresses" a ON a.contact_id = c.id
+		WHERE s.saved_search_id = ? AND s.status = 'accepted' AND c.id IN ?
+		ORDER BY a.id`,
+		entities.EntityAddress, entities.ActionUpdate, savedSearchID, contactIDs).Error
+}
+
+// covers matches the contacts c of its owner that share s applies to: the
+// whole address book, one contact, or the members of a saved search.
+const covers = `(s.contact_id IS NULL AND s.saved_search_id IS NULL OR s.contact_id = c.id ` +
+	`OR EXISTS (SELECT 1 FROM "saved_search_members" m WHERE m.saved_search_id = s.saved_search_id AND m.contact_id = c.id))`
+
+// contactPayload is the state of contact c published in its events, without
+// the columns kept for searching and deduplication.
+const contactPayload = `to_jsonb(c) - 'search_vector' - 'email_key'`
+
+// publish appends to the outbox the events selected by query, which must
+// return their type, aggregate id, username, organization id, payload and
+// creation time.
+func publish(tx *gorm.DB, query string, args ...any) error {
+	if err := outbox.Begin(tx); err != nil {
+		return err
+	}
+	return tx.Exec(`INSERT INTO "outbox_events" ("metadata", "type", "aggregate_id", "username", "organization_id", "payload", "created_at")
+		SELECT ?::jsonb, e.* FROM (`+query+`) e`, append([]any{outbox.Metadata(tx)}, args...)...).Error
+}
+
+// eventType is the type of the domain event for action on entity.
+func eventType(entity string, action string) string {
+	return eventTypes[entity][action]
+}
+
+var eventTypes = map[string]map[string]string{
+	entities.EntityContact: {
+		entities.ActionCreate: outbox.ContactCreated,
+		entities.ActionUpdate: outbox.ContactUpdated,
+		entities.ActionDelete: outbox.ContactDeleted,
+	},
+	entities.EntityAddress: {
+		entities.ActionCreate: outbox.AddressCreated,
+		entities.ActionUpdate: outbox.AddressUpdated,
+		entities.ActionDelete: outbox.AddressDeleted,
+	},
+}
+
+// begin prepares tx for appending to the change log. Like the outbox,
+// entries are read in the order of the transactions that wrote them, so
+// writers need not wait for each other.
+func begin(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_notify(?, '')", Channel).Error
+}
@@ -0,0 +1,64 @@
+package changelog
+
+import (
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun returns a session that only renders its statements, and the
+// statements it rendered so far.
+func dryRun(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statements []string
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:record", func(db *gorm.DB) {
+		statements = append(statements, db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return db, &statements
+}
+
+func TestContactRecordsGranteeTombstones(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		want   bool
+	}{
+		{name: "delete", action: entities.ActionDelete, want: true},
+		{name: "update", action: entities.ActionUpdate, want: false},
+		{name: "create", action: entities.ActionCreate, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, statements := dryRun(t)
+			if err := Contact(db, 7, tt.action); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, entity := range []string{entities.EntityContact, entities.EntityAddress} {
+				tombstone := "SELECT DISTINCT '" + entity + "', "
+				var found bool
+				for _, statement := range *statements {
+					if strings.Contains(statement, `INSERT INTO "change_log"`) && strings.Contains(statement, tombstone) &&
+						strings.Contains(statement, "'delete', s.grantee_username") && strings.Contains(statement, "c.id = 7") {
+						found = true
+					}
+				}
+				if found != tt.want {
+					t.Errorf("%s tombstone for grantees recorded = %v, want %v in:\n%s", entity, found, tt.want, strings.Join(*statements, "\n"))
+				}
+			}
+		})
+	}
+}
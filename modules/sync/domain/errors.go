@@ -0,0 +1,10 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidToken = errors.New("invalid sync token")
+	ErrTokenExpired = errors.New("sync token has expired, start a full sync without since")
+
+	ErrInvalidEventID = errors.New("invalid last event id")
+)
@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+const (
+	EntityContact = "contact"
+	EntityAddress = "address"
+
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is an entry of the change log, which orders the writes to
+// contacts and addresses by the transaction that made them, set by the
+// database, and then by a monotonic sequence number. Username and
+// OrganizationID are the owner of the contact, kept so deletions stay
+// scoped after the contact is gone.
+type Change struct {
+	Seq            int64     `json:"seq" gorm:"column:seq;primaryKey;autoIncrement;index:idx_change_log_position,priority:2"`
+	TransactionID  int64     `json:"-" gorm:"column:transaction_id;not null;default:(pg_current_xact_id())::text::bigint;index:idx_change_log_position,priority:1"`
+	Entity         string    `json:"entity" gorm:"column:entity;size:16;not null;index:idx_change_log_entity,priority:1"`
+	EntityID       int       `json:"entity_id" gorm:"column:entity_id;not null;index:idx_change_log_entity,priority:2"`
+	ContactID      int       `json:"contact_id" gorm:"column:contact_id;not null;index"`
+	Action         string    `json:"action" gorm:"column:action;size:16;not null"`
+	Username       string    `json:"username" gorm:"column:username;size:255;not null;index"`
+	OrganizationID *int      `json:"organization_id,omitempty" gorm:"column:organization_id;index"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Change) TableName() string {
+	return "change_log"
+}
+
+// Position is a place in the change log, after the entry written with seq
+// by the given transaction.
+type Position struct {
+	TransactionID int64
+	Seq           int64
+}
+
+// Position returns the position of the change.
+func (change Change) Position() Position {
+	return Position{TransactionID: change.TransactionID, Seq: change.Seq}
+}
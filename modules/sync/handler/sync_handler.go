@@ -0,0 +1,9 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type SyncHandler interface {
+	Sync(ctx *fiber.Ctx) error
+	Events(ctx *fiber.Ctx) error
+	WebSocket(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-contact-management-restful-api/internal/transport/http"
+	"golang-contact-management-restful-api/internal/transport/websocket"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/sync/domain"
+	"golang-contact-management-restful-api/modules/sync/models"
+	"golang-contact-management-restful-api/modules/sync/usecase"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type syncHandlerHttp struct {
+	app      *fiber.App
+	usecase  usecase.SyncUsecase
+	validate *validator.Validate
+}
+
+func NewSyncHttpHandler(app *fiber.App, usecase usecase.SyncUsecase) SyncHandler {
+	return &syncHandlerHttp{
+		app:      app,
+		usecase:  usecase,
+		validate: validator.New(),
+	}
+}
+
+func (handler *syncHandlerHttp) Sync(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit", strconv.Itoa(models.DefaultBatchSize)))
+
+	response, err := handler.usecase.Sync(ctx.Context(), username, models.SyncQuery{
+		Since: ctx.Query("since"),
+		Limit: limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTokenExpired):
+			return ctx.Status(fiber.StatusGone).JSON(http.ErrorResponse{Errors: err.Error()})
+		default:
+			return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(http.DataEnvelope[models.SyncResponse]{
+		Data: response,
+	})
+}
+
+// Events streams the user's events as Server-Sent Events. Clients resume
+// with the Last-Event-ID header, which EventSource sends when reconnecting,
+// or the last_event_id query parameter.
+func (handler *syncHandlerHttp) Events(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	lastEventID := ctx.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+	after, err := handler.usecase.ResumePoint(ctx.Context(), lastEventID)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	// As for exports, the stream writer runs after the handler has
+	// returned and only the workspace is carried over.
+	streamCtx := workspace.NewContext(context.Background(), workspace.FromContext(ctx.Context()))
+	ctx.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if _, err := w.WriteString("retry: 3000\n\n"); err != nil || w.Flush() != nil {
+			return
+		}
+
+		_ = handler.usecase.Stream(streamCtx, username, after, func(events []models.Event) error {
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				if _, err := w.WriteString("id: " + event.Position + "\nevent: " + event.Type + "\ndata: " + string(data) + "\n\n"); err != nil {
+					return err
+				}
+			}
+			return w.Flush()
+		}, func() error {
+			if _, err := w.WriteString(": heartbeat\n\n"); err != nil {
+				return err
+			}
+			return w.Flush()
+		})
+	})
+
+	return nil
+}
+
+// WebSocket streams the same events as Events over a WebSocket, one JSON
+// message per event. Clients resume with the last_event_id query parameter
+// set to the position of the last event they received.
+func (handler *syncHandlerHttp) WebSocket(ctx *fiber.Ctx) error {
+	username, err := http.MustGetUsername(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	if !websocket.IsUpgrade(ctx) {
+		return ctx.Status(fiber.StatusUpgradeRequired).JSON(http.ErrorResponse{Errors: websocket.ErrNotUpgrade.Error()})
+	}
+
+	after, err := handler.usecase.ResumePoint(ctx.Context(), ctx.Query("last_event_id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	streamCtx := workspace.NewContext(context.Background(), workspace.FromContext(ctx.Context()))
+	err = websocket.Upgrade(ctx, func(conn *websocket.Conn) {
+		streamCtx, cancel := context.WithCancel(streamCtx)
+		defer cancel()
+
+		// Messages from the client are not expected; reading answers its
+		// pings and notices when it goes away or stops answering ours.
+		conn.SetIdleTimeout(2 * models.HeartbeatInterval)
+		go func() {
+			defer cancel()
+			for {
+				if _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		_ = handler.usecase.Stream(streamCtx, username, after, func(events []models.Event) error {
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				if err := conn.WriteText(data); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, conn.Ping)
+		_ = conn.Close(websocket.CloseGoingAway, "")
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{Errors: err.Error()})
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	addressModels "golang-contact-management-restful-api/modules/address/models"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"time"
+)
+
+const (
+	DefaultBatchSize = 500
+	MaxBatchSize     = 1000
+
+	// EventBatchSize bounds the changes read at once for the event stream.
+	EventBatchSize = 100
+	// HeartbeatInterval is how often an idle event stream is kept alive,
+	// and checked for changes whose notification was missed.
+	HeartbeatInterval = 15 * time.Second
+)
+
+type SyncQuery struct {
+	Since string
+	Limit int
+}
+
+type SyncAddress struct {
+	ContactID int `json:"contact_id"`
+	addressModels.AddressResponse
+}
+
+// SyncDeleted lists the tombstones of a batch: ids of contacts and
+// addresses deleted since the token.
+type SyncDeleted struct {
+	Contacts  []int `json:"contacts"`
+	Addresses []int `json:"addresses"`
+}
+
+// SyncResponse holds the current state of the contacts and addresses that
+// changed since the token. NextToken resumes after this batch; more changes
+// are pending while HasMore is set.
+type SyncResponse struct {
+	Contacts  []contactModels.ContactResponse `json:"contacts"`
+	Addresses []SyncAddress                   `json:"addresses"`
+	Deleted   SyncDeleted                     `json:"deleted"`
+	NextToken string                          `json:"next_token"`
+	HasMore   bool                            `json:"has_more"`
+}
+
+// Event is a change to a contact or an address pushed on the event stream.
+// Its type is contact.created, contact.updated, contact.deleted or the
+// same for address. Created and updated events carry the current state.
+// Position, also the event's SSE id, is where the stream resumes after it.
+// Events may be delivered more than once, for example after a resume, so
+// clients apply them idempotently.
+type Event struct {
+	ID        int64                          `json:"id"`
+	Position  string                         `json:"position,omitempty"`
+	Type      string                         `json:"type"`
+	EntityID  int                            `json:"entity_id"`
+	ContactID int                            `json:"contact_id"`
+	Contact   *contactModels.ContactResponse `json:"contact,omitempty"`
+	Address   *addressModels.AddressResponse `json:"address,omitempty"`
+}
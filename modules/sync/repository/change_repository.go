@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"time"
+)
+
+type ChangeRepository interface {
+	Head(ctx context.Context) (int64, error)
+	Horizon(ctx context.Context) (entities.Position, error)
+	FindSince(ctx context.Context, username string, after entities.Position, limit int) ([]entities.Change, error)
+	FindContacts(ctx context.Context, username string, ids []int) ([]contactEntities.Contact, error)
+	FindAddresses(ctx context.Context, username string, ids []int) ([]addressEntities.Address, error)
+	Prune(ctx context.Context, before time.Time) error
+}
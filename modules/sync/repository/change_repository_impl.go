@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/outbox"
+	"golang-contact-management-restful-api/internal/workspace"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	contactRepository "golang-contact-management-restful-api/modules/contact/repository"
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type changeRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewChangeRepository(db *gorm.DB) ChangeRepository {
+	return &changeRepositoryImpl{DB: db}
+}
+
+// Head returns the sequence number of the latest change.
+func (repository *changeRepositoryImpl) Head(ctx context.Context) (int64, error) {
+	var seq int64
+	if err := repository.DB.WithContext(ctx).Model(&entities.Change{}).
+		Select("coalesce(max(seq), 0)").Scan(&seq).Error; err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// Horizon returns the position before the changes of the transactions
+// still running, which readers have not seen yet.
+func (repository *changeRepositoryImpl) Horizon(ctx context.Context) (entities.Position, error) {
+	var position entities.Position
+	if err := repository.DB.WithContext(ctx).Raw("SELECT " + outbox.Horizon + " AS transaction_id, 0 AS seq").
+		Scan(&position).Error; err != nil {
+		return entities.Position{}, err
+	}
+	return position, nil
+}
+
+// FindSince returns up to limit changes after the given position, in order,
+// to contacts the user can read or deleted contacts owned in the workspace.
+// Only the changes of transactions older than any still running are read,
+// as those may yet commit changes ordered before later ones.
+func (repository *changeRepositoryImpl) FindSince(ctx context.Context, username string, after entities.Position, limit int) ([]entities.Change, error) {
+	owned, ownedArgs := workspace.Owned(ctx, "change_log", username)
+	readable, readableArgs := contactRepository.ReadableBy(ctx, username)
+
+	var changes []entities.Change
+	if err := repository.DB.WithContext(ctx).Model(&entities.Change{}).
+		Where("(change_log.transaction_id, change_log.seq) > (?, ?)", after.TransactionID, after.Seq).
+		Where("change_log.transaction_id < "+outbox.Horizon).
+		Where("(("+owned+") OR change_log.contact_id IN (?))",
+			append(ownedArgs, repository.DB.Table("contacts").Select("contacts.id").Where(readable, readableArgs...))...,
+		).
+		Order("change_log.transaction_id ASC, change_log.seq ASC").Limit(limit).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (repository *changeRepositoryImpl) FindContacts(ctx context.Context, username string, ids []int) ([]contactEntities.Contact, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	readable, args := contactRepository.ReadableBy(ctx, username)
+	var contacts []contactEntities.Contact
+	if err := repository.DB.WithContext(ctx).Where("contacts.id IN ?", ids).Where(readable, args...).
+		Order("contacts.id").Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+func (repository *changeRepositoryImpl) FindAddresses(ctx context.Context, username string, ids []int) ([]addressEntities.Address, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	readable, args := contactRepository.ReadableBy(ctx, username)
+	var addresses []addressEntities.Address
+	if err := repository.DB.WithContext(ctx).Model(&addressEntities.Address{}).
+		Joins("JOIN contacts ON contacts.id = addresses.contact_id").
+		Where("addresses.id IN ?", ids).Where(readable, args...).
+		Order("addresses.id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// Prune compacts the change log. Entries superseded by a later change to
+// the same entity, visible to the same users, are never needed as readers
+// only apply the latest state. Tombstones are kept until before, which is
+// why tokens issued earlier are rejected.
+func (repository *changeRepositoryImpl) Prune(ctx context.Context, before time.Time) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM "change_log" c USING "change_log" n
+			WHERE n.entity = c.entity AND n.entity_id = c.entity_id AND (n.transaction_id, n.seq) > (c.transaction_id, c.seq)
+			AND n.username = c.username AND n.organization_id IS NOT DISTINCT FROM c.organization_id`).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("action = ? AND created_at < ?", entities.ActionDelete, before).Delete(&entities.Change{}).Error
+	})
+}
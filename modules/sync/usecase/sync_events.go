@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	addressUsecase "golang-contact-management-restful-api/modules/address/usecase"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	"golang-contact-management-restful-api/modules/sync/changelog"
+	"golang-contact-management-restful-api/modules/sync/domain"
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"golang-contact-management-restful-api/modules/sync/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var eventActions = map[string]string{
+	entities.ActionCreate: "created",
+	entities.ActionUpdate: "updated",
+	entities.ActionDelete: "deleted",
+}
+
+// ResumePoint returns the position an event stream starts after: that of
+// the last event the client received, or the present when it has none.
+func (usecase *syncUsecaseImpl) ResumePoint(ctx context.Context, lastEventID string) (entities.Position, error) {
+	if lastEventID == "" {
+		return usecase.changeRepository.Horizon(ctx)
+	}
+
+	position, err := parsePosition(lastEventID)
+	if err != nil {
+		return entities.Position{}, domain.ErrInvalidEventID
+	}
+	return position, nil
+}
+
+// Stream sends the user's events after the given position as they happen,
+// until ctx is done or send or heartbeat fail. Heartbeats are sent while
+// the stream is idle.
+func (usecase *syncUsecaseImpl) Stream(ctx context.Context, username string, after entities.Position, send func(events []models.Event) error, heartbeat func() error) error {
+	notifications, unsubscribe := usecase.broker.Subscribe(changelog.Channel)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(models.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			events, last, err := usecase.Events(ctx, username, after)
+			if err != nil {
+				return err
+			}
+			if len(events) > 0 {
+				if err := send(events); err != nil {
+					return err
+				}
+			}
+			if last == after {
+				break
+			}
+			after = last
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notifications:
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Watch publishes the position of the latest change whenever it moves,
+// checking every interval until ctx is done. It feeds brokers that are not
+// told about changes by the database.
+func (usecase *syncUsecaseImpl) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var head int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		seq, err := usecase.changeRepository.Head(ctx)
+		if err != nil || seq == head {
+			continue
+		}
+		head = seq
+		_ = usecase.broker.Publish(ctx, changelog.Channel, strconv.FormatInt(seq, 10))
+	}
+}
+
+// Events returns the user's next batch of events after the given position,
+// with the position the batch ends at.
+func (usecase *syncUsecaseImpl) Events(ctx context.Context, username string, after entities.Position) ([]models.Event, entities.Position, error) {
+	changes, err := usecase.changeRepository.FindSince(ctx, username, after, models.EventBatchSize)
+	if err != nil || len(changes) == 0 {
+		return nil, after, err
+	}
+
+	var contactIDs, addressIDs []int
+	for _, change := range changes {
+		if change.Action == entities.ActionDelete {
+			continue
+		}
+		if change.Entity == entities.EntityContact {
+			contactIDs = append(contactIDs, change.EntityID)
+		} else {
+			addressIDs = append(addressIDs, change.EntityID)
+		}
+	}
+
+	contacts, err := usecase.changeRepository.FindContacts(ctx, username, contactIDs)
+	if err != nil {
+		return nil, after, err
+	}
+	addresses, err := usecase.changeRepository.FindAddresses(ctx, username, addressIDs)
+	if err != nil {
+		return nil, after, err
+	}
+
+	contactsByID := make(map[int]contactEntities.Contact, len(contacts))
+	for _, contact := range contacts {
+		contactsByID[contact.ID] = contact
+	}
+	addressesByID := make(map[int]addressEntities.Address, len(addresses))
+	for _, address := range addresses {
+		addressesByID[address.ID] = address
+	}
+
+	events := make([]models.Event, len(changes))
+	for i, change := range changes {
+		event := models.Event{
+			ID:        change.Seq,
+			Position:  formatPosition(change.Position()),
+			EntityID:  change.EntityID,
+			ContactID: change.ContactID,
+		}
+
+		// Entities are sent as they are now; one the user can no longer
+		// read is gone for them.
+		action := entities.ActionDelete
+		if change.Action != entities.ActionDelete {
+			if contact, ok := contactsByID[change.EntityID]; ok && change.Entity == entities.EntityContact {
+				response := contactUsecase.ToContactResponse(username, contact)
+				event.Contact = &response
+				action = change.Action
+			} else if address, ok := addressesByID[change.EntityID]; ok && change.Entity == entities.EntityAddress {
+				response := addressUsecase.ToAddressResponse(address)
+				event.Address = &response
+				action = change.Action
+			}
+		}
+
+		event.Type = change.Entity + "." + eventActions[action]
+		events[i] = event
+	}
+
+	return events, changes[len(changes)-1].Position(), nil
+}
+
+// formatPosition writes a position as the id of an event, the transaction
+// and sequence number separated by a dash.
+func formatPosition(position entities.Position) string {
+	return strconv.FormatInt(position.TransactionID, 10) + "-" + strconv.FormatInt(position.Seq, 10)
+}
+
+func parsePosition(value string) (entities.Position, error) {
+	transaction, seq, found := strings.Cut(value, "-")
+	if !found {
+		return entities.Position{}, domain.ErrInvalidEventID
+	}
+
+	transactionID, err := strconv.ParseUint(transaction, 10, 63)
+	if err != nil {
+		return entities.Position{}, domain.ErrInvalidEventID
+	}
+	parsedSeq, err := strconv.ParseUint(seq, 10, 63)
+	if err != nil {
+		return entities.Position{}, domain.ErrInvalidEventID
+	}
+	return entities.Position{TransactionID: int64(transactionID), Seq: int64(parsedSeq)}, nil
+}
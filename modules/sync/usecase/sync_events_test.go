@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"golang-contact-management-restful-api/modules/sync/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// changeLog is a change repository over changes already filtered for the
+// user, with the contacts and addresses they can still read.
+type changeLog struct {
+	changes   []entities.Change
+	contacts  []contactEntities.Contact
+	addresses []addressEntities.Address
+}
+
+func (log changeLog) Head(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (log changeLog) Horizon(ctx context.Context) (entities.Position, error) {
+	return entities.Position{}, nil
+}
+
+func (log changeLog) FindSince(ctx context.Context, username string, after entities.Position, limit int) ([]entities.Change, error) {
+	var changes []entities.Change
+	for _, change := range log.changes {
+		position := change.Position()
+		if position.TransactionID > after.TransactionID || position.TransactionID == after.TransactionID && position.Seq > after.Seq {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+func (log changeLog) FindContacts(ctx context.Context, username string, ids []int) ([]contactEntities.Contact, error) {
+	return log.contacts, nil
+}
+
+func (log changeLog) FindAddresses(ctx context.Context, username string, ids []int) ([]addressEntities.Address, error) {
+	return log.addresses, nil
+}
+
+func (log changeLog) Prune(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   changeLog
+		after entities.Position
+		want  []models.Event
+		last  entities.Position
+	}{
+		{
+			name: "grantee tombstones of a deleted shared contact",
+			log: changeLog{changes: []entities.Change{
+				{Seq: 11, TransactionID: 700, Entity: entities.EntityAddress, EntityID: 3, ContactID: 7, Action: entities.ActionDelete, Username: "bob"},
+				{Seq: 12, TransactionID: 700, Entity: entities.EntityContact, EntityID: 7, ContactID: 7, Action: entities.ActionDelete, Username: "bob"},
+			}},
+			want: []models.Event{
+				{ID: 11, Position: "700-11", Type: "address.deleted", EntityID: 3, ContactID: 7},
+				{ID: 12, Position: "700-12", Type: "contact.deleted", EntityID: 7, ContactID: 7},
+			},
+			last: entities.Position{TransactionID: 700, Seq: 12},
+		},
+		{
+			name: "written contact no longer readable",
+			log: changeLog{changes: []entities.Change{
+				{Seq: 5, TransactionID: 650, Entity: entities.EntityContact, EntityID: 7, ContactID: 7, Action: entities.ActionUpdate, Username: "alice"},
+			}},
+			want: []models.Event{
+				{ID: 5, Position: "650-5", Type: "contact.deleted", EntityID: 7, ContactID: 7},
+			},
+			last: entities.Position{TransactionID: 650, Seq: 5},
+		},
+		{
+			name: "ordered by transaction before sequence",
+			log: changeLog{changes: []entities.Change{
+				{Seq: 9, TransactionID: 640, Entity: entities.EntityAddress, EntityID: 4, ContactID: 8, Action: entities.ActionDelete, Username: "bob"},
+				{Seq: 8, TransactionID: 660, Entity: entities.EntityAddress, EntityID: 5, ContactID: 8, Action: entities.ActionDelete, Username: "bob"},
+			}},
+			after: entities.Position{TransactionID: 640, Seq: 9},
+			want: []models.Event{
+				{ID: 8, Position: "660-8", Type: "address.deleted", EntityID: 5, ContactID: 8},
+			},
+			last: entities.Position{TransactionID: 660, Seq: 8},
+		},
+		{
+			name:  "nothing new",
+			after: entities.Position{TransactionID: 700, Seq: 12},
+			last:  entities.Position{TransactionID: 700, Seq: 12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &syncUsecaseImpl{changeRepository: tt.log}
+			events, last, err := usecase.Events(context.Background(), "bob", tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("events = %+v, want %+v", events, tt.want)
+			}
+			if last != tt.last {
+				t.Errorf("last = %+v, want %+v", last, tt.last)
+			}
+		})
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    entities.Position
+		wantErr bool
+	}{
+		{value: "700-12", want: entities.Position{TransactionID: 700, Seq: 12}},
+		{value: "0-42", want: entities.Position{Seq: 42}},
+		{value: "42", wantErr: true},
+		{value: "-1-2", wantErr: true},
+		{value: "1--2", wantErr: true},
+		{value: "a-2", wantErr: true},
+		{value: "1-", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parsePosition(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePosition(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePosition(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+			if !tt.wantErr && formatPosition(got) != tt.value {
+				t.Errorf("formatPosition(%+v) = %q, want %q", got, formatPosition(got), tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"golang-contact-management-restful-api/modules/sync/models"
+	"time"
+)
+
+type SyncUsecase interface {
+	Sync(ctx context.Context, username string, query models.SyncQuery) (models.SyncResponse, error)
+	Prune(ctx context.Context) error
+
+	ResumePoint(ctx context.Context, lastEventID string) (entities.Position, error)
+	Events(ctx context.Context, username string, after entities.Position) ([]models.Event, entities.Position, error)
+	Stream(ctx context.Context, username string, after entities.Position, send func(events []models.Event) error, heartbeat func() error) error
+	Watch(ctx context.Context, interval time.Duration)
+}
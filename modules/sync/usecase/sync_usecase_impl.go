@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"golang-contact-management-restful-api/internal/pubsub"
+	addressUsecase "golang-contact-management-restful-api/modules/address/usecase"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	"golang-contact-management-restful-api/modules/sync/domain"
+	"golang-contact-management-restful-api/modules/sync/entities"
+	"golang-contact-management-restful-api/modules/sync/models"
+	"golang-contact-management-restful-api/modules/sync/repository"
+	"time"
+)
+
+type syncUsecaseImpl struct {
+	changeRepository repository.ChangeRepository
+	broker           pubsub.Broker
+	retention        time.Duration
+}
+
+// NewSyncUsecase returns a usecase keeping tombstones for retention. Tokens
+// issued longer ago than that are rejected, as deletions they have not seen
+// may already be pruned. Event streams wait for new changes on broker.
+func NewSyncUsecase(changeRepository repository.ChangeRepository, broker pubsub.Broker, retention time.Duration) SyncUsecase {
+	return &syncUsecaseImpl{
+		changeRepository: changeRepository,
+		broker:           broker,
+		retention:        retention,
+	}
+}
+
+type syncToken struct {
+	TransactionID int64 `json:"x"`
+	Seq           int64 `json:"s"`
+	IssuedAt      int64 `json:"t"`
+}
+
+func (usecase *syncUsecaseImpl) Sync(ctx context.Context, username string, query models.SyncQuery) (models.SyncResponse, error) {
+	if query.Limit < 1 {
+		query.Limit = models.DefaultBatchSize
+	} else if query.Limit > models.MaxBatchSize {
+		query.Limit = models.MaxBatchSize
+	}
+
+	var position entities.Position
+	if query.Since != "" {
+		token, err := decodeToken(query.Since)
+		if err != nil {
+			return models.SyncResponse{}, err
+		}
+		if time.Since(time.Unix(token.IssuedAt, 0)) > usecase.retention {
+			return models.SyncResponse{}, domain.ErrTokenExpired
+		}
+		position = entities.Position{TransactionID: token.TransactionID, Seq: token.Seq}
+	}
+
+	changes, err := usecase.changeRepository.FindSince(ctx, username, position, query.Limit+1)
+	if err != nil {
+		return models.SyncResponse{}, err
+	}
+
+	hasMore := len(changes) > query.Limit
+	if hasMore {
+		changes = changes[:query.Limit]
+	}
+	if len(changes) > 0 {
+		position = changes[len(changes)-1].Position()
+	}
+
+	// Only the last change to each entity in the batch matters.
+	latest := map[string]map[int]string{
+		entities.EntityContact: {},
+		entities.EntityAddress: {},
+	}
+	var order []entities.Change
+	for _, change := range changes {
+		if _, seen := latest[change.Entity][change.EntityID]; !seen {
+			order = append(order, change)
+		}
+		latest[change.Entity][change.EntityID] = change.Action
+	}
+
+	var contactIDs, addressIDs []int
+	for _, change := range order {
+		if latest[change.Entity][change.EntityID] == entities.ActionDelete {
+			continue
+		}
+		if change.Entity == entities.EntityContact {
+			contactIDs = append(contactIDs, change.EntityID)
+		} else {
+			addressIDs = append(addressIDs, change.EntityID)
+		}
+	}
+
+	contacts, err := usecase.changeRepository.FindContacts(ctx, username, contactIDs)
+	if err != nil {
+		return models.SyncResponse{}, err
+	}
+	addresses, err := usecase.changeRepository.FindAddresses(ctx, username, addressIDs)
+	if err != nil {
+		return models.SyncResponse{}, err
+	}
+
+	response := models.SyncResponse{
+		Contacts:  make([]contactModels.ContactResponse, 0, len(contacts)),
+		Addresses: make([]models.SyncAddress, 0, len(addresses)),
+		Deleted: models.SyncDeleted{
+			Contacts:  []int{},
+			Addresses: []int{},
+		},
+		HasMore: hasMore,
+	}
+
+	// A written entity the user can no longer read was deleted or moved
+	// out of reach since, which the client treats like a deletion.
+	found := map[string]map[int]bool{
+		entities.EntityContact: {},
+		entities.EntityAddress: {},
+	}
+	for _, contact := range contacts {
+		found[entities.EntityContact][contact.ID] = true
+		response.Contacts = append(response.Contacts, contactUsecase.ToContactResponse(username, contact))
+	}
+	for _, address := range addresses {
+		found[entities.EntityAddress][address.ID] = true
+		response.Addresses = append(response.Addresses, models.SyncAddress{
+			ContactID:       address.ContactID,
+			AddressResponse: addressUsecase.ToAddressResponse(address),
+		})
+	}
+
+	for _, change := range order {
+		if found[change.Entity][change.EntityID] {
+			continue
+		}
+		if change.Entity == entities.EntityContact {
+			response.Deleted.Contacts = append(response.Deleted.Contacts, change.EntityID)
+		} else {
+			response.Deleted.Addresses = append(response.Deleted.Addresses, change.EntityID)
+		}
+	}
+
+	response.NextToken, err = encodeToken(syncToken{
+		TransactionID: position.TransactionID,
+		Seq:           position.Seq,
+		IssuedAt:      time.Now().Unix(),
+	})
+	if err != nil {
+		return models.SyncResponse{}, err
+	}
+
+	return response, nil
+}
+
+// Prune drops superseded changes and the tombstones older than the
+// retention period.
+func (usecase *syncUsecaseImpl) Prune(ctx context.Context) error {
+	return usecase.changeRepository.Prune(ctx, time.Now().Add(-usecase.retention))
+}
+
+func encodeToken(token syncToken) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func decodeToken(value string) (syncToken, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return syncToken{}, domain.ErrInvalidToken
+	}
+
+	var token syncToken
+	if err := json.Unmarshal(payload, &token); err != nil || token.TransactionID < 0 || token.Seq < 0 || token.IssuedAt <= 0 {
+		return syncToken{}, domain.ErrInvalidToken
+	}
+	return token, nil
+}
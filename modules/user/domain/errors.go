@@ -6,4 +6,5 @@ var (
 	ErrUsernameAlreadyExists = errors.New("username already exists")
 	ErrUserNotFound          = errors.New("user not found")
 	ErrInvalidCredentials    = errors.New("username or password is wrong")
+	ErrDuplicateContactEmail = errors.New("some contacts share the same email, merge or change them before enabling unique emails")
 )
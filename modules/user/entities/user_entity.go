@@ -1,10 +1,20 @@
 package entities
 
+import "time"
+
 type User struct {
 	Username string `json:"username" gorm:"column:username;primaryKey;size:255;not null"`
 	Password string `json:"-" gorm:"column:password;size:255;not null"`
 	Name     string `json:"name" gorm:"column:name;size:255;not null"`
 	Token    string `json:"token" gorm:"column:token;size:255"`
+
+	DefaultRegion string `json:"default_region" gorm:"column:default_region;size:2"`
+
+	UniqueContactEmails  bool `json:"unique_contact_emails" gorm:"column:unique_contact_emails;not null;default:false"`
+	CanonicalGmailEmails bool `json:"canonical_gmail_emails" gorm:"column:canonical_gmail_emails;not null;default:false"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;not null;default:now();autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;not null;default:now();autoUpdateTime"`
 }
 
 func (User) TableName() string {
@@ -74,6 +74,9 @@ func (handler *userHandlerHttp) UpdateCurrent(ctx *fiber.Ctx) error {
 
 	response, err := handler.usecase.UpdateCurrent(ctx.Context(), username, request)
 	if err != nil {
+		if errors.Is(err, domain.ErrDuplicateContactEmail) {
+			return ctx.Status(fiber.StatusConflict).JSON(http.ErrorResponse{Errors: err.Error()})
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(http.ErrorResponse{
 			Errors: err.Error(),
 		})
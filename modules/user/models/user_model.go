@@ -1,9 +1,13 @@
 package models
 
+import "time"
+
 type UserRegisterRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=255"`
 	Password string `json:"password" validate:"required,min=8,max=255"`
 	Name     string `json:"name"     validate:"required,min=3,max=100"`
+
+	DefaultRegion string `json:"default_region" validate:"omitempty,iso3166_1_alpha2"`
 }
 
 type UserLoginRequest struct {
@@ -14,10 +18,22 @@ type UserLoginRequest struct {
 type UserUpdateRequest struct {
 	Name     string `json:"name"     validate:"omitempty,min=3,max=100"`
 	Password string `json:"password" validate:"omitempty,min=8,max=255"`
+
+	DefaultRegion string `json:"default_region" validate:"omitempty,iso3166_1_alpha2"`
+
+	UniqueContactEmails  *bool `json:"unique_contact_emails"`
+	CanonicalGmailEmails *bool `json:"canonical_gmail_emails"`
 }
 type UserResponse struct {
-	Username string `json:"username"`
-	Name     string `json:"name"`
+	Username      string `json:"username"`
+	Name          string `json:"name"`
+	DefaultRegion string `json:"default_region,omitempty"`
+
+	UniqueContactEmails  bool `json:"unique_contact_emails"`
+	CanonicalGmailEmails bool `json:"canonical_gmail_emails"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type LoginResponse struct {
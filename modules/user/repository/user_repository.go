@@ -12,4 +12,6 @@ type UserRepository interface {
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	FindByToken(ctx context.Context, token string) (entities.User, error)
 	ClearTokenByUsername(ctx context.Context, username string) error
+	RecordLogin(ctx context.Context, username string, succeeded bool) error
+	UpdateContactEmailSettings(ctx context.Context, username string, unique bool, canonicalGmail bool) error
 }
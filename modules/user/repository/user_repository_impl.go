@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"golang-contact-management-restful-api/internal/database"
+	"golang-contact-management-restful-api/internal/outbox"
 	"golang-contact-management-restful-api/modules/user/domain"
 	"golang-contact-management-restful-api/modules/user/entities"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userRepositoryImpl struct {
@@ -18,8 +21,23 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (repository *userRepositoryImpl) Save(ctx context.Context, user entities.User) (entities.User, error) {
-	if err := repository.DB.WithContext(ctx).Create(&user).Error; err != nil {
-		return entities.User{}, nil
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		event, err := outbox.NewEvent(outbox.UserRegistered, user.Username, user.Username, nil, map[string]any{
+			"username":   user.Username,
+			"name":       user.Name,
+			"created_at": user.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return outbox.Publish(tx, event)
+	})
+	if err != nil {
+		return entities.User{}, err
 	}
 	return user, nil
 }
@@ -37,24 +55,51 @@ func (repository *userRepositoryImpl) Update(ctx context.Context, username strin
 		updateMap["token"] = user.Token
 	}
 
+	if user.DefaultRegion != "" {
+		updateMap["default_region"] = user.DefaultRegion
+	}
+
 	if len(updateMap) == 0 {
 		return entities.User{}, nil
 	}
 
-	result := repository.DB.WithContext(ctx).
-		Model(&entities.User{}).
-		Where("username = ?", username).
-		Updates(updateMap)
+	var updated entities.User
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entities.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&current, "username = ?", username).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
 
-	if result.Error != nil {
-		return entities.User{}, result.Error
-	}
-	if result.RowsAffected == 0 {
-		return entities.User{}, domain.ErrUserNotFound
-	}
+		if err := tx.Model(&entities.User{}).Where("username = ?", username).Updates(updateMap).Error; err != nil {
+			return err
+		}
 
-	var updated entities.User
-	if err := repository.DB.WithContext(ctx).Take(&updated, "username = ?", username).Error; err != nil {
+		if err := tx.Take(&updated, "username = ?", username).Error; err != nil {
+			return err
+		}
+
+		// A new token replaces the one the user had, which stops working.
+		var events []outbox.Event
+		if user.Token != "" && current.Token != "" && current.Token != user.Token {
+			event, err := userEvent(outbox.UserTokenRevoked, username)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		if user.Password != "" {
+			event, err := userEvent(outbox.UserPasswordChanged, username)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return outbox.Publish(tx, events...)
+	})
+	if err != nil {
 		return entities.User{}, err
 	}
 
@@ -94,15 +139,72 @@ func (repository *userRepositoryImpl) FindByToken(ctx context.Context, token str
 }
 
 func (repository *userRepositoryImpl) ClearTokenByUsername(ctx context.Context, username string) error {
-	result := repository.DB.WithContext(ctx).Model(&entities.User{}).Where("username = ?", username).Update("token", "")
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.User{}).Where("username = ?", username).Update("token", "")
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrUserNotFound
+		}
+
+		event, err := userEvent(outbox.UserLoggedOut, username)
+		if err != nil {
+			return err
+		}
+		return outbox.Publish(tx, event)
+	})
+}
 
-	if result.Error != nil {
-		return result.Error
+// RecordLogin publishes a login attempt for username, which need not be
+// the name of an existing user.
+func (repository *userRepositoryImpl) RecordLogin(ctx context.Context, username string, succeeded bool) error {
+	eventType := outbox.UserLoginFailed
+	if succeeded {
+		eventType = outbox.UserLoggedIn
 	}
 
-	if result.RowsAffected == 0 {
-		return domain.ErrUserNotFound
+	event, err := userEvent(eventType, username)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return outbox.Publish(tx, event)
+	})
+}
+
+// UpdateContactEmailSettings stores the contact email settings and re-keys
+// the user's contacts in the same transaction, so enabling uniqueness fails
+// as a whole when duplicates already exist.
+func (repository *userRepositoryImpl) UpdateContactEmailSettings(ctx context.Context, username string, unique bool, canonicalGmail bool) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.User{}).Where("username = ?", username).Updates(map[string]any{
+			"unique_contact_emails":  unique,
+			"canonical_gmail_emails": canonicalGmail,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrUserNotFound
+		}
+
+		err := tx.Exec(`UPDATE "contacts" SET email_key = CASE WHEN ? THEN contacts_email_key(email, ?) END WHERE username = ? AND organization_id IS NULL`,
+			unique, canonicalGmail, username).Error
+		if database.IsUniqueViolation(err, "idx_contacts_username_email_key") {
+			return domain.ErrDuplicateContactEmail
+		}
+		return err
+	})
+}
+
+// userEvent returns an event about the user, which carries no more than
+// the username so that credentials never reach the outbox.
+func userEvent(eventType string, username string) (outbox.Event, error) {
+	return outbox.NewEvent(eventType, username, username, nil, map[string]any{
+		"username": username,
+	})
 }
@@ -49,6 +49,8 @@ func (usecase *userUsecaseImpl) Register(ctx context.Context, req models.UserReg
 		Username: req.Username,
 		Password: string(hashedPassword),
 		Name:     req.Name,
+
+		DefaultRegion: req.DefaultRegion,
 	}
 
 	saved, err := usecase.userRepository.Save(ctx, user)
@@ -57,8 +59,15 @@ func (usecase *userUsecaseImpl) Register(ctx context.Context, req models.UserReg
 	}
 
 	return models.UserResponse{
-		Username: saved.Username,
-		Name:     saved.Name,
+		Username:      saved.Username,
+		Name:          saved.Name,
+		DefaultRegion: saved.DefaultRegion,
+
+		UniqueContactEmails:  saved.UniqueContactEmails,
+		CanonicalGmailEmails: saved.CanonicalGmailEmails,
+
+		CreatedAt: saved.CreatedAt,
+		UpdatedAt: saved.UpdatedAt,
 	}, nil
 
 }
@@ -71,13 +80,13 @@ func (usecase *userUsecaseImpl) Login(ctx context.Context, req models.UserLoginR
 	user, err := usecase.userRepository.FindByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
-			return models.LoginResponse{}, domain.ErrInvalidCredentials
+			return models.LoginResponse{}, usecase.loginFailed(ctx, req.Username)
 		}
 		return models.LoginResponse{}, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return models.LoginResponse{}, domain.ErrInvalidCredentials
+		return models.LoginResponse{}, usecase.loginFailed(ctx, req.Username)
 	}
 
 	token := uuid.NewString()
@@ -89,12 +98,24 @@ func (usecase *userUsecaseImpl) Login(ctx context.Context, req models.UserLoginR
 		return models.LoginResponse{}, err
 	}
 
+	if err := usecase.userRepository.RecordLogin(ctx, user.Username, true); err != nil {
+		return models.LoginResponse{}, err
+	}
+
 	return models.LoginResponse{
 		Token: token,
 	}, nil
 
 }
 
+// loginFailed records a failed login and returns the error reported for it.
+func (usecase *userUsecaseImpl) loginFailed(ctx context.Context, username string) error {
+	if err := usecase.userRepository.RecordLogin(ctx, username, false); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
 func (usecase *userUsecaseImpl) UpdateCurrent(ctx context.Context, username string, req models.UserUpdateRequest) (models.UserResponse, error) {
 	if err := usecase.validator.Struct(req); err != nil {
 		return models.UserResponse{}, err
@@ -105,6 +126,10 @@ func (usecase *userUsecaseImpl) UpdateCurrent(ctx context.Context, username stri
 		upd.Name = req.Name
 	}
 
+	if req.DefaultRegion != "" {
+		upd.DefaultRegion = req.DefaultRegion
+	}
+
 	if req.Password != "" {
 		hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -113,19 +138,55 @@ func (usecase *userUsecaseImpl) UpdateCurrent(ctx context.Context, username stri
 		upd.Password = string(hashed)
 	}
 
+	if req.UniqueContactEmails != nil || req.CanonicalGmailEmails != nil {
+		if err := usecase.updateContactEmailSettings(ctx, username, req); err != nil {
+			return models.UserResponse{}, err
+		}
+	}
+
 	updated, err := usecase.userRepository.Update(ctx, username, upd)
 
 	if err != nil {
 		return models.UserResponse{}, err
 	}
 
+	if updated.Username == "" {
+		if updated, err = usecase.userRepository.FindByUsername(ctx, username); err != nil {
+			return models.UserResponse{}, err
+		}
+	}
+
 	return models.UserResponse{
-		Username: updated.Username,
-		Name:     updated.Name,
+		Username:      updated.Username,
+		Name:          updated.Name,
+		DefaultRegion: updated.DefaultRegion,
+
+		UniqueContactEmails:  updated.UniqueContactEmails,
+		CanonicalGmailEmails: updated.CanonicalGmailEmails,
+
+		CreatedAt: updated.CreatedAt,
+		UpdatedAt: updated.UpdatedAt,
 	}, nil
 
 }
 
+func (usecase *userUsecaseImpl) updateContactEmailSettings(ctx context.Context, username string, req models.UserUpdateRequest) error {
+	user, err := usecase.userRepository.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	unique, canonicalGmail := user.UniqueContactEmails, user.CanonicalGmailEmails
+	if req.UniqueContactEmails != nil {
+		unique = *req.UniqueContactEmails
+	}
+	if req.CanonicalGmailEmails != nil {
+		canonicalGmail = *req.CanonicalGmailEmails
+	}
+
+	return usecase.userRepository.UpdateContactEmailSettings(ctx, username, unique, canonicalGmail)
+}
+
 func (usecase *userUsecaseImpl) GetCurrent(ctx context.Context, username string) (models.UserResponse, error) {
 	user, err := usecase.userRepository.FindByUsername(ctx, username)
 
@@ -134,8 +195,15 @@ func (usecase *userUsecaseImpl) GetCurrent(ctx context.Context, username string)
 	}
 
 	return models.UserResponse{
-		Username: user.Username,
-		Name:     user.Name,
+		Username:      user.Username,
+		Name:          user.Name,
+		DefaultRegion: user.DefaultRegion,
+
+		UniqueContactEmails:  user.UniqueContactEmails,
+		CanonicalGmailEmails: user.CanonicalGmailEmails,
+
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
 	}, nil
 }
 
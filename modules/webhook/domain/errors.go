@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookForbidden = errors.New("only owners and admins can manage the webhooks of an organization")
+
+	ErrInvalidDeliveryStatus     = errors.New("status must be pending, succeeded or failed")
+	ErrWebhookTargetForbidden    = errors.New("webhook url must point to a public address")
+	ErrWebhookTargetUnresolvable = errors.New("webhook url host could not be resolved")
+)
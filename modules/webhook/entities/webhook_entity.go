@@ -0,0 +1,67 @@
+package entities
+
+import "time"
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook subscribes an endpoint to the events of a workspace.
+// FailureCount is the number of attempts that failed since the last
+// success.
+type Webhook struct {
+	ID             int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	URL            string    `json:"url" gorm:"column:url;size:2048;not null"`
+	EventTypes     []string  `json:"event_types" gorm:"column:event_types;type:jsonb;serializer:json;not null"`
+	Secret         string    `json:"-" gorm:"column:secret;size:100;not null"`
+	Enabled        bool      `json:"enabled" gorm:"column:enabled;not null;default:true"`
+	DisabledReason *string   `json:"disabled_reason,omitempty" gorm:"column:disabled_reason;size:255"`
+	FailureCount   int       `json:"failure_count" gorm:"column:failure_count;not null;default:0"`
+	Username       string    `json:"username" gorm:"column:username;size:255;not null;index"`
+	OrganizationID *int      `json:"organization_id,omitempty" gorm:"column:organization_id;index"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery is an event queued for a webhook. The table is the outbox
+// drained by the delivery worker: pending deliveries are attempted once
+// NextAttemptAt has passed. EventID is the id of the event in the outbox of
+// the application, and is delivered once per webhook, besides redeliveries.
+type WebhookDelivery struct {
+	ID            int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	WebhookID     int        `json:"webhook_id" gorm:"column:webhook_id;not null;index;uniqueIndex:idx_webhook_deliveries_event,where:redelivery_of IS NULL"`
+	EventID       int64      `json:"event_id" gorm:"column:event_id;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType     string     `json:"event_type" gorm:"column:event_type;size:32;not null"`
+	Payload       string     `json:"payload" gorm:"column:payload;type:jsonb;not null"`
+	Status        string     `json:"status" gorm:"column:status;size:16;not null"`
+	Attempts      int        `json:"attempts" gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"column:next_attempt_at;not null;index"`
+	LastAttemptAt *time.Time `json:"last_attempt_at" gorm:"column:last_attempt_at"`
+	RedeliveryOf  *int       `json:"redelivery_of,omitempty" gorm:"column:redelivery_of"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookAttempt logs one request made for a delivery.
+type WebhookAttempt struct {
+	ID         int       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	DeliveryID int       `json:"delivery_id" gorm:"column:delivery_id;not null;index"`
+	Attempt    int       `json:"attempt" gorm:"column:attempt;not null"`
+	StatusCode *int      `json:"status_code" gorm:"column:status_code"`
+	Error      *string   `json:"error" gorm:"column:error;size:1000"`
+	DurationMS int       `json:"duration_ms" gorm:"column:duration_ms;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (WebhookAttempt) TableName() string {
+	return "webhook_attempts"
+}
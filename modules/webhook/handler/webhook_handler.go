@@ -0,0 +1,14 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type WebhookHandler interface {
+	Create(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	FindByID(ctx *fiber.Ctx) error
+	UpdateByID(ctx *fiber.Ctx) error
+	DeleteByID(ctx *fiber.Ctx) error
+	Deliveries(ctx *fiber.Ctx) error
+	Delivery(ctx *fiber.Ctx) error
+	Redeliver(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// MaxAttempts is how often a delivery is tried before it fails.
+	MaxAttempts = 10
+	// DisableAfterFailures is how many attempts in a row may fail before
+	// the webhook is disabled.
+	DisableAfterFailures = 20
+
+	// EventTypeAll subscribes a webhook to every event type.
+	EventTypeAll = "*"
+)
+
+type WebhookCreateRequest struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=* contact.created contact.updated contact.deleted address.created address.updated address.deleted"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=100"`
+}
+
+// WebhookUpdateRequest changes the set fields of a webhook. Enabling a
+// disabled webhook resumes its pending deliveries.
+type WebhookUpdateRequest struct {
+	URL        string   `json:"url" validate:"omitempty,http_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"omitempty,min=1,dive,oneof=* contact.created contact.updated contact.deleted address.created address.updated address.deleted"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=100"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// WebhookResponse describes a webhook. The secret is only returned when it
+// is set.
+type WebhookResponse struct {
+	ID             int       `json:"id"`
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"event_types"`
+	Secret         string    `json:"secret,omitempty"`
+	Enabled        bool      `json:"enabled"`
+	DisabledReason string    `json:"disabled_reason,omitempty"`
+	FailureCount   int       `json:"failure_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type DeliveryQuery struct {
+	Status string
+	Page   int
+	Size   int
+}
+
+type DeliveryResponse struct {
+	ID            int               `json:"id"`
+	EventID       int64             `json:"event_id"`
+	EventType     string            `json:"event_type"`
+	Status        string            `json:"status"`
+	Attempts      int               `json:"attempts"`
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"`
+	LastAttemptAt *time.Time        `json:"last_attempt_at,omitempty"`
+	RedeliveryOf  int               `json:"redelivery_of,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Payload       json.RawMessage   `json:"payload,omitempty"`
+	Log           []AttemptResponse `json:"log,omitempty"`
+}
+
+type AttemptResponse struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
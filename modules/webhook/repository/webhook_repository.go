@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/webhook/entities"
+	"golang-contact-management-restful-api/modules/webhook/models"
+	"time"
+)
+
+type WebhookRepository interface {
+	Save(ctx context.Context, username string, webhook entities.Webhook) (entities.Webhook, error)
+	UpdateByID(ctx context.Context, username string, id int, webhook entities.Webhook) (entities.Webhook, error)
+	FindByID(ctx context.Context, username string, id int) (entities.Webhook, error)
+	FindAll(ctx context.Context, username string) ([]entities.Webhook, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+
+	FindDeliveries(ctx context.Context, username string, webhookID int, query models.DeliveryQuery) ([]entities.WebhookDelivery, int, error)
+	FindDelivery(ctx context.Context, username string, webhookID int, deliveryID int) (entities.WebhookDelivery, []entities.WebhookAttempt, error)
+	Redeliver(ctx context.Context, username string, webhookID int, deliveryID int) (entities.WebhookDelivery, error)
+
+	// The methods below serve the delivery worker and are not scoped to a
+	// user.
+	FindSubscribed(ctx context.Context, username string, organizationID *int, createdBefore time.Time) ([]entities.Webhook, error)
+	Enqueue(ctx context.Context, deliveries []entities.WebhookDelivery) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, map[int]entities.Webhook, error)
+	RecordAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookAttempt) error
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"golang-contact-management-restful-api/internal/workspace"
+	"golang-contact-management-restful-api/modules/webhook/domain"
+	"golang-contact-management-restful-api/modules/webhook/entities"
+	"golang-contact-management-restful-api/modules/webhook/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepositoryImpl{DB: db}
+}
+
+func (repository *webhookRepositoryImpl) Save(ctx context.Context, username string, webhook entities.Webhook) (entities.Webhook, error) {
+	current := workspace.FromContext(ctx)
+	if !canManage(current) {
+		return entities.Webhook{}, domain.ErrWebhookForbidden
+	}
+
+	webhook.Username = username
+	if !current.IsPersonal() {
+		webhook.OrganizationID = &current.OrganizationID
+	}
+
+	if err := repository.DB.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return entities.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// UpdateByID writes the URL, event types, secret, enabled flag and failure
+// state of webhook.
+func (repository *webhookRepositoryImpl) UpdateByID(ctx context.Context, username string, id int, webhook entities.Webhook) (entities.Webhook, error) {
+	if !canManage(workspace.FromContext(ctx)) {
+		return entities.Webhook{}, domain.ErrWebhookForbidden
+	}
+
+	owned, args := workspace.Owned(ctx, "webhooks", username)
+	result := repository.DB.WithContext(ctx).Model(&entities.Webhook{}).
+		Where("webhooks.id = ?", id).Where(owned, args...).
+		Select("url", "event_types", "secret", "enabled", "failure_count", "disabled_reason").
+		Updates(&webhook)
+	if result.Error != nil {
+		return entities.Webhook{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.Webhook{}, domain.ErrWebhookNotFound
+	}
+
+	return repository.FindByID(ctx, username, id)
+}
+
+func (repository *webhookRepositoryImpl) FindByID(ctx context.Context, username string, id int) (entities.Webhook, error) {
+	if !canManage(workspace.FromContext(ctx)) {
+		return entities.Webhook{}, domain.ErrWebhookForbidden
+	}
+
+	owned, args := workspace.Owned(ctx, "webhooks", username)
+	var webhook entities.Webhook
+	if err := repository.DB.WithContext(ctx).Where("webhooks.id = ?", id).Where(owned, args...).Take(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Webhook{}, domain.ErrWebhookNotFound
+		}
+		return entities.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (repository *webhookRepositoryImpl) FindAll(ctx context.Context, username string) ([]entities.Webhook, error) {
+	if !canManage(workspace.FromContext(ctx)) {
+		return nil, domain.ErrWebhookForbidden
+	}
+
+	owned, args := workspace.Owned(ctx, "webhooks", username)
+	var webhooks []entities.Webhook
+	if err := repository.DB.WithContext(ctx).Where(owned, args...).Order("webhooks.id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// DeleteByID deletes the webhook with its deliveries and their log.
+func (repository *webhookRepositoryImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	if !canManage(workspace.FromContext(ctx)) {
+		return domain.ErrWebhookForbidden
+	}
+
+	owned, args := workspace.Owned(ctx, "webhooks", username)
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("webhooks.id = ?", id).Where(owned, args...).Delete(&entities.Webhook{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrWebhookNotFound
+		}
+
+		if err := tx.Exec(`DELETE FROM "webhook_attempts" WHERE delivery_id IN (SELECT id FROM "webhook_deliveries" WHERE webhook_id = ?)`, id).Error; err != nil {
+			return err
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&entities.WebhookDelivery{}).Error
+	})
+}
+
+func (repository *webhookRepositoryImpl) FindDeliveries(ctx context.Context, username string, webhookID int, query models.DeliveryQuery) ([]entities.WebhookDelivery, int, error) {
+	if _, err := repository.FindByID(ctx, username, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	db := repository.DB.WithContext(ctx).Model(&entities.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []entities.WebhookDelivery
+	if err := db.Omit("payload").Order("id DESC").
+		Offset((query.Page - 1) * query.Size).Limit(query.Size).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, int(total), nil
+}
+
+func (repository *webhookRepositoryImpl) FindDelivery(ctx context.Context, username string, webhookID int, deliveryID int) (entities.WebhookDelivery, []entities.WebhookAttempt, error) {
+	if _, err := repository.FindByID(ctx, username, webhookID); err != nil {
+		return entities.WebhookDelivery{}, nil, err
+	}
+
+	var delivery entities.WebhookDelivery
+	if err := repository.DB.WithContext(ctx).Take(&delivery, "id = ? AND webhook_id = ?", deliveryID, webhookID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.WebhookDelivery{}, nil, domain.ErrDeliveryNotFound
+		}
+		return entities.WebhookDelivery{}, nil, err
+	}
+
+	var attempts []entities.WebhookAttempt
+	if err := repository.DB.WithContext(ctx).Where("delivery_id = ?", deliveryID).
+		Order("attempt ASC").Find(&attempts).Error; err != nil {
+		return entities.WebhookDelivery{}, nil, err
+	}
+
+	return delivery, attempts, nil
+}
+
+// Redeliver queues the event of a delivery again as a new delivery, due
+// immediately.
+func (repository *webhookRepositoryImpl) Redeliver(ctx context.Context, username string, webhookID int, deliveryID int) (entities.WebhookDelivery, error) {
+	original, _, err := repository.FindDelivery(ctx, username, webhookID, deliveryID)
+	if err != nil {
+		return entities.WebhookDelivery{}, err
+	}
+
+	delivery := entities.WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        entities.DeliveryPending,
+		NextAttemptAt: time.Now(),
+		RedeliveryOf:  &original.ID,
+	}
+	if err := repository.DB.WithContext(ctx).Create(&delivery).Error; err != nil {
+		return entities.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// FindSubscribed returns the deliverable webhooks of a workspace, the
+// personal one of username or the organization's, that were created by the
+// given time.
+func (repository *webhookRepositoryImpl) FindSubscribed(ctx context.Context, username string, organizationID *int, createdBefore time.Time) ([]entities.Webhook, error) {
+	db := repository.DB.WithContext(ctx).Select(`"webhooks".*`).Joins(membership).Where(deliverable).
+		Where("webhooks.created_at <= ?", createdBefore)
+	if organizationID != nil {
+		db = db.Where("webhooks.organization_id = ?", *organizationID)
+	} else {
+		db = db.Where("webhooks.organization_id IS NULL AND webhooks.username = ?", username)
+	}
+
+	var webhooks []entities.Webhook
+	if err := db.Order("webhooks.id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Enqueue stores new deliveries. Deliveries of an event a webhook already
+// has are skipped, so that an event handed over again is not delivered
+// twice.
+func (repository *webhookRepositoryImpl) Enqueue(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return repository.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ClaimDue returns up to limit pending deliveries of deliverable webhooks
+// that are due, with their webhooks. They are not due again for lease, so that
+// other workers leave them alone while they are attempted.
+func (repository *webhookRepositoryImpl) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, map[int]entities.Webhook, error) {
+	var deliveries []entities.WebhookDelivery
+	err := repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ? AND next_attempt_at <= now()", entities.DeliveryPending).
+			Where("webhook_id IN (?)", tx.Model(&entities.Webhook{}).Select("webhooks.id").Joins(membership).Where(deliverable)).
+			Order("next_attempt_at ASC").Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]int, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&entities.WebhookDelivery{}).Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", time.Now().Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, nil, err
+	}
+
+	webhookIDs := make([]int, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+	}
+	var webhooks []entities.Webhook
+	if err := repository.DB.WithContext(ctx).Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int]entities.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		byID[webhook.ID] = webhook
+	}
+	return deliveries, byID, nil
+}
+
+// RecordAttempt logs attempt and stores the resulting state of delivery.
+// The webhook's failures are counted, and it is disabled once too many
+// attempts failed in a row.
+func (repository *webhookRepositoryImpl) RecordAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookAttempt) error {
+	return repository.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entities.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]any{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_attempt_at": delivery.LastAttemptAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		if delivery.Status == entities.DeliverySucceeded {
+			return tx.Model(&entities.Webhook{}).Where("id = ?", delivery.WebhookID).
+				UpdateColumn("failure_count", 0).Error
+		}
+
+		return tx.Exec(`UPDATE "webhooks" SET
+			"failure_count" = "failure_count" + 1,
+			"enabled" = "enabled" AND "failure_count" + 1 < ?,
+			"disabled_reason" = CASE WHEN "enabled" AND "failure_count" + 1 >= ? THEN ? ELSE "disabled_reason" END
+			WHERE "id" = ?`,
+			models.DisableAfterFailures, models.DisableAfterFailures,
+			"disabled after too many failed deliveries", delivery.WebhookID).Error
+	})
+}
+
+// membership joins webhooks with the membership of their owner in the
+// organization of organization webhooks.
+const membership = `LEFT JOIN "organization_members" ON "organization_members"."organization_id" = "webhooks"."organization_id" AND "organization_members"."username" = "webhooks"."username"`
+
+// deliverable selects the enabled webhooks, joined by membership, that may
+// still receive events: personal ones, and those of organizations their
+// owner still owns or administers.
+const deliverable = `"webhooks"."enabled" AND ("webhooks"."organization_id" IS NULL OR "organization_members"."role" IN ('owner', 'admin'))`
+
+// canManage reports whether the user may manage the webhooks of the
+// workspace: their own, or those of an organization they own or
+// administer.
+func canManage(current workspace.Workspace) bool {
+	return current.IsPersonal() || current.Role == workspace.RoleOwner || current.Role == workspace.RoleAdmin
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/modules/webhook/domain"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// nonPublic lists the special-purpose ranges, besides those recognised by
+// netip.Addr, that webhooks may not be delivered to.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// isPublic reports whether addr is a globally routable unicast address.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublic {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTarget makes sure a webhook URL is an absolute HTTP(S) URL whose
+// host only resolves to public addresses. The worker checks the address
+// again when it connects, as DNS may change in between.
+func checkTarget(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return domain.ErrWebhookTargetForbidden
+	}
+
+	if addr, err := netip.ParseAddr(target.Hostname()); err == nil {
+		if !isPublic(addr) {
+			return domain.ErrWebhookTargetForbidden
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", target.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return domain.ErrWebhookTargetUnresolvable
+	}
+	for _, addr := range addrs {
+		if !isPublic(addr) {
+			return domain.ErrWebhookTargetForbidden
+		}
+	}
+	return nil
+}
+
+// dialPublic refuses connections to non-public addresses. It runs after
+// the host has been resolved, so names rebinding to internal addresses
+// are caught too.
+func dialPublic(_ string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !isPublic(addrPort.Addr()) {
+		return domain.ErrWebhookTargetForbidden
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"golang-contact-management-restful-api/internal/outbox"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/webhook/models"
+)
+
+type WebhookUsecase interface {
+	Create(ctx context.Context, username string, request models.WebhookCreateRequest) (models.WebhookResponse, error)
+	Update(ctx context.Context, username string, id int, request models.WebhookUpdateRequest) (models.WebhookResponse, error)
+	FindByID(ctx context.Context, username string, id int) (models.WebhookResponse, error)
+	FindAll(ctx context.Context, username string) ([]models.WebhookResponse, error)
+	DeleteByID(ctx context.Context, username string, id int) error
+
+	FindDeliveries(ctx context.Context, username string, webhookID int, query models.DeliveryQuery) ([]models.DeliveryResponse, contactModels.Paging, error)
+	FindDelivery(ctx context.Context, username string, webhookID int, deliveryID int) (models.DeliveryResponse, error)
+	Redeliver(ctx context.Context, username string, webhookID int, deliveryID int) (models.DeliveryResponse, error)
+
+	Enqueue(ctx context.Context, event outbox.Event) error
+	Run(ctx context.Context, onError func(err error))
+}
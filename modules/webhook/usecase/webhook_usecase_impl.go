@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"golang-contact-management-restful-api/internal/transport/http"
+	contactModels "golang-contact-management-restful-api/modules/contact/models"
+	"golang-contact-management-restful-api/modules/webhook/domain"
+	"golang-contact-management-restful-api/modules/webhook/entities"
+	"golang-contact-management-restful-api/modules/webhook/models"
+	"golang-contact-management-restful-api/modules/webhook/repository"
+	"math"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type webhookUsecaseImpl struct {
+	webhookRepository repository.WebhookRepository
+	validator         *validator.Validate
+	// wake tells the delivery worker about new deliveries.
+	wake chan struct{}
+}
+
+// NewWebhookUsecase returns a usecase delivering outbox events, handed to
+// Enqueue, to webhooks.
+func NewWebhookUsecase(webhookRepository repository.WebhookRepository, validator *validator.Validate) WebhookUsecase {
+	return &webhookUsecaseImpl{
+		webhookRepository: webhookRepository,
+		validator:         validator,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// Create subscribes a webhook to the events from now on. Its URL must
+// resolve to public addresses only. A secret is generated unless one is
+// given; it is only returned here and when it is changed.
+func (usecase *webhookUsecaseImpl) Create(ctx context.Context, username string, request models.WebhookCreateRequest) (models.WebhookResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.WebhookResponse{}, err
+	}
+
+	if err := checkTarget(ctx, request.URL); err != nil {
+		return models.WebhookResponse{}, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return models.WebhookResponse{}, err
+		}
+	}
+
+	saved, err := usecase.webhookRepository.Save(ctx, username, entities.Webhook{
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+		Secret:     secret,
+		Enabled:    true,
+	})
+	if err != nil {
+		return models.WebhookResponse{}, err
+	}
+
+	response := toWebhookResponse(saved)
+	response.Secret = saved.Secret
+	return response, nil
+}
+
+func (usecase *webhookUsecaseImpl) Update(ctx context.Context, username string, id int, request models.WebhookUpdateRequest) (models.WebhookResponse, error) {
+	if err := usecase.validator.Struct(request); err != nil {
+		return models.WebhookResponse{}, err
+	}
+
+	webhook, err := usecase.webhookRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.WebhookResponse{}, err
+	}
+
+	if request.URL != "" {
+		if err := checkTarget(ctx, request.URL); err != nil {
+			return models.WebhookResponse{}, err
+		}
+		webhook.URL = request.URL
+	}
+	if len(request.EventTypes) > 0 {
+		webhook.EventTypes = request.EventTypes
+	}
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+	if request.Enabled != nil {
+		if *request.Enabled && !webhook.Enabled {
+			webhook.FailureCount = 0
+			webhook.DisabledReason = nil
+		}
+		webhook.Enabled = *request.Enabled
+	}
+
+	updated, err := usecase.webhookRepository.UpdateByID(ctx, username, id, webhook)
+	if err != nil {
+		return models.WebhookResponse{}, err
+	}
+
+	response := toWebhookResponse(updated)
+	if request.Secret != "" {
+		response.Secret = updated.Secret
+	}
+	return response, nil
+}
+
+func (usecase *webhookUsecaseImpl) FindByID(ctx context.Context, username string, id int) (models.WebhookResponse, error) {
+	webhook, err := usecase.webhookRepository.FindByID(ctx, username, id)
+	if err != nil {
+		return models.WebhookResponse{}, err
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+func (usecase *webhookUsecaseImpl) FindAll(ctx context.Context, username string) ([]models.WebhookResponse, error) {
+	webhooks, err := usecase.webhookRepository.FindAll(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = toWebhookResponse(webhook)
+	}
+	return responses, nil
+}
+
+func (usecase *webhookUsecaseImpl) DeleteByID(ctx context.Context, username string, id int) error {
+	return usecase.webhookRepository.DeleteByID(ctx, username, id)
+}
+
+func (usecase *webhookUsecaseImpl) FindDeliveries(ctx context.Context, username string, webhookID int, query models.DeliveryQuery) ([]models.DeliveryResponse, contactModels.Paging, error) {
+	switch query.Status {
+	case "", entities.DeliveryPending, entities.DeliverySucceeded, entities.DeliveryFailed:
+	default:
+		return nil, contactModels.Paging{}, domain.ErrInvalidDeliveryStatus
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Size < 1 {
+		query.Size = 10
+	} else if query.Size > 100 {
+		query.Size = 100
+	}
+
+	deliveries, total, err := usecase.webhookRepository.FindDeliveries(ctx, username, webhookID, query)
+	if err != nil {
+		return nil, contactModels.Paging{}, err
+	}
+
+	responses := make([]models.DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = toDeliveryResponse(delivery)
+	}
+
+	totalPage := int(math.Ceil(float64(total) / float64(query.Size)))
+	if totalPage == 0 {
+		totalPage = 1
+	}
+
+	return responses, contactModels.Paging{
+		Page:      query.Page,
+		TotalPage: totalPage,
+		TotalItem: total,
+	}, nil
+}
+
+// FindDelivery returns a delivery with its payload and the log of its
+// attempts.
+func (usecase *webhookUsecaseImpl) FindDelivery(ctx context.Context, username string, webhookID int, deliveryID int) (models.DeliveryResponse, error) {
+	delivery, attempts, err := usecase.webhookRepository.FindDelivery(ctx, username, webhookID, deliveryID)
+	if err != nil {
+		return models.DeliveryResponse{}, err
+	}
+
+	response := toDeliveryResponse(delivery)
+	response.Payload = json.RawMessage(delivery.Payload)
+	response.Log = make([]models.AttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		response.Log[i] = models.AttemptResponse{
+			Attempt:    attempt.Attempt,
+			StatusCode: http.PointerToInt(attempt.StatusCode),
+			Error:      http.PointerToString(attempt.Error),
+			DurationMS: attempt.DurationMS,
+			CreatedAt:  attempt.CreatedAt,
+		}
+	}
+	return response, nil
+}
+
+func (usecase *webhookUsecaseImpl) Redeliver(ctx context.Context, username string, webhookID int, deliveryID int) (models.DeliveryResponse, error) {
+	delivery, err := usecase.webhookRepository.Redeliver(ctx, username, webhookID, deliveryID)
+	if err != nil {
+		return models.DeliveryResponse{}, err
+	}
+	return toDeliveryResponse(delivery), nil
+}
+
+func toWebhookResponse(webhook entities.Webhook) models.WebhookResponse {
+	return models.WebhookResponse{
+		ID:             webhook.ID,
+		URL:            webhook.URL,
+		EventTypes:     webhook.EventTypes,
+		Enabled:        webhook.Enabled,
+		DisabledReason: http.PointerToString(webhook.DisabledReason),
+		FailureCount:   webhook.FailureCount,
+		CreatedAt:      webhook.CreatedAt,
+		UpdatedAt:      webhook.UpdatedAt,
+	}
+}
+
+func toDeliveryResponse(delivery entities.WebhookDelivery) models.DeliveryResponse {
+	response := models.DeliveryResponse{
+		ID:            delivery.ID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		LastAttemptAt: delivery.LastAttemptAt,
+		RedeliveryOf:  http.PointerToInt(delivery.RedeliveryOf),
+		CreatedAt:     delivery.CreatedAt,
+	}
+	if delivery.Status == entities.DeliveryPending {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return response
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
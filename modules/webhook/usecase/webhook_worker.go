@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"golang-contact-management-restful-api/internal/outbox"
+	addressEntities "golang-contact-management-restful-api/modules/address/entities"
+	addressUsecase "golang-contact-management-restful-api/modules/address/usecase"
+	contactEntities "golang-contact-management-restful-api/modules/contact/entities"
+	contactUsecase "golang-contact-management-restful-api/modules/contact/usecase"
+	syncModels "golang-contact-management-restful-api/modules/sync/models"
+	"golang-contact-management-restful-api/modules/webhook/entities"
+	"golang-contact-management-restful-api/modules/webhook/models"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// workerInterval is how often the worker looks for due retries.
+	workerInterval = 5 * time.Second
+	// claimSize deliveries are attempted at once, and are left alone by
+	// other workers for claimLease, which must cover the request timeout.
+	claimSize  = 10
+	claimLease = time.Minute
+
+	requestTimeout = 10 * time.Second
+	// maxDrainedBody bytes of a response are read so that the connection
+	// can be reused.
+	maxDrainedBody = 4096
+
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = 6 * time.Hour
+)
+
+// client only connects to public addresses, and neither follows redirects
+// nor uses a proxy, so that webhooks cannot reach internal services.
+var client = &http.Client{
+	Timeout: requestTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: requestTimeout,
+			Control: dialPublic,
+		}).DialContext,
+		TLSHandshakeTimeout: requestTimeout,
+		MaxIdleConnsPerHost: claimSize,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// Signature returns the value of the X-Webhook-Signature header: the
+// hex-encoded HMAC-SHA256, keyed with the webhook's secret, of the
+// timestamp from X-Webhook-Timestamp, a dot and the body. Receivers should
+// recompute it and reject old timestamps to prevent replays.
+func Signature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Enqueue queues an event of the outbox as a delivery to every webhook of
+// its workspace subscribed to its type, and wakes up the worker. Webhooks
+// created after the event do not get it. It is meant to be subscribed to
+// the outbox for outbox.ContactEvents.
+func (usecase *webhookUsecaseImpl) Enqueue(ctx context.Context, event outbox.Event) error {
+	webhooks, err := usecase.webhookRepository.FindSubscribed(ctx, event.Username, event.OrganizationID, event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []entities.WebhookDelivery
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !slices.Contains(webhook.EventTypes, event.Type) && !slices.Contains(webhook.EventTypes, models.EventTypeAll) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = toPayload(event); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, entities.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        entities.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := usecase.webhookRepository.Enqueue(ctx, deliveries); err != nil {
+		return err
+	}
+
+	select {
+	case usecase.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// toPayload encodes the body of the deliveries of an event: the event in
+// the shape of the sync event stream, with the state the contact or address
+// was written with unless it was deleted.
+func toPayload(event outbox.Event) ([]byte, error) {
+	payload := syncModels.Event{ID: event.ID, Type: event.Type}
+	switch event.Type {
+	case outbox.ContactCreated, outbox.ContactUpdated, outbox.ContactDeleted:
+		var contact contactEntities.Contact
+		if err := json.Unmarshal([]byte(event.Payload), &contact); err != nil {
+			return nil, err
+		}
+		payload.EntityID, payload.ContactID = contact.ID, contact.ID
+		if event.Type != outbox.ContactDeleted {
+			response := contactUsecase.ToContactResponse(contact.Username, contact)
+			payload.Contact = &response
+		}
+	default:
+		var address addressEntities.Address
+		if err := json.Unmarshal([]byte(event.Payload), &address); err != nil {
+			return nil, err
+		}
+		payload.EntityID, payload.ContactID = address.ID, address.ContactID
+		if event.Type != outbox.AddressDeleted {
+			response := addressUsecase.ToAddressResponse(address)
+			payload.Address = &response
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// Run attempts the due deliveries until ctx is done. It wakes up when
+// events are queued and regularly for retries. Errors are passed to
+// onError and retried on the next round.
+func (usecase *webhookUsecaseImpl) Run(ctx context.Context, onError func(err error)) {
+	ticker := time.NewTicker(workerInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := usecase.deliver(ctx); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-usecase.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver attempts the due deliveries until there are none left.
+func (usecase *webhookUsecaseImpl) deliver(ctx context.Context) error {
+	for {
+		deliveries, webhooks, err := usecase.webhookRepository.ClaimDue(ctx, claimSize, claimLease)
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		errs := make([]error, len(deliveries))
+		var wg sync.WaitGroup
+		for i, delivery := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = usecase.attempt(ctx, webhooks[delivery.WebhookID], delivery)
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// attempt posts a delivery to its webhook and records the outcome. Any 2xx
+// response counts as success; otherwise the delivery is retried with
+// exponential backoff until it has been attempted MaxAttempts times.
+func (usecase *webhookUsecaseImpl) attempt(ctx context.Context, webhook entities.Webhook, delivery entities.WebhookDelivery) error {
+	started := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &started
+
+	attempt := entities.WebhookAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+	}
+
+	statusCode, err := post(ctx, webhook, delivery, started)
+	attempt.DurationMS = int(time.Since(started).Milliseconds())
+	if err != nil {
+		message := err.Error()
+		if len(message) > 1000 {
+			message = message[:1000]
+		}
+		attempt.Error = &message
+	} else {
+		attempt.StatusCode = &statusCode
+	}
+
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		delivery.Status = entities.DeliverySucceeded
+	case delivery.Attempts >= models.MaxAttempts:
+		delivery.Status = entities.DeliveryFailed
+	default:
+		delivery.NextAttemptAt = started.Add(retryDelay(delivery.Attempts))
+	}
+
+	return usecase.webhookRepository.RecordAttempt(ctx, delivery, attempt)
+}
+
+// post sends a delivery and returns the response's status code. The body
+// of the response is discarded rather than logged, so that it cannot be
+// read back through the delivery log.
+func post(ctx context.Context, webhook entities.Webhook, delivery entities.WebhookDelivery, now time.Time) (int, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "golang-contact-management-webhooks")
+	request.Header.Set("X-Webhook-ID", strconv.Itoa(webhook.ID))
+	request.Header.Set("X-Webhook-Delivery", strconv.Itoa(delivery.ID))
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", Signature(webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainedBody))
+	return response.StatusCode, nil
+}
+
+// retryDelay doubles the wait after each failed attempt, up to a limit.
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}